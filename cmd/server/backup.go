@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/bketelsen/docko/internal/backup"
+	"github.com/bketelsen/docko/internal/config"
+	"github.com/bketelsen/docko/internal/database"
+	"github.com/bketelsen/docko/internal/document"
+	"github.com/bketelsen/docko/internal/queue"
+)
+
+// newBackupService connects to the database and storage for a backup command
+func newBackupService(ctx context.Context, cfg *config.Config) (*backup.Service, func(), error) {
+	db, err := database.New(ctx, cfg.DatabaseURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("connect to database: %w", err)
+	}
+	store, err := newStorage(ctx, cfg.Storage)
+	if err != nil {
+		db.Close()
+		return nil, nil, fmt.Errorf("initialize storage: %w", err)
+	}
+	docSvc := document.New(db, store, queue.New(db, queue.DefaultConfig()))
+	return backup.New(db, docSvc, store), db.Close, nil
+}
+
+// runBackup writes a backup bundle of every document and its files
+func runBackup(ctx context.Context, cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("backup", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: docko backup <file.tar.gz>")
+		fmt.Fprintln(flags.Output(), `Writes the bundle to standard output if the file is "-".`)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("expected one file")
+	}
+	dest := flags.Arg(0)
+
+	svc, closeDB, err := newBackupService(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	if dest == "-" {
+		_, err := svc.Export(ctx, os.Stdout)
+		return err
+	}
+
+	// Write next to the destination and rename so a failed backup never
+	// replaces a good one
+	tmp := dest + ".partial"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("create backup file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp) }()
+
+	result, err := svc.Export(ctx, f)
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("write backup file: %w", err)
+	}
+	if err := os.Rename(tmp, dest); err != nil {
+		return fmt.Errorf("write backup file: %w", err)
+	}
+
+	fmt.Printf("backed up %d documents and %d files (%d bytes) to %s\n",
+		result.Manifest.Rows("documents"), result.Files, result.Bytes, dest)
+	return nil
+}
+
+// runRestore loads a backup bundle into an empty instance
+func runRestore(ctx context.Context, cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("restore", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: docko restore <file.tar.gz>")
+		fmt.Fprintln(flags.Output(), `Reads the bundle from standard input if the file is "-".`)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("expected one file")
+	}
+
+	var r io.Reader = os.Stdin
+	if src := flags.Arg(0); src != "-" {
+		f, err := os.Open(src)
+		if err != nil {
+			return fmt.Errorf("open backup file: %w", err)
+		}
+		defer func() { _ = f.Close() }()
+		r = f
+	}
+
+	svc, closeDB, err := newBackupService(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	result, err := svc.Restore(ctx, r)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("restored %d documents, %d tags, %d correspondents and %d files from a backup taken %s\n",
+		result.Manifest.Rows("documents"), result.Manifest.Rows("tags"), result.Manifest.Rows("correspondents"),
+		result.Files, result.Manifest.CreatedAt.Local().Format("2006-01-02 15:04"))
+	return nil
+}
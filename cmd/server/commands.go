@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/bketelsen/docko/internal/config"
+	"github.com/bketelsen/docko/internal/storage"
+)
+
+// runCommand runs a command-line subcommand and returns the exit code
+// Without a subcommand the binary runs the web server.
+func runCommand(name string, args []string) int {
+	commands := map[string]func(context.Context, *config.Config, []string) error{
+		"backup":           runBackup,
+		"encrypt-storage":  runEncryptStorage,
+		"import":           runImport,
+		"import-paperless": runImportPaperless,
+		"restore":          runRestore,
+	}
+
+	run, ok := commands[name]
+	switch {
+	case name == "help" || name == "-h" || name == "--help":
+		printUsage()
+		return 0
+	case !ok:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+		printUsage()
+		return 2
+	}
+
+	err := run(context.Background(), config.Load(), args)
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+		return 1
+	}
+	return 0
+}
+
+func printUsage() {
+	fmt.Fprintln(os.Stderr, `Usage: docko [command]
+
+Without a command, docko runs the web server.
+
+Commands:
+  backup            Write a backup bundle of all documents, metadata and files
+  encrypt-storage   Encrypt files stored before STORAGE_ENCRYPTION_KEY was set
+  import            Import every supported file under a directory tree
+  import-paperless  Import a Paperless-ngx document exporter dump
+  restore           Restore a backup bundle into an empty instance
+
+Run "docko <command> -h" for a command's flags.`)
+}
+
+// runEncryptStorage encrypts existing plaintext files in place
+// Already-encrypted files are skipped, so it can be re-run after an interruption.
+func runEncryptStorage(ctx context.Context, cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("encrypt-storage", flag.ContinueOnError)
+	verbose := fs.Bool("v", false, "print each file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if cfg.Storage.EncryptionKey == "" {
+		return fmt.Errorf("STORAGE_ENCRYPTION_KEY is not set")
+	}
+
+	backend, err := newBackend(ctx, cfg.Storage)
+	if err != nil {
+		return fmt.Errorf("initialize storage: %w", err)
+	}
+	enc := storage.NewEncryptedBackend(backend, cfg.Storage.EncryptionKey)
+
+	result, err := enc.EncryptExisting(ctx, func(key string, encrypted bool) {
+		if *verbose && encrypted {
+			fmt.Println("encrypted", key)
+		}
+	})
+	fmt.Printf("%d files encrypted, %d already encrypted\n", result.Encrypted, result.Skipped)
+	return err
+}
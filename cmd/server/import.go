@@ -0,0 +1,221 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/bketelsen/docko/internal/config"
+	"github.com/bketelsen/docko/internal/database"
+	"github.com/bketelsen/docko/internal/document"
+	"github.com/bketelsen/docko/internal/queue"
+	"github.com/bketelsen/docko/internal/storage"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// stringList is a flag that can be given more than once
+type stringList []string
+
+func (l *stringList) String() string { return strings.Join(*l, ",") }
+
+func (l *stringList) Set(v string) error {
+	if v = strings.TrimSpace(v); v != "" {
+		*l = append(*l, v)
+	}
+	return nil
+}
+
+// importResult is the outcome of importing one file
+type importResult struct {
+	path      string
+	duplicate bool
+	err       error
+}
+
+// importSummary counts the outcomes of an import
+type importSummary struct {
+	imported   int
+	duplicates int
+	skipped    int // Unsupported file types
+	failed     int
+}
+
+// runImport ingests every supported file under a directory tree
+// Files are queued for processing like inbox imports; the server's queue
+// workers process them once it is running.
+func runImport(ctx context.Context, cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: docko import [flags] <directory>")
+		flags.PrintDefaults()
+	}
+	dryRun := flags.Bool("dry-run", false, "report what would be imported without importing")
+	var tags stringList
+	flags.Var(&tags, "tag", "tag every document (repeatable)")
+	correspondent := flags.String("correspondent", "", "assign every document to this correspondent")
+	folderTags := flags.Bool("folder-tags", false, "tag documents with the names of the folders they are in, below the directory")
+	workers := flags.Int("workers", 4, "number of files to import at once")
+	verbose := flags.Bool("v", false, "print each file")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("expected one directory")
+	}
+	root, err := filepath.Abs(flags.Arg(0))
+	if err != nil {
+		return fmt.Errorf("resolve directory: %w", err)
+	}
+	if info, err := os.Stat(root); err != nil || !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", root)
+	}
+	*workers = max(*workers, 1)
+
+	// Stop queuing files on Ctrl-C; files already being imported finish
+	walkCtx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	db, err := database.New(ctx, cfg.DatabaseURL)
+	if err != nil {
+		return fmt.Errorf("connect to database: %w", err)
+	}
+	defer db.Close()
+
+	store, err := newStorage(ctx, cfg.Storage)
+	if err != nil {
+		return fmt.Errorf("initialize storage: %w", err)
+	}
+	docSvc := document.New(db, store, queue.New(db, queue.DefaultConfig()))
+
+	var summary importSummary
+	files := make(chan string)
+	results := make(chan importResult)
+
+	go func() {
+		defer close(files)
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				results <- importResult{path: path, err: err}
+				return nil
+			}
+			if strings.HasPrefix(d.Name(), ".") && path != root {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if d.IsDir() || !d.Type().IsRegular() {
+				return nil
+			}
+			if !document.IsSupportedFilename(path) {
+				results <- importResult{path: path, err: document.ErrUnsupportedType}
+				return nil
+			}
+			select {
+			case files <- path:
+				return nil
+			case <-walkCtx.Done():
+				return walkCtx.Err()
+			}
+		})
+		if err != nil && !errors.Is(err, context.Canceled) {
+			results <- importResult{path: root, err: err}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for range *workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range files {
+				opts := importOptions(root, path, tags, *correspondent, *folderTags)
+				results <- importFile(ctx, docSvc, store, path, opts, *dryRun)
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	for r := range results {
+		rel, _ := filepath.Rel(root, r.path)
+		switch {
+		case errors.Is(r.err, document.ErrUnsupportedType):
+			summary.skipped++
+			if *verbose {
+				fmt.Println("skipped  ", rel)
+			}
+		case r.err != nil:
+			summary.failed++
+			fmt.Fprintf(os.Stderr, "failed    %s: %v\n", rel, r.err)
+		case r.duplicate:
+			summary.duplicates++
+			if *verbose {
+				fmt.Println("duplicate", rel)
+			}
+		default:
+			summary.imported++
+			if *verbose {
+				fmt.Println("imported ", rel)
+			}
+		}
+	}
+
+	verb := "imported"
+	if *dryRun {
+		verb = "would be imported"
+	}
+	fmt.Printf("%d files %s, %d duplicates, %d unsupported, %d errors\n",
+		summary.imported, verb, summary.duplicates, summary.skipped, summary.failed)
+	if walkCtx.Err() != nil {
+		return fmt.Errorf("interrupted")
+	}
+	if summary.failed > 0 {
+		return fmt.Errorf("%d files failed to import", summary.failed)
+	}
+	return nil
+}
+
+// importOptions returns the metadata to ingest the file at path under root with
+// Email attachments inherit it from their message.
+func importOptions(root, path string, tags []string, correspondent string, folderTags bool) document.IngestOptions {
+	opts := document.IngestOptions{
+		CorrespondentName: correspondent,
+		TagNames:          tags,
+	}
+	if folderTags {
+		opts.TagNames = append(slices.Clone(tags), document.FolderTags(root, path)...)
+	}
+	return opts
+}
+
+// importFile ingests one file, or in a dry run checks whether it is a duplicate
+func importFile(ctx context.Context, docSvc *document.Service, store *storage.Storage, path string, opts document.IngestOptions, dryRun bool) importResult {
+	if dryRun {
+		hash, err := store.HashFile(path)
+		if err != nil {
+			return importResult{path: path, err: err}
+		}
+		_, err = docSvc.GetByHash(ctx, hash)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return importResult{path: path, err: err}
+		}
+		return importResult{path: path, duplicate: err == nil}
+	}
+
+	_, duplicate, err := docSvc.IngestWithOptions(ctx, path, filepath.Base(path), opts)
+	return importResult{path: path, duplicate: duplicate, err: err}
+}
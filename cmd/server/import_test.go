@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/bketelsen/docko/internal/document"
+	"github.com/bketelsen/docko/internal/queue"
+	"github.com/bketelsen/docko/internal/storage"
+	"github.com/bketelsen/docko/internal/testutil"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestImportOptions(t *testing.T) {
+	root := filepath.FromSlash("/backlog")
+	path := filepath.FromSlash("/backlog/utilities/2024/bill.eml")
+
+	opts := importOptions(root, path, []string{"imported"}, "City Power", false)
+	if !slices.Equal(opts.TagNames, []string{"imported"}) || opts.CorrespondentName != "City Power" {
+		t.Errorf("importOptions() = %+v", opts)
+	}
+
+	tags := []string{"imported"}
+	opts = importOptions(root, path, tags, "", true)
+	if want := []string{"imported", "utilities", "2024"}; !slices.Equal(opts.TagNames, want) {
+		t.Errorf("importOptions() tags = %q, want %q", opts.TagNames, want)
+	}
+	if len(tags) != 1 {
+		t.Errorf("importOptions() changed the --tag list to %q", tags)
+	}
+}
+
+// emailWithPDF returns a message with one PDF attachment, unique per call so
+// it isn't taken for a duplicate of an earlier run
+func emailWithPDF() []byte {
+	id := uuid.NewString()
+	pdf := base64.StdEncoding.EncodeToString([]byte("%PDF-1.4\n% " + id + "\n"))
+	return []byte("From: Billing <billing@example.com>\r\n" +
+		"To: me@example.com\r\n" +
+		"Subject: Invoice " + id + "\r\n" +
+		"Date: Mon, 02 Mar 2026 10:00:00 +0000\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: multipart/mixed; boundary=\"b1\"\r\n" +
+		"\r\n" +
+		"--b1\r\n" +
+		"Content-Type: text/plain\r\n" +
+		"\r\n" +
+		"Invoice attached.\r\n" +
+		"--b1\r\n" +
+		"Content-Type: application/pdf; name=\"invoice.pdf\"\r\n" +
+		"Content-Disposition: attachment; filename=\"invoice.pdf\"\r\n" +
+		"Content-Transfer-Encoding: base64\r\n" +
+		"\r\n" +
+		pdf + "\r\n" +
+		"--b1--\r\n")
+}
+
+func TestImportFile_EmailAttachments(t *testing.T) {
+	db := testutil.NewTestDB(t)
+	ctx := context.Background()
+
+	store, err := storage.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	docSvc := document.New(db, store, queue.New(db, queue.DefaultConfig()))
+
+	root := t.TempDir()
+	path := filepath.Join(root, "receipts", "invoice.eml")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, emailWithPDF(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := importOptions(root, path, []string{"imported"}, "", true)
+	r := importFile(ctx, docSvc, store, path, opts, false)
+	if r.err != nil || r.duplicate {
+		t.Fatalf("importFile() = %+v", r)
+	}
+
+	msg, err := db.Queries.GetDocumentByHash(ctx, mustHash(t, store, path))
+	if err != nil {
+		t.Fatalf("get message document: %v", err)
+	}
+	children, err := db.Queries.ListChildDocuments(ctx, pgtype.UUID{Bytes: msg.ID, Valid: true})
+	if err != nil {
+		t.Fatalf("list attachments: %v", err)
+	}
+	if len(children) != 1 {
+		t.Fatalf("got %d attachment documents, want 1", len(children))
+	}
+
+	tags, err := db.Queries.GetDocumentTags(ctx, children[0].ID)
+	if err != nil {
+		t.Fatalf("get attachment tags: %v", err)
+	}
+	var names []string
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	if want := []string{"imported", "receipts"}; !slices.Equal(names, want) {
+		t.Errorf("attachment tags = %q, want %q", names, want)
+	}
+}
+
+func mustHash(t *testing.T, store *storage.Storage, path string) string {
+	t.Helper()
+	hash, err := store.HashFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hash
+}
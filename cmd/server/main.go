@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"os"
 	"os/signal"
@@ -10,9 +11,11 @@ import (
 
 	"github.com/bketelsen/docko/internal/ai"
 	"github.com/bketelsen/docko/internal/auth"
+	"github.com/bketelsen/docko/internal/backup"
 	"github.com/bketelsen/docko/internal/config"
 	"github.com/bketelsen/docko/internal/database"
 	"github.com/bketelsen/docko/internal/document"
+	"github.com/bketelsen/docko/internal/export"
 	"github.com/bketelsen/docko/internal/handler"
 	"github.com/bketelsen/docko/internal/inbox"
 	"github.com/bketelsen/docko/internal/middleware"
@@ -25,9 +28,14 @@ import (
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		os.Exit(runCommand(os.Args[1], os.Args[2:]))
+	}
+
 	cfg := config.Load()
 
 	ctx := context.Background()
+
 	db, err := database.New(ctx, cfg.DatabaseURL)
 	if err != nil {
 		slog.Error("failed to connect to database", "error", err)
@@ -43,12 +51,12 @@ func main() {
 	}
 
 	// Initialize storage
-	store, err := storage.New(cfg.Storage.Path)
+	store, err := newStorage(ctx, cfg.Storage)
 	if err != nil {
 		slog.Error("failed to initialize storage", "error", err)
 		os.Exit(1)
 	}
-	slog.Info("storage initialized", "path", cfg.Storage.Path)
+	slog.Info("storage initialized", "backend", cfg.Storage.Backend, "path", cfg.Storage.Path, "encrypted", cfg.Storage.EncryptionKey != "")
 
 	// Initialize queue
 	q := queue.New(db, queue.DefaultConfig())
@@ -67,13 +75,23 @@ func main() {
 		slog.Warn("processing dependencies missing", "error", err)
 		// Don't fatal - app can run, just processing will fail
 	}
+	if !processing.OfficeConversionAvailable() {
+		slog.Warn("soffice not found, office documents will fail conversion")
+	}
+	if cfg.Processing.SplitBarcode != "" && !processing.SeparatorDetectionAvailable() {
+		slog.Warn("zbarimg not found, barcode separator pages will not be detected")
+	}
 
 	// Initialize status broadcaster for SSE updates
 	broadcaster := processing.NewStatusBroadcaster()
 
 	// Initialize processor and register with queue
-	processor := processing.New(db, docService, store, "static/images/placeholder.webp", broadcaster)
+	splitter := processing.NewSeparatorDetector(cfg.Processing.SplitBarcode, cfg.Processing.SplitOnBlankPages)
+	passwords := processing.NewPasswords(db, networkSvc.GetCrypto())
+	processor := processing.New(db, docService, store, "static/images/placeholder.webp", broadcaster, splitter, passwords)
 	q.RegisterHandler(document.JobTypeProcess, processor.HandleJob)
+	q.RegisterHandler(document.JobTypeConvert, processor.HandleConvertJob)
+	q.RegisterHandler(document.JobTypeThumbnail, processor.HandleThumbnailJob)
 
 	// Initialize AI service and processor
 	aiSvc := ai.NewService(db)
@@ -96,13 +114,56 @@ func main() {
 		}
 	}()
 
+	// Start background purge of expired trash
+	if cfg.Storage.TrashRetentionDays > 0 {
+		retention := time.Duration(cfg.Storage.TrashRetentionDays) * 24 * time.Hour
+		go func() {
+			ticker := time.NewTicker(1 * time.Hour)
+			defer ticker.Stop()
+			for range ticker.C {
+				purged, err := docService.PurgeExpiredTrash(context.Background(), retention)
+				if err != nil {
+					slog.Warn("failed to purge expired trash", "error", err)
+				} else if purged > 0 {
+					slog.Info("purged expired trash", "documents", purged)
+				}
+			}
+		}()
+	}
+
+	// Start scheduled storage integrity checks
+	if cfg.Storage.IntegrityCheckHours > 0 {
+		go func() {
+			ticker := time.NewTicker(time.Duration(cfg.Storage.IntegrityCheckHours) * time.Hour)
+			defer ticker.Stop()
+			for range ticker.C {
+				if _, err := docService.CheckIntegrity(context.Background()); err != nil {
+					slog.Warn("failed to check storage integrity", "error", err)
+				}
+			}
+		}()
+	}
+
+	// Start export mirror sync
+	exportCtx, exportCancel := context.WithCancel(context.Background())
+	defer exportCancel()
+	if cfg.Export.Path != "" {
+		exporter, err := export.New(db, docService, cfg.Export)
+		if err != nil {
+			slog.Error("failed to initialize export mirror", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("export mirror enabled", "path", cfg.Export.Path, "template", cfg.Export.Template, "mode", cfg.Export.Mode)
+		go exporter.Start(exportCtx, time.Duration(cfg.Export.IntervalSeconds)*time.Second)
+	}
+
 	e := echo.New()
 	e.HideBanner = true
 	e.HidePort = true
 
 	middleware.Setup(e, cfg)
 
-	h := handler.New(cfg, db, authService, docService, inboxSvc, networkSvc, aiSvc, q, broadcaster)
+	h := handler.New(cfg, db, authService, docService, inboxSvc, networkSvc, aiSvc, backup.New(db, docService, store), passwords, q, broadcaster)
 	h.RegisterRoutes(e)
 
 	// Start inbox watcher in background
@@ -150,6 +211,9 @@ func main() {
 		slog.Error("failed to stop inbox watcher", "error", err)
 	}
 
+	// Stop export mirror sync
+	exportCancel()
+
 	// Stop network service
 	slog.Info("stopping network service...")
 	networkCancel()
@@ -164,3 +228,41 @@ func main() {
 
 	slog.Info("server stopped")
 }
+
+// newStorage creates the document storage for the configured backend,
+// encrypting files when a storage encryption key is set
+func newStorage(ctx context.Context, cfg config.StorageConfig) (*storage.Storage, error) {
+	backend, err := newBackend(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.EncryptionKey != "" {
+		backend = storage.NewEncryptedBackend(backend, cfg.EncryptionKey)
+	}
+	// STORAGE_PATH also holds local scratch files such as the OCR volumes
+	return storage.NewWithBackend(backend, cfg.Path)
+}
+
+// newBackend creates the configured storage backend
+func newBackend(ctx context.Context, cfg config.StorageConfig) (storage.Backend, error) {
+	switch cfg.Backend {
+	case "local":
+		backend := storage.NewLocalBackend(cfg.Path)
+		if err := backend.EnsureDirectories(); err != nil {
+			return nil, fmt.Errorf("failed to create directories: %w", err)
+		}
+		return backend, nil
+	case "s3":
+		return storage.NewS3Backend(ctx, storage.S3Config{
+			Endpoint:  cfg.S3.Endpoint,
+			Bucket:    cfg.S3.Bucket,
+			Region:    cfg.S3.Region,
+			AccessKey: cfg.S3.AccessKey,
+			SecretKey: cfg.S3.SecretKey,
+			Prefix:    cfg.S3.Prefix,
+			UseSSL:    cfg.S3.UseSSL,
+			PathStyle: cfg.S3.PathStyle,
+		})
+	}
+	return nil, fmt.Errorf("unknown storage backend %q (want local or s3)", cfg.Backend)
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+
+	"github.com/bketelsen/docko/internal/config"
+	"github.com/bketelsen/docko/internal/database"
+	"github.com/bketelsen/docko/internal/document"
+	"github.com/bketelsen/docko/internal/paperless"
+	"github.com/bketelsen/docko/internal/queue"
+)
+
+// runImportPaperless imports a Paperless-ngx document exporter dump
+// Like import, documents are queued for processing by the server's workers.
+func runImportPaperless(ctx context.Context, cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("import-paperless", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: docko import-paperless [flags] <export-directory>")
+		flags.PrintDefaults()
+	}
+	dryRun := flags.Bool("dry-run", false, "report what would be imported without importing")
+	verbose := flags.Bool("v", false, "print each document")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("expected one export directory")
+	}
+
+	exp, err := paperless.Load(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	// Stop after the current document on Ctrl-C
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	db, err := database.New(ctx, cfg.DatabaseURL)
+	if err != nil {
+		return fmt.Errorf("connect to database: %w", err)
+	}
+	defer db.Close()
+
+	store, err := newStorage(ctx, cfg.Storage)
+	if err != nil {
+		return fmt.Errorf("initialize storage: %w", err)
+	}
+	docSvc := document.New(db, store, queue.New(db, queue.DefaultConfig()))
+
+	report, err := paperless.Import(ctx, db, docSvc, exp, paperless.Options{
+		DryRun: *dryRun,
+		Progress: func(d *paperless.Document, duplicate bool, err error) {
+			switch {
+			case err != nil:
+				fmt.Fprintf(os.Stderr, "failed    %d %s: %v\n", d.PK, d.Title, err)
+			case !*verbose:
+			case duplicate:
+				fmt.Printf("duplicate %d %s\n", d.PK, d.Title)
+			default:
+				fmt.Printf("imported  %d %s\n", d.PK, d.Title)
+			}
+		},
+	})
+
+	verb := "imported"
+	if *dryRun {
+		verb = "would be imported"
+	}
+	fmt.Printf("%d documents %s, %d duplicates, %d errors\n", report.Imported, verb, report.Duplicates, report.Failed)
+	if len(report.Unmapped) > 0 {
+		fmt.Println("Not imported:")
+		for _, what := range slices.Sorted(maps.Keys(report.Unmapped)) {
+			fmt.Printf("  %5d %s\n", report.Unmapped[what], what)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if report.Failed > 0 {
+		return fmt.Errorf("%d documents failed to import", report.Failed)
+	}
+	return nil
+}
@@ -22,6 +22,7 @@ type AnalyzeRequest struct {
 	TextContent    string   // First N pages of extracted text
 	ExistingTags   []string // User's current tag taxonomy
 	Correspondents []string // User's current correspondents
+	DocumentTypes  []string // User's current document types
 	MaxTokens      int      // Max response tokens (default 1024)
 }
 
@@ -33,7 +34,7 @@ type AnalyzeResponse struct {
 
 // Suggestion represents a single AI recommendation
 type Suggestion struct {
-	Type       string  // "tag" or "correspondent"
+	Type       string  // "tag", "correspondent" or "document_type"
 	Value      string  // The suggested name
 	Confidence float64 // 0.0 to 1.0
 	Reasoning  string  // Brief explanation
@@ -52,6 +53,7 @@ type Usage struct {
 type AIResponse struct {
 	Tags          []TagSuggestion          `json:"tags" jsonschema_description:"Array of suggested tags for the document"`
 	Correspondent *CorrespondentSuggestion `json:"correspondent" jsonschema:"nullable" jsonschema_description:"Suggested correspondent (sender/organization), or null if unclear"`
+	DocumentType  *DocumentTypeSuggestion  `json:"document_type" jsonschema:"nullable" jsonschema_description:"Suggested document type (invoice, contract, ...), or null if unclear"`
 }
 
 // TagSuggestion is the JSON schema for a tag suggestion
@@ -68,13 +70,20 @@ type CorrespondentSuggestion struct {
 	Reasoning  string  `json:"reasoning" jsonschema_description:"Brief explanation for this suggestion"`
 }
 
+// DocumentTypeSuggestion is the JSON schema for a document type suggestion
+type DocumentTypeSuggestion struct {
+	Name       string  `json:"name" jsonschema_description:"The document type name"`
+	Confidence float64 `json:"confidence" jsonschema_description:"Confidence score from 0.0 to 1.0"`
+	Reasoning  string  `json:"reasoning" jsonschema_description:"Brief explanation for this suggestion"`
+}
+
 // ConvertToSuggestions converts AIResponse to []Suggestion
-func ConvertToSuggestions(resp AIResponse, existingTags, existingCorrespondents []string) []Suggestion {
+func ConvertToSuggestions(resp AIResponse, req AnalyzeRequest) []Suggestion {
 	var suggestions []Suggestion
 
 	// Convert tag suggestions
 	tagSet := make(map[string]bool)
-	for _, t := range existingTags {
+	for _, t := range req.ExistingTags {
 		tagSet[t] = true
 	}
 	for _, t := range resp.Tags {
@@ -90,7 +99,7 @@ func ConvertToSuggestions(resp AIResponse, existingTags, existingCorrespondents
 	// Convert correspondent suggestion
 	if resp.Correspondent != nil {
 		corrSet := make(map[string]bool)
-		for _, c := range existingCorrespondents {
+		for _, c := range req.Correspondents {
 			corrSet[c] = true
 		}
 		suggestions = append(suggestions, Suggestion{
@@ -102,5 +111,20 @@ func ConvertToSuggestions(resp AIResponse, existingTags, existingCorrespondents
 		})
 	}
 
+	// Convert document type suggestion
+	if resp.DocumentType != nil {
+		typeSet := make(map[string]bool)
+		for _, t := range req.DocumentTypes {
+			typeSet[t] = true
+		}
+		suggestions = append(suggestions, Suggestion{
+			Type:       "document_type",
+			Value:      resp.DocumentType.Name,
+			Confidence: resp.DocumentType.Confidence,
+			Reasoning:  resp.DocumentType.Reasoning,
+			IsNew:      !typeSet[resp.DocumentType.Name],
+		})
+	}
+
 	return suggestions
 }
@@ -80,7 +80,7 @@ func (p *AnthropicProvider) Analyze(ctx context.Context, req AnalyzeRequest) (*A
 	}
 
 	return &AnalyzeResponse{
-		Suggestions: ConvertToSuggestions(aiResp, req.ExistingTags, req.Correspondents),
+		Suggestions: ConvertToSuggestions(aiResp, req),
 		Usage: Usage{
 			InputTokens:  int(message.Usage.InputTokens),
 			OutputTokens: int(message.Usage.OutputTokens),
@@ -75,7 +75,7 @@ func (p *OllamaProvider) Analyze(ctx context.Context, req AnalyzeRequest) (*Anal
 	}
 
 	return &AnalyzeResponse{
-		Suggestions: ConvertToSuggestions(aiResp, req.ExistingTags, req.Correspondents),
+		Suggestions: ConvertToSuggestions(aiResp, req),
 		Usage: Usage{
 			InputTokens:  promptEvalCount,
 			OutputTokens: evalCount,
@@ -40,8 +40,19 @@ var aiResponseSchema = map[string]any{
 			"required":             []string{"name", "confidence", "reasoning"},
 			"additionalProperties": false,
 		},
+		"document_type": map[string]any{
+			"type":        []string{"object", "null"},
+			"description": "Suggested document type (invoice, contract, ...), or null if unclear",
+			"properties": map[string]any{
+				"name":       map[string]any{"type": "string", "description": "The document type name"},
+				"confidence": map[string]any{"type": "number", "description": "Confidence score from 0.0 to 1.0"},
+				"reasoning":  map[string]any{"type": "string", "description": "Brief explanation for this suggestion"},
+			},
+			"required":             []string{"name", "confidence", "reasoning"},
+			"additionalProperties": false,
+		},
 	},
-	"required":             []string{"tags", "correspondent"},
+	"required":             []string{"tags", "correspondent", "document_type"},
 	"additionalProperties": false,
 }
 
@@ -84,7 +95,7 @@ func (p *OpenAIProvider) Analyze(ctx context.Context, req AnalyzeRequest) (*Anal
 
 	schemaParam := openai.ResponseFormatJSONSchemaJSONSchemaParam{
 		Name:        "document_analysis",
-		Description: openai.String("AI analysis of document with tag and correspondent suggestions"),
+		Description: openai.String("AI analysis of document with tag, correspondent and document type suggestions"),
 		Schema:      aiResponseSchema,
 		Strict:      openai.Bool(true),
 	}
@@ -116,7 +127,7 @@ func (p *OpenAIProvider) Analyze(ctx context.Context, req AnalyzeRequest) (*Anal
 	}
 
 	return &AnalyzeResponse{
-		Suggestions: ConvertToSuggestions(aiResp, req.ExistingTags, req.Correspondents),
+		Suggestions: ConvertToSuggestions(aiResp, req),
 		Usage: Usage{
 			InputTokens:  int(chatResp.Usage.PromptTokens),
 			OutputTokens: int(chatResp.Usage.CompletionTokens),
@@ -6,16 +6,19 @@ import (
 
 // SystemPrompt is the system instruction for AI analysis
 const SystemPrompt = `You are a document analysis assistant. Analyze the provided document text and suggest:
-1. Tags that categorize this document (e.g., invoice, receipt, contract, medical, insurance, tax, bank)
+1. Tags that describe what this document is about (e.g., medical, insurance, tax, bank, utilities, car)
 2. The correspondent (sender/recipient organization or person)
+3. The document type, i.e. what kind of document it is (e.g., Invoice, Statement, Contract, Letter, Receipt)
 
 IMPORTANT:
-- Prefer existing tags/correspondents when they match
+- Prefer existing tags/correspondents/document types when they match
 - Only suggest new ones if no existing option fits well
+- Don't suggest document types as tags
 - Assign confidence scores (0.0-1.0) based on how certain you are
 - Provide brief reasoning for each suggestion
 - Suggest 1-5 tags maximum, focusing on the most relevant
 - Suggest exactly one correspondent (or omit if unclear)
+- Suggest exactly one document type (or omit if unclear)
 
 Your response must be valid JSON matching the schema.`
 
@@ -45,6 +48,17 @@ func BuildPrompt(req AnalyzeRequest) string {
 		b.WriteString("(none yet)\n")
 	}
 
+	b.WriteString("\n## Existing Document Types\n")
+	if len(req.DocumentTypes) > 0 {
+		for _, t := range req.DocumentTypes {
+			b.WriteString("- ")
+			b.WriteString(t)
+			b.WriteString("\n")
+		}
+	} else {
+		b.WriteString("(none yet)\n")
+	}
+
 	b.WriteString("\n## Document Text (first pages)\n")
 	b.WriteString(req.TextContent)
 
@@ -76,6 +90,15 @@ var JSONSchema = map[string]any{
 			},
 			"required": []string{"name", "confidence", "reasoning"},
 		},
+		"document_type": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"name":       map[string]any{"type": "string"},
+				"confidence": map[string]any{"type": "number"},
+				"reasoning":  map[string]any{"type": "string"},
+			},
+			"required": []string{"name", "confidence", "reasoning"},
+		},
 	},
 	"required": []string{"tags"},
 }
@@ -2,11 +2,13 @@ package ai
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 
 	"github.com/bketelsen/docko/internal/database"
@@ -90,6 +92,11 @@ func (s *Service) AnalyzeDocument(ctx context.Context, docID uuid.UUID, jobID *u
 		return nil, fmt.Errorf("get existing correspondents: %w", err)
 	}
 
+	existingDocumentTypes, err := s.getExistingDocumentTypes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get existing document types: %w", err)
+	}
+
 	// Truncate text to first N pages worth (roughly 3000 chars per page)
 	maxPages := int(settings.MaxPages)
 	maxChars := maxPages * 3000
@@ -104,6 +111,7 @@ func (s *Service) AnalyzeDocument(ctx context.Context, docID uuid.UUID, jobID *u
 		TextContent:    textContent,
 		ExistingTags:   existingTags,
 		Correspondents: existingCorrespondents,
+		DocumentTypes:  existingDocumentTypes,
 	}
 
 	// Get preferred provider or use fallback
@@ -258,15 +266,10 @@ func (s *Service) autoApplySuggestion(ctx context.Context, docID uuid.UUID, jobI
 	qtx := s.db.Queries.WithTx(tx)
 
 	// Create the suggestion record
-	suggestionType := sqlc.SuggestionTypeTag
-	if suggestion.Type == "correspondent" {
-		suggestionType = sqlc.SuggestionTypeCorrespondent
-	}
-
 	created, err := qtx.CreateAISuggestion(ctx, sqlc.CreateAISuggestionParams{
 		DocumentID:     docID,
 		JobID:          jobID,
-		SuggestionType: suggestionType,
+		SuggestionType: sqlc.SuggestionType(suggestion.Type),
 		Value:          suggestion.Value,
 		Confidence:     float64ToNumeric(suggestion.Confidence),
 		Reasoning:      &suggestion.Reasoning,
@@ -285,6 +288,8 @@ func (s *Service) autoApplySuggestion(ctx context.Context, docID uuid.UUID, jobI
 		err = s.applyTagSuggestion(ctx, qtx, docID, suggestion)
 	case "correspondent":
 		err = s.applyCorrespondentSuggestion(ctx, qtx, docID, suggestion)
+	case "document_type":
+		err = s.applyDocumentTypeSuggestion(ctx, qtx, docID, suggestion)
 	}
 
 	if err != nil {
@@ -306,15 +311,10 @@ func (s *Service) autoApplySuggestion(ctx context.Context, docID uuid.UUID, jobI
 
 // storePendingSuggestion stores a suggestion for manual review
 func (s *Service) storePendingSuggestion(ctx context.Context, docID uuid.UUID, jobID pgtype.UUID, suggestion Suggestion) error {
-	suggestionType := sqlc.SuggestionTypeTag
-	if suggestion.Type == "correspondent" {
-		suggestionType = sqlc.SuggestionTypeCorrespondent
-	}
-
 	_, err := s.db.Queries.CreateAISuggestion(ctx, sqlc.CreateAISuggestionParams{
 		DocumentID:     docID,
 		JobID:          jobID,
-		SuggestionType: suggestionType,
+		SuggestionType: sqlc.SuggestionType(suggestion.Type),
 		Value:          suggestion.Value,
 		Confidence:     float64ToNumeric(suggestion.Confidence),
 		Reasoning:      &suggestion.Reasoning,
@@ -416,6 +416,47 @@ func (s *Service) applyCorrespondentSuggestion(ctx context.Context, qtx *sqlc.Qu
 	return nil
 }
 
+// applyDocumentTypeSuggestion creates or finds the document type and sets it on the document
+func (s *Service) applyDocumentTypeSuggestion(ctx context.Context, qtx *sqlc.Queries, docID uuid.UUID, suggestion Suggestion) error {
+	var typeID uuid.UUID
+	existing, err := qtx.GetDocumentTypeByName(ctx, suggestion.Value)
+	switch {
+	case err == nil:
+		typeID = existing.ID
+	case errors.Is(err, pgx.ErrNoRows):
+		created, err := qtx.CreateDocumentType(ctx, suggestion.Value)
+		if err != nil {
+			return fmt.Errorf("create document type: %w", err)
+		}
+		typeID = created.ID
+		slog.Info("created new document type from AI suggestion", "document_type_id", typeID, "name", suggestion.Value)
+	default:
+		return fmt.Errorf("get document type: %w", err)
+	}
+
+	err = qtx.SetDocumentType(ctx, sqlc.SetDocumentTypeParams{
+		ID:             docID,
+		DocumentTypeID: pgtype.UUID{Bytes: typeID, Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("set document type: %w", err)
+	}
+
+	return nil
+}
+
+// applyDateSuggestion sets a detected document date confirmed by the user
+func (s *Service) applyDateSuggestion(ctx context.Context, qtx *sqlc.Queries, docID uuid.UUID, suggestion Suggestion) error {
+	date, err := time.Parse("2006-01-02", suggestion.Value)
+	if err != nil {
+		return fmt.Errorf("parse date: %w", err)
+	}
+	if err := qtx.SetDocumentDate(ctx, sqlc.SetDocumentDateParams{ID: docID, DocumentDate: date}); err != nil {
+		return fmt.Errorf("set document date: %w", err)
+	}
+	return nil
+}
+
 // getExistingTags returns all tag names for context
 func (s *Service) getExistingTags(ctx context.Context) ([]string, error) {
 	tags, err := s.db.Queries.ListTagsWithCounts(ctx)
@@ -444,6 +485,20 @@ func (s *Service) getExistingCorrespondents(ctx context.Context) ([]string, erro
 	return names, nil
 }
 
+// getExistingDocumentTypes returns all document type names for context
+func (s *Service) getExistingDocumentTypes(ctx context.Context) ([]string, error) {
+	types, err := s.db.Queries.ListDocumentTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(types))
+	for i, t := range types {
+		names[i] = t.Name
+	}
+	return names, nil
+}
+
 // AnyProviderAvailable returns true if at least one AI provider is available
 func (s *Service) AnyProviderAvailable() bool {
 	for _, p := range s.providers {
@@ -509,6 +564,10 @@ func (s *Service) ApplySuggestionManual(ctx context.Context, docID uuid.UUID, su
 		err = s.applyTagSuggestion(ctx, qtx, docID, suggestion)
 	case "correspondent":
 		err = s.applyCorrespondentSuggestion(ctx, qtx, docID, suggestion)
+	case "document_type":
+		err = s.applyDocumentTypeSuggestion(ctx, qtx, docID, suggestion)
+	case "date":
+		err = s.applyDateSuggestion(ctx, qtx, docID, suggestion)
 	}
 
 	if err != nil {
@@ -0,0 +1,528 @@
+// Package backup writes and restores self-describing archive bundles
+// A bundle is a gzipped tar holding a JSON manifest of the database rows
+// and every stored file, so an archive can be moved to a new instance
+// without pg_dump or copying the storage directory by hand.
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/bketelsen/docko/internal/database"
+	"github.com/bketelsen/docko/internal/database/sqlc"
+	"github.com/bketelsen/docko/internal/document"
+	"github.com/bketelsen/docko/internal/storage"
+)
+
+// Format is the bundle format version written by Export
+const Format = 1
+
+// Bundle entries. The manifest comes first so a restore can reject a bundle
+// before writing any files; checksums come last because they are computed
+// while the files are streamed.
+const (
+	manifestEntry  = "manifest.json"
+	checksumsEntry = "checksums.json"
+	filesDir       = "files/"
+)
+
+// table is a database table carried in a bundle
+type table struct {
+	name    string
+	replace bool     // Seeded by migrations; restored rows replace the defaults
+	omit    []string // Columns that don't survive a move, restored as NULL
+}
+
+// tables lists the bundled tables in an order that satisfies foreign keys.
+// Instance-specific tables (users, sessions, inboxes, network sources, PDF
+// passwords, jobs) are left out; they are configured on the new instance.
+var tables = []table{
+	{name: "tags"},
+	{name: "correspondents"},
+	{name: "document_types", replace: true},
+	{name: "custom_fields"},
+	{name: "documents"},
+	{name: "document_revisions"},
+	{name: "document_tags"},
+	{name: "document_correspondents"},
+	{name: "document_custom_fields"},
+	{name: "document_events"},
+	{name: "ai_suggestions", omit: []string{"job_id"}},
+	{name: "duplicate_candidates"},
+	{name: "ai_settings", replace: true},
+}
+
+// ErrNotEmpty is returned when restoring into an instance that already has documents
+var ErrNotEmpty = errors.New("instance is not empty")
+
+// Manifest describes a bundle and holds its database rows
+type Manifest struct {
+	Format        int                        `json:"format"`
+	SchemaVersion int64                      `json:"schema_version"`
+	CreatedAt     time.Time                  `json:"created_at"`
+	Tables        map[string]json.RawMessage `json:"tables"` // JSON array of rows per table
+}
+
+// Rows returns the number of rows bundled for a table
+func (m *Manifest) Rows(name string) int {
+	var rows []json.RawMessage
+	_ = json.Unmarshal(m.Tables[name], &rows)
+	return len(rows)
+}
+
+// File is a stored file in a bundle
+type File struct {
+	Key    string `json:"key"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Result summarizes an export or restore
+type Result struct {
+	Manifest *Manifest
+	Files    int
+	Bytes    int64
+}
+
+// Service exports and restores bundles
+type Service struct {
+	db      *database.DB
+	docSvc  *document.Service
+	storage *storage.Storage
+}
+
+// New creates a backup Service
+func New(db *database.DB, docSvc *document.Service, store *storage.Storage) *Service {
+	return &Service{db: db, docSvc: docSvc, storage: store}
+}
+
+// Export writes a bundle of the whole archive to w
+// The rows are read in a single snapshot; only files that belong to a
+// document in it are bundled. Export fails if an original or archive file
+// is missing or doesn't match its recorded hash, since the bundle couldn't
+// be restored; run an integrity check to find the damaged documents.
+func (s *Service) Export(ctx context.Context, w io.Writer) (*Result, error) {
+	manifest, docIDs, err := s.snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	if err := writeJSON(tw, manifestEntry, manifest); err != nil {
+		return nil, err
+	}
+
+	result := &Result{Manifest: manifest}
+	var files []File
+	for _, cat := range storage.Categories {
+		keys, err := s.storage.List(ctx, cat+"/")
+		if err != nil {
+			return nil, fmt.Errorf("list %s files: %w", cat, err)
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			if id, ok := document.IDFromKey(key); !ok || !docIDs[id] {
+				continue // Orphaned, or added after the snapshot
+			}
+			f, err := s.exportFile(ctx, tw, key)
+			if err != nil {
+				return nil, fmt.Errorf("export %s: %w", key, err)
+			}
+			files = append(files, f)
+			result.Files++
+			result.Bytes += f.Size
+		}
+	}
+
+	if err := s.verify(manifest, files, files); err != nil {
+		return nil, err
+	}
+	if err := writeJSON(tw, checksumsEntry, files); err != nil {
+		return nil, err
+	}
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("close archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("close archive: %w", err)
+	}
+	return result, nil
+}
+
+// snapshot reads the bundled tables and the set of document IDs in one transaction
+func (s *Service) snapshot(ctx context.Context) (*Manifest, map[uuid.UUID]bool, error) {
+	version, err := s.db.SchemaVersion(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tx, err := s.db.Pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	manifest := &Manifest{
+		Format:        Format,
+		SchemaVersion: version,
+		CreatedAt:     time.Now().UTC(),
+		Tables:        make(map[string]json.RawMessage, len(tables)),
+	}
+	for _, t := range tables {
+		// Generated columns are rebuilt on restore
+		generated, err := columns(ctx, tx, t.name, false)
+		if err != nil {
+			return nil, nil, err
+		}
+		drop := append(append([]string{}, generated...), t.omit...)
+		var rows json.RawMessage
+		query := fmt.Sprintf(`SELECT COALESCE(jsonb_agg(to_jsonb(t) - $1::text[]), '[]') FROM %s t`, pgx.Identifier{t.name}.Sanitize())
+		if err := tx.QueryRow(ctx, query, drop).Scan(&rows); err != nil {
+			return nil, nil, fmt.Errorf("export %s: %w", t.name, err)
+		}
+		manifest.Tables[t.name] = rows
+	}
+
+	ids, err := sqlc.New(tx).ListAllDocuments(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("list documents: %w", err)
+	}
+	docIDs := make(map[uuid.UUID]bool, len(ids))
+	for _, doc := range ids {
+		docIDs[doc.ID] = true
+	}
+	return manifest, docIDs, nil
+}
+
+// exportFile streams a stored file into the archive, hashing it on the way
+func (s *Service) exportFile(ctx context.Context, tw *tar.Writer, key string) (File, error) {
+	obj, info, err := s.storage.Open(ctx, key)
+	if err != nil {
+		return File{}, err
+	}
+	defer func() { _ = obj.Close() }()
+
+	err = tw.WriteHeader(&tar.Header{
+		Name:    filesDir + key,
+		Mode:    0644,
+		Size:    info.Size,
+		ModTime: info.ModTime,
+	})
+	if err != nil {
+		return File{}, err
+	}
+	hash := sha256.New()
+	if _, err := io.Copy(tw, io.TeeReader(obj, hash)); err != nil {
+		return File{}, err
+	}
+	return File{Key: key, Size: info.Size, SHA256: fmt.Sprintf("%x", hash.Sum(nil))}, nil
+}
+
+// Restore loads a bundle into an empty instance
+// Files are written to storage as they are read and checked against the
+// bundle's checksums and the documents' recorded hashes; the rows are only
+// committed once everything matches. On failure the written files are removed.
+func (s *Service) Restore(ctx context.Context, r io.Reader) (*Result, error) {
+	version, err := s.db.SchemaVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkEmpty(ctx, s.db.Pool); err != nil {
+		return nil, err
+	}
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("read archive: %w", err)
+	}
+	tr := tar.NewReader(gz)
+
+	manifest, err := readManifest(tr, version)
+	if err != nil {
+		return nil, err
+	}
+
+	var written []string
+	committed := false
+	defer func() {
+		if committed {
+			return
+		}
+		for _, key := range written {
+			if err := s.storage.Delete(context.Background(), key); err != nil {
+				slog.Warn("failed to remove restored file", "key", key, "error", err)
+			}
+		}
+	}()
+
+	result := &Result{Manifest: manifest}
+	var got, want []File
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read archive: %w", err)
+		}
+
+		switch key, isFile := strings.CutPrefix(hdr.Name, filesDir); {
+		case isFile:
+			if !validKey(key) {
+				return nil, fmt.Errorf("invalid file name %q in bundle", hdr.Name)
+			}
+			written = append(written, key)
+			hash := sha256.New()
+			if err := s.storage.Put(ctx, key, io.TeeReader(tr, hash), hdr.Size); err != nil {
+				return nil, fmt.Errorf("restore %s: %w", key, err)
+			}
+			got = append(got, File{Key: key, Size: hdr.Size, SHA256: fmt.Sprintf("%x", hash.Sum(nil))})
+			result.Files++
+			result.Bytes += hdr.Size
+		case hdr.Name == checksumsEntry:
+			if err := json.NewDecoder(tr).Decode(&want); err != nil {
+				return nil, fmt.Errorf("read %s: %w", checksumsEntry, err)
+			}
+		default:
+			return nil, fmt.Errorf("unexpected entry %q in bundle", hdr.Name)
+		}
+	}
+	if want == nil {
+		return nil, fmt.Errorf("bundle has no %s; it is incomplete", checksumsEntry)
+	}
+
+	if err := s.verify(manifest, want, got); err != nil {
+		return nil, err
+	}
+	if err := s.restoreRows(ctx, manifest); err != nil {
+		return nil, err
+	}
+	committed = true
+	return result, nil
+}
+
+// readManifest reads the first bundle entry and checks it can be restored
+func readManifest(tr *tar.Reader, schemaVersion int64) (*Manifest, error) {
+	hdr, err := tr.Next()
+	if err != nil {
+		return nil, fmt.Errorf("read archive: %w", err)
+	}
+	if hdr.Name != manifestEntry {
+		return nil, fmt.Errorf("not a docko backup: first entry is %q", hdr.Name)
+	}
+
+	var m Manifest
+	if err := json.NewDecoder(tr).Decode(&m); err != nil {
+		return nil, fmt.Errorf("read %s: %w", manifestEntry, err)
+	}
+	if m.Format != Format {
+		return nil, fmt.Errorf("unsupported backup format %d", m.Format)
+	}
+	if m.SchemaVersion != schemaVersion {
+		return nil, fmt.Errorf("backup is from schema version %d but this instance is at %d; restore with the same docko version", m.SchemaVersion, schemaVersion)
+	}
+	return &m, nil
+}
+
+// restoreRows inserts the bundled rows in one transaction
+func (s *Service) restoreRows(ctx context.Context, m *Manifest) error {
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// Another restore or an upload may have landed while files were copied
+	if err := checkEmpty(ctx, tx); err != nil {
+		return err
+	}
+
+	for _, t := range tables {
+		rows, ok := m.Tables[t.name]
+		if !ok {
+			continue
+		}
+		name := pgx.Identifier{t.name}.Sanitize()
+		if t.replace {
+			if _, err := tx.Exec(ctx, "DELETE FROM "+name); err != nil {
+				return fmt.Errorf("clear %s: %w", t.name, err)
+			}
+		}
+
+		cols, err := columns(ctx, tx, t.name, true)
+		if err != nil {
+			return err
+		}
+		list := make([]string, len(cols))
+		for i, col := range cols {
+			list[i] = pgx.Identifier{col}.Sanitize()
+		}
+		// Rows come back as the table's own record type, so every column is
+		// converted by Postgres and IDs are kept as they were
+		query := fmt.Sprintf(`INSERT INTO %[1]s (%[2]s) SELECT %[2]s FROM json_populate_recordset(NULL::%[1]s, $1)`,
+			name, strings.Join(list, ", "))
+		if _, err := tx.Exec(ctx, query, string(rows)); err != nil {
+			return fmt.Errorf("restore %s: %w", t.name, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// querier is satisfied by both the pool and a transaction
+type querier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+// checkEmpty returns ErrNotEmpty if the instance has documents or metadata
+// that restored rows could collide with
+func checkEmpty(ctx context.Context, q querier) error {
+	var found bool
+	err := q.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM documents)
+		OR EXISTS (SELECT 1 FROM tags)
+		OR EXISTS (SELECT 1 FROM correspondents)
+		OR EXISTS (SELECT 1 FROM custom_fields)`).Scan(&found)
+	if err != nil {
+		return fmt.Errorf("check for existing documents: %w", err)
+	}
+	if found {
+		return fmt.Errorf("%w: restore needs a fresh instance without documents, tags, correspondents or custom fields", ErrNotEmpty)
+	}
+	return nil
+}
+
+// columns returns a table's stored columns, or its generated ones if stored is false
+func columns(ctx context.Context, q querier, name string, stored bool) ([]string, error) {
+	rows, err := q.Query(ctx, `SELECT column_name FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = $1 AND (is_generated = 'NEVER') = $2
+		ORDER BY ordinal_position`, name, stored)
+	if err != nil {
+		return nil, fmt.Errorf("list %s columns: %w", name, err)
+	}
+	cols, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("list %s columns: %w", name, err)
+	}
+	return cols, nil
+}
+
+// validKey reports whether a bundled file name is a storage key in a known category
+func validKey(key string) bool {
+	category, _, _ := strings.Cut(key, "/")
+	return path.Clean(key) == key && !strings.HasPrefix(key, "/") && slices.Contains(storage.Categories, category)
+}
+
+// verify checks the files read from a bundle against its checksums and the
+// bundled documents' recorded hashes
+func (s *Service) verify(m *Manifest, want, got []File) error {
+	wantFiles := make(map[string]File, len(want))
+	for _, f := range want {
+		wantFiles[f.Key] = f
+	}
+	for _, g := range got {
+		f, ok := wantFiles[g.Key]
+		switch {
+		case !ok:
+			return fmt.Errorf("%s has no checksum in the bundle", g.Key)
+		case g.Size != f.Size || g.SHA256 != f.SHA256:
+			return fmt.Errorf("%s does not match its checksum", g.Key)
+		}
+		delete(wantFiles, g.Key)
+	}
+	for _, f := range want {
+		if _, ok := wantFiles[f.Key]; ok {
+			return fmt.Errorf("%s is missing from the bundle", f.Key)
+		}
+	}
+
+	expected, err := s.expectedHashes(m)
+	if err != nil {
+		return err
+	}
+	hashes := make(map[string]string, len(want))
+	for _, f := range want {
+		hashes[f.Key] = f.SHA256
+	}
+	for key, hash := range expected {
+		stored, ok := hashes[key]
+		switch {
+		case !ok:
+			return fmt.Errorf("%s is missing; run an integrity check on the source instance", key)
+		case stored != hash:
+			return fmt.Errorf("%s does not match the hash recorded for its document", key)
+		}
+	}
+	return nil
+}
+
+// expectedHashes returns the recorded hash of every original, earlier
+// revision and archive version in the manifest, by storage key
+func (s *Service) expectedHashes(m *Manifest) (map[string]string, error) {
+	var docs []struct {
+		ID               uuid.UUID `json:"id"`
+		OriginalFilename string    `json:"original_filename"`
+		ContentHash      string    `json:"content_hash"`
+		ArchiveChecksum  *string   `json:"archive_checksum"`
+	}
+	if err := json.Unmarshal(m.Tables["documents"], &docs); err != nil {
+		return nil, fmt.Errorf("read documents: %w", err)
+	}
+	var revisions []sqlc.DocumentRevision
+	if err := json.Unmarshal(m.Tables["document_revisions"], &revisions); err != nil {
+		return nil, fmt.Errorf("read document revisions: %w", err)
+	}
+
+	expected := make(map[string]string, len(docs)+len(revisions))
+	for _, d := range docs {
+		doc := &sqlc.Document{ID: d.ID, OriginalFilename: d.OriginalFilename}
+		expected[s.docSvc.OriginalKey(doc)] = d.ContentHash
+		if d.ArchiveChecksum != nil {
+			expected[s.docSvc.ArchiveKey(doc)] = *d.ArchiveChecksum
+		}
+	}
+	for i := range revisions {
+		expected[s.docSvc.RevisionOriginalKey(&revisions[i])] = revisions[i].ContentHash
+	}
+	return expected, nil
+}
+
+// writeJSON adds a JSON document to the archive
+func writeJSON(tw *tar.Writer, name string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encode %s: %w", name, err)
+	}
+	err = tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	return nil
+}
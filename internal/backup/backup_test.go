@@ -0,0 +1,74 @@
+package backup
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/bketelsen/docko/internal/document"
+)
+
+func TestVerify(t *testing.T) {
+	s := &Service{docSvc: &document.Service{}}
+	m := &Manifest{Tables: map[string]json.RawMessage{
+		"documents": json.RawMessage(`[{"id": "0b6f2a3e-8f3c-4a51-9d7e-2f1c0a9b8e7d", "original_filename": "bill.pdf",
+			"content_hash": "aaa", "archive_checksum": "bbb"}]`),
+		"document_revisions": json.RawMessage(`[{"document_id": "0b6f2a3e-8f3c-4a51-9d7e-2f1c0a9b8e7d", "revision": 1,
+			"original_filename": "scan.png", "content_hash": "ccc", "created_at": "2024-03-15T10:00:00.123456+00:00"}]`),
+	}}
+	files := []File{
+		{Key: "originals/0b/6f/0b6f2a3e-8f3c-4a51-9d7e-2f1c0a9b8e7d.pdf", Size: 3, SHA256: "aaa"},
+		{Key: "originals/0b/6f/0b6f2a3e-8f3c-4a51-9d7e-2f1c0a9b8e7d.v1.png", Size: 3, SHA256: "ccc"},
+		{Key: "archive/0b/6f/0b6f2a3e-8f3c-4a51-9d7e-2f1c0a9b8e7d.pdf", Size: 3, SHA256: "bbb"},
+		{Key: "thumbnails/0b/6f/0b6f2a3e-8f3c-4a51-9d7e-2f1c0a9b8e7d.webp", Size: 3, SHA256: "ddd"},
+	}
+	changed := func(i int, f File) []File {
+		out := append([]File{}, files...)
+		out[i] = f
+		return out
+	}
+
+	tests := []struct {
+		name      string
+		want, got []File
+		err       string
+	}{
+		{"complete", files, files, ""},
+		{"file missing", files, files[:3], "missing from the bundle"},
+		{"file changed", files, changed(3, File{Key: files[3].Key, Size: 3, SHA256: "eee"}), "does not match its checksum"},
+		{"extra file", files[:3], files, "has no checksum"},
+		{"original missing", files[1:], files[1:], "is missing; run an integrity check"},
+		{"original changed", changed(0, File{Key: files[0].Key, Size: 3, SHA256: "fff"}),
+			changed(0, File{Key: files[0].Key, Size: 3, SHA256: "fff"}), "does not match the hash recorded"},
+	}
+
+	for _, tt := range tests {
+		err := s.verify(m, tt.want, tt.got)
+		switch {
+		case tt.err == "" && err != nil:
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+		case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+			t.Errorf("%s: error = %v, want %q", tt.name, err, tt.err)
+		}
+	}
+}
+
+func TestValidKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{"originals/0b/6f/0b6f2a3e-8f3c-4a51-9d7e-2f1c0a9b8e7d.pdf", true},
+		{"thumbnails/0b/6f/0b6f2a3e-8f3c-4a51-9d7e-2f1c0a9b8e7d.webp", true},
+		{"originals/../../etc/passwd", false},
+		{"/originals/x.pdf", false},
+		{"config/x.pdf", false},
+		{"originals//x.pdf", false},
+	}
+
+	for _, tt := range tests {
+		if got := validKey(tt.key); got != tt.want {
+			t.Errorf("validKey(%q) = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+}
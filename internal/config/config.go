@@ -21,7 +21,23 @@ type AuthConfig struct {
 }
 
 type StorageConfig struct {
-	Path string // Root path for document storage
+	Path                string // Root path for document storage (scratch space when Backend is "s3")
+	Backend             string // "local" (default) or "s3"
+	EncryptionKey       string // Master key for encrypting stored files (empty disables)
+	TrashRetentionDays  int    // Days before trashed documents are purged (0 disables auto-empty)
+	IntegrityCheckHours int    // Hours between storage integrity checks (0 disables)
+	S3                  S3Config
+}
+
+type S3Config struct {
+	Endpoint  string // host[:port] of the S3-compatible service
+	Bucket    string
+	Region    string
+	AccessKey string
+	SecretKey string
+	Prefix    string // Optional key prefix within the bucket
+	UseSSL    bool   // Use HTTPS (default: true)
+	PathStyle bool   // Path-style bucket addressing, needed by most self-hosted services
 }
 
 type InboxConfig struct {
@@ -31,6 +47,18 @@ type InboxConfig struct {
 	ScanIntervalMs int    // Interval between directory scans in ms (default: 1000)
 }
 
+type ProcessingConfig struct {
+	SplitBarcode      string // Barcode/QR value on separator sheets that split batch scans (empty disables)
+	SplitOnBlankPages bool   // Split batch scans on blank pages
+}
+
+type ExportConfig struct {
+	Path            string // Mirror directory for the human-readable export tree (empty disables)
+	Template        string // Path template for exported files, relative to Path
+	Mode            string // "hardlink" (copies when linking fails) or "copy"
+	IntervalSeconds int    // Seconds between mirror syncs (default: 60)
+}
+
 type NetworkConfig struct {
 	CredentialKey string // Key for encrypting network source credentials (required for network sources)
 }
@@ -43,6 +71,8 @@ type Config struct {
 	Auth        AuthConfig
 	Storage     StorageConfig
 	Inbox       InboxConfig
+	Processing  ProcessingConfig
+	Export      ExportConfig
 	Network     NetworkConfig
 }
 
@@ -62,7 +92,21 @@ func Load() *Config {
 			SessionMaxAge: getEnvIntOrDefault("SESSION_MAX_AGE", 24),
 		},
 		Storage: StorageConfig{
-			Path: getEnvOrDefault("STORAGE_PATH", "./storage"),
+			Path:                getEnvOrDefault("STORAGE_PATH", "./storage"),
+			Backend:             getEnvOrDefault("STORAGE_BACKEND", "local"),
+			EncryptionKey:       os.Getenv("STORAGE_ENCRYPTION_KEY"),
+			TrashRetentionDays:  getEnvIntOrDefault("TRASH_RETENTION_DAYS", 30),
+			IntegrityCheckHours: getEnvIntOrDefault("INTEGRITY_CHECK_INTERVAL_HOURS", 168),
+			S3: S3Config{
+				Endpoint:  os.Getenv("S3_ENDPOINT"),
+				Bucket:    os.Getenv("S3_BUCKET"),
+				Region:    os.Getenv("S3_REGION"),
+				AccessKey: os.Getenv("S3_ACCESS_KEY_ID"),
+				SecretKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+				Prefix:    os.Getenv("S3_PREFIX"),
+				UseSSL:    getEnvBoolOrDefault("S3_USE_SSL", true),
+				PathStyle: getEnvBoolOrDefault("S3_PATH_STYLE", false),
+			},
 		},
 		Inbox: InboxConfig{
 			DefaultPath:    os.Getenv("INBOX_PATH"), // Empty string if not set
@@ -70,6 +114,16 @@ func Load() *Config {
 			MaxFileSizeMB:  getEnvIntOrDefault("INBOX_MAX_FILE_SIZE_MB", 100),
 			ScanIntervalMs: getEnvIntOrDefault("INBOX_SCAN_INTERVAL_MS", 1000),
 		},
+		Processing: ProcessingConfig{
+			SplitBarcode:      os.Getenv("SPLIT_BARCODE"),
+			SplitOnBlankPages: getEnvBoolOrDefault("SPLIT_ON_BLANK_PAGES", false),
+		},
+		Export: ExportConfig{
+			Path:            os.Getenv("EXPORT_PATH"), // Empty string if not set
+			Template:        getEnvOrDefault("EXPORT_TEMPLATE", "{correspondent}/{year}/{document_date} {title}.pdf"),
+			Mode:            getEnvOrDefault("EXPORT_MODE", "hardlink"),
+			IntervalSeconds: getEnvIntOrDefault("EXPORT_INTERVAL_SECONDS", 60),
+		},
 		Network: NetworkConfig{
 			CredentialKey: os.Getenv("CREDENTIAL_ENCRYPTION_KEY"),
 		},
@@ -119,6 +173,15 @@ func getEnvIntOrDefault(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvBoolOrDefault(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return defaultValue
+}
+
 func generateDefaultSecret() string {
 	b := make([]byte, 32)
 	_, _ = rand.Read(b)
@@ -62,3 +62,15 @@ func (db *DB) migrate() error {
 
 	return nil
 }
+
+// SchemaVersion returns the version of the latest applied migration
+func (db *DB) SchemaVersion(ctx context.Context) (int64, error) {
+	conn := stdlib.OpenDBFromPool(db.Pool)
+	defer func() { _ = conn.Close() }()
+
+	version, err := goose.GetDBVersionContext(ctx, conn)
+	if err != nil {
+		return 0, fmt.Errorf("get schema version: %w", err)
+	}
+	return version, nil
+}
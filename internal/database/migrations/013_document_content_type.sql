@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE documents ADD COLUMN content_type VARCHAR(100) NOT NULL DEFAULT 'application/pdf';
+-- MIME type of the stored original. Non-PDF originals (e.g. images) keep a
+-- PDF rendition in the renditions storage category for viewing and processing.
+
+-- +goose Down
+ALTER TABLE documents DROP COLUMN IF EXISTS content_type;
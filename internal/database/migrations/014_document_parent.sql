@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE documents ADD COLUMN parent_document_id UUID REFERENCES documents(id) ON DELETE SET NULL;
+-- Set for documents extracted from another document (e.g. email attachments)
+
+CREATE INDEX idx_documents_parent ON documents(parent_document_id) WHERE parent_document_id IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_documents_parent;
+ALTER TABLE documents DROP COLUMN IF EXISTS parent_document_id;
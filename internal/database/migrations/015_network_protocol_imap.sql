@@ -0,0 +1,12 @@
+-- +goose NO TRANSACTION
+-- +goose Up
+ALTER TYPE network_protocol ADD VALUE IF NOT EXISTS 'imap';
+-- IMAP sources store the mailbox folder in share_path and import PDF attachments
+
+-- +goose Down
+-- Postgres can't drop enum values; recreate the type without 'imap'
+DELETE FROM network_sources WHERE protocol = 'imap';
+ALTER TYPE network_protocol RENAME TO network_protocol_old;
+CREATE TYPE network_protocol AS ENUM ('smb', 'nfs');
+ALTER TABLE network_sources ALTER COLUMN protocol TYPE network_protocol USING protocol::text::network_protocol;
+DROP TYPE network_protocol_old;
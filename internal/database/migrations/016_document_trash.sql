@@ -0,0 +1,10 @@
+-- +goose Up
+ALTER TABLE documents ADD COLUMN deleted_at TIMESTAMPTZ;
+-- Set when a document is moved to the trash; trashed documents are hidden from
+-- search and purged (row and files) after the configured retention period
+
+CREATE INDEX idx_documents_deleted_at ON documents(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_documents_deleted_at;
+ALTER TABLE documents DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,21 @@
+-- +goose Up
+ALTER TABLE documents ADD COLUMN revision INT NOT NULL DEFAULT 1;
+-- Revision number of the current original; bumped each time the file is replaced
+
+-- Document revisions table: prior originals of documents whose file was replaced.
+-- The archived original is stored as {uuid}.v{revision}{ext} in originals.
+CREATE TABLE document_revisions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    document_id UUID NOT NULL REFERENCES documents(id) ON DELETE CASCADE,
+    revision INT NOT NULL,
+    original_filename VARCHAR(255) NOT NULL,
+    content_hash VARCHAR(64) NOT NULL,
+    file_size BIGINT NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT document_revisions_unique UNIQUE (document_id, revision)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS document_revisions;
+ALTER TABLE documents DROP COLUMN IF EXISTS revision;
@@ -0,0 +1,31 @@
+-- +goose Up
+-- Similarity fingerprints computed during processing: a simhash of the
+-- extracted text and a difference hash of the first page image
+ALTER TABLE documents ADD COLUMN text_simhash BIGINT;
+ALTER TABLE documents ADD COLUMN image_hash BIGINT;
+
+-- Duplicate status enum for the near-duplicate review workflow
+CREATE TYPE duplicate_status AS ENUM ('pending', 'merged', 'distinct');
+
+-- Duplicate candidates table: pairs of documents with similar fingerprints.
+-- document_id is the newly processed document, duplicate_of_id the existing one.
+CREATE TABLE duplicate_candidates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    document_id UUID NOT NULL REFERENCES documents(id) ON DELETE CASCADE,
+    duplicate_of_id UUID NOT NULL REFERENCES documents(id) ON DELETE CASCADE,
+    text_distance INT,
+    image_distance INT,
+    status duplicate_status NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    resolved_at TIMESTAMPTZ,
+    CONSTRAINT duplicate_candidates_unique UNIQUE (document_id, duplicate_of_id)
+);
+
+CREATE INDEX idx_duplicate_candidates_status ON duplicate_candidates (status) WHERE status = 'pending';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_duplicate_candidates_status;
+DROP TABLE IF EXISTS duplicate_candidates;
+DROP TYPE IF EXISTS duplicate_status;
+ALTER TABLE documents DROP COLUMN IF EXISTS image_hash;
+ALTER TABLE documents DROP COLUMN IF EXISTS text_simhash;
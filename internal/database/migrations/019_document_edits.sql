@@ -0,0 +1,9 @@
+-- +goose Up
+-- Page edit version of the current revision; 0 means unedited. Each page
+-- operation (rotate, delete, reorder, split, merge) writes a new PDF as
+-- {uuid}.v{revision}.e{edit_version}.pdf in renditions, leaving the original
+-- and earlier edits untouched.
+ALTER TABLE documents ADD COLUMN edit_version INT NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE documents DROP COLUMN IF EXISTS edit_version;
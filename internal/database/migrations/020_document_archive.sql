@@ -0,0 +1,7 @@
+-- +goose Up
+-- SHA256 of the searchable PDF/A kept in the archive category when a
+-- document was OCR'd; NULL when there is no archive version
+ALTER TABLE documents ADD COLUMN archive_checksum VARCHAR(64);
+
+-- +goose Down
+ALTER TABLE documents DROP COLUMN IF EXISTS archive_checksum;
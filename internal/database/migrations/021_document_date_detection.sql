@@ -0,0 +1,19 @@
+-- +goose NO TRANSACTION
+-- +goose Up
+ALTER TYPE suggestion_type ADD VALUE IF NOT EXISTS 'date';
+-- Date suggestions hold a detected document date (YYYY-MM-DD) that was too
+-- uncertain to apply automatically
+
+-- Confidence of document_date: NULL for the ingest-time default, 1.00 when it
+-- came from the source (e.g. an email's Date header) or a person, otherwise
+-- the confidence of the date detected in the text
+ALTER TABLE documents ADD COLUMN date_confidence DECIMAL(3,2);
+
+-- +goose Down
+ALTER TABLE documents DROP COLUMN IF EXISTS date_confidence;
+-- Postgres can't drop enum values; recreate the type without 'date'
+DELETE FROM ai_suggestions WHERE suggestion_type = 'date';
+ALTER TYPE suggestion_type RENAME TO suggestion_type_old;
+CREATE TYPE suggestion_type AS ENUM ('tag', 'correspondent');
+ALTER TABLE ai_suggestions ALTER COLUMN suggestion_type TYPE suggestion_type USING suggestion_type::text::suggestion_type;
+DROP TYPE suggestion_type_old;
@@ -0,0 +1,34 @@
+-- +goose Up
+-- User-editable title (pre-filled from pdf_title) and free-form notes
+ALTER TABLE documents ADD COLUMN title TEXT;
+ALTER TABLE documents ADD COLUMN notes TEXT;
+
+-- Rebuild the search vector to include title and notes
+DROP INDEX IF EXISTS idx_documents_search;
+ALTER TABLE documents DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE documents
+    ADD COLUMN search_vector tsvector
+    GENERATED ALWAYS AS (
+        to_tsvector('english',
+            coalesce(title, '') || ' ' ||
+            coalesce(original_filename, '') || ' ' ||
+            coalesce(notes, '') || ' ' ||
+            coalesce(text_content, '')
+        )
+    ) STORED;
+CREATE INDEX idx_documents_search ON documents USING GIN (search_vector);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_documents_search;
+ALTER TABLE documents DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE documents
+    ADD COLUMN search_vector tsvector
+    GENERATED ALWAYS AS (
+        to_tsvector('english',
+            coalesce(original_filename, '') || ' ' ||
+            coalesce(text_content, '')
+        )
+    ) STORED;
+CREATE INDEX idx_documents_search ON documents USING GIN (search_vector);
+ALTER TABLE documents DROP COLUMN IF EXISTS notes;
+ALTER TABLE documents DROP COLUMN IF EXISTS title;
@@ -0,0 +1,34 @@
+-- +goose Up
+-- Custom field types; each stores its value in one typed column below
+CREATE TYPE custom_field_type AS ENUM ('string', 'number', 'monetary', 'date', 'boolean', 'url', 'document_link');
+
+-- Custom fields table: admin-defined fields that can be set on any document
+CREATE TABLE custom_fields (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL UNIQUE,
+    field_type custom_field_type NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Document custom field values: string and url use value_text, number and
+-- monetary value_number, date value_date, boolean value_bool and
+-- document_link value_document_id
+CREATE TABLE document_custom_fields (
+    document_id UUID NOT NULL REFERENCES documents(id) ON DELETE CASCADE,
+    field_id UUID NOT NULL REFERENCES custom_fields(id) ON DELETE CASCADE,
+    value_text TEXT,
+    value_number DECIMAL,
+    value_date DATE,
+    value_bool BOOLEAN,
+    value_document_id UUID REFERENCES documents(id) ON DELETE SET NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (document_id, field_id)
+);
+
+CREATE INDEX idx_document_custom_fields_field ON document_custom_fields (field_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_document_custom_fields_field;
+DROP TABLE IF EXISTS document_custom_fields;
+DROP TABLE IF EXISTS custom_fields;
+DROP TYPE IF EXISTS custom_field_type;
@@ -0,0 +1,29 @@
+-- +goose NO TRANSACTION
+-- +goose Up
+-- Document types table: what kind of document it is (invoice, contract, ...)
+CREATE TABLE IF NOT EXISTS document_types (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO document_types (name)
+VALUES ('Invoice'), ('Statement'), ('Contract'), ('Letter'), ('Receipt')
+ON CONFLICT (name) DO NOTHING;
+
+-- A document has at most one type
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS document_type_id UUID REFERENCES document_types(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_documents_document_type ON documents (document_type_id);
+
+ALTER TYPE suggestion_type ADD VALUE IF NOT EXISTS 'document_type';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_documents_document_type;
+ALTER TABLE documents DROP COLUMN IF EXISTS document_type_id;
+DROP TABLE IF EXISTS document_types;
+-- Postgres can't drop enum values; recreate the type without 'document_type'
+DELETE FROM ai_suggestions WHERE suggestion_type = 'document_type';
+ALTER TYPE suggestion_type RENAME TO suggestion_type_old;
+CREATE TYPE suggestion_type AS ENUM ('tag', 'correspondent', 'date');
+ALTER TABLE ai_suggestions ALTER COLUMN suggestion_type TYPE suggestion_type USING suggestion_type::text::suggestion_type;
+DROP TYPE suggestion_type_old;
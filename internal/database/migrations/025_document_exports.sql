@@ -0,0 +1,13 @@
+-- +goose Up
+-- Files written to the human-readable export mirror, one per document
+-- No foreign key: rows outlive purged documents so their files can be removed
+CREATE TABLE document_exports (
+    document_id UUID PRIMARY KEY,
+    export_path TEXT NOT NULL,
+    source_path TEXT NOT NULL,
+    source_revision INT NOT NULL,
+    exported_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS document_exports;
@@ -0,0 +1,30 @@
+-- +goose Up
+-- Storage integrity check runs; only the latest finished run's issues are kept
+CREATE TABLE integrity_checks (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    started_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMPTZ,
+    documents_checked INT NOT NULL DEFAULT 0,
+    files_checked INT NOT NULL DEFAULT 0,
+    error TEXT
+);
+
+-- Problems found by a check
+-- kind: missing, corrupt (hash mismatch), orphaned (no document), thumbnail_flag
+-- (thumbnail_generated doesn't match storage). Orphans have no document.
+CREATE TABLE integrity_issues (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    check_id UUID NOT NULL REFERENCES integrity_checks(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL,
+    category VARCHAR(20) NOT NULL,
+    storage_key TEXT NOT NULL,
+    document_id UUID REFERENCES documents(id) ON DELETE CASCADE,
+    detail TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_integrity_issues_check_id ON integrity_issues(check_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS integrity_issues;
+DROP TABLE IF EXISTS integrity_checks;
@@ -0,0 +1,19 @@
+-- +goose Up
+-- Metadata applied to every document an inbox or network source imports.
+-- Kept by name, like docko import's flags: missing tags, correspondents and
+-- document types are created when first applied.
+ALTER TABLE inboxes ADD COLUMN default_tags TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE inboxes ADD COLUMN default_correspondent VARCHAR(255);
+ALTER TABLE inboxes ADD COLUMN default_document_type VARCHAR(100);
+
+ALTER TABLE network_sources ADD COLUMN default_tags TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE network_sources ADD COLUMN default_correspondent VARCHAR(255);
+ALTER TABLE network_sources ADD COLUMN default_document_type VARCHAR(100);
+
+-- +goose Down
+ALTER TABLE network_sources DROP COLUMN IF EXISTS default_document_type;
+ALTER TABLE network_sources DROP COLUMN IF EXISTS default_correspondent;
+ALTER TABLE network_sources DROP COLUMN IF EXISTS default_tags;
+ALTER TABLE inboxes DROP COLUMN IF EXISTS default_document_type;
+ALTER TABLE inboxes DROP COLUMN IF EXISTS default_correspondent;
+ALTER TABLE inboxes DROP COLUMN IF EXISTS default_tags;
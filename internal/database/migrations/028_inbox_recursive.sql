@@ -0,0 +1,9 @@
+-- +goose Up
+-- recursive: also import from subdirectories, except the error directory
+-- folder_tags: tag documents with the subdirectories they were found in
+ALTER TABLE inboxes ADD COLUMN recursive BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE inboxes ADD COLUMN folder_tags BOOLEAN NOT NULL DEFAULT false;
+
+-- +goose Down
+ALTER TABLE inboxes DROP COLUMN IF EXISTS folder_tags;
+ALTER TABLE inboxes DROP COLUMN IF EXISTS recursive;
@@ -0,0 +1,27 @@
+-- +goose NO TRANSACTION
+-- +goose Up
+-- Passwords tried on encrypted PDFs, encrypted like network source credentials
+CREATE TABLE IF NOT EXISTS pdf_passwords (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    label VARCHAR(255) NOT NULL,
+    password_encrypted TEXT NOT NULL,
+    last_used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Encrypted PDFs that none of the passwords open wait for one from the user
+ALTER TYPE processing_status ADD VALUE IF NOT EXISTS 'needs_password';
+
+-- +goose Down
+-- Postgres can't drop enum values; recreate the type without 'needs_password'
+UPDATE documents SET processing_status = 'failed' WHERE processing_status = 'needs_password';
+DROP INDEX IF EXISTS idx_documents_processing_status;
+ALTER TABLE documents ALTER COLUMN processing_status DROP DEFAULT;
+ALTER TYPE processing_status RENAME TO processing_status_old;
+CREATE TYPE processing_status AS ENUM ('pending', 'processing', 'completed', 'failed');
+ALTER TABLE documents ALTER COLUMN processing_status TYPE processing_status USING processing_status::text::processing_status;
+ALTER TABLE documents ALTER COLUMN processing_status SET DEFAULT 'pending';
+DROP TYPE processing_status_old;
+CREATE INDEX idx_documents_processing_status ON documents (processing_status)
+    WHERE processing_status IN ('pending', 'processing');
+DROP TABLE IF EXISTS pdf_passwords;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- Edit version a revision was at when it was replaced; its latest page edit
+-- stays at {uuid}.v{revision}.e{edit_version}.pdf in renditions
+ALTER TABLE document_revisions ADD COLUMN edit_version INT NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE document_revisions DROP COLUMN IF EXISTS edit_version;
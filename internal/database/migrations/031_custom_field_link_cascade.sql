@@ -0,0 +1,16 @@
+-- +goose Up
+-- A document link field goes away with the document it links to, rather
+-- than staying behind empty and matching "is set" filters
+DELETE FROM document_custom_fields dcf
+USING custom_fields f
+WHERE f.id = dcf.field_id
+  AND f.field_type = 'document_link'
+  AND dcf.value_document_id IS NULL;
+ALTER TABLE document_custom_fields DROP CONSTRAINT document_custom_fields_value_document_id_fkey;
+ALTER TABLE document_custom_fields ADD CONSTRAINT document_custom_fields_value_document_id_fkey
+    FOREIGN KEY (value_document_id) REFERENCES documents(id) ON DELETE CASCADE;
+
+-- +goose Down
+ALTER TABLE document_custom_fields DROP CONSTRAINT document_custom_fields_value_document_id_fkey;
+ALTER TABLE document_custom_fields ADD CONSTRAINT document_custom_fields_value_document_id_fkey
+    FOREIGN KEY (value_document_id) REFERENCES documents(id) ON DELETE SET NULL;
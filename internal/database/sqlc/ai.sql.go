@@ -72,7 +72,10 @@ func (q *Queries) AutoApplySuggestion(ctx context.Context, id uuid.UUID) (AiSugg
 }
 
 const countPendingSuggestions = `-- name: CountPendingSuggestions :one
-SELECT COUNT(*) FROM ai_suggestions WHERE status = 'pending'
+SELECT COUNT(*)
+FROM ai_suggestions s
+JOIN documents d ON s.document_id = d.id
+WHERE s.status = 'pending' AND d.deleted_at IS NULL
 `
 
 func (q *Queries) CountPendingSuggestions(ctx context.Context) (int64, error) {
@@ -176,14 +179,25 @@ func (q *Queries) CreateAIUsage(ctx context.Context, arg CreateAIUsageParams) (A
 }
 
 const deleteDocumentSuggestions = `-- name: DeleteDocumentSuggestions :exec
-DELETE FROM ai_suggestions WHERE document_id = $1
+DELETE FROM ai_suggestions WHERE document_id = $1 AND suggestion_type != 'date'
 `
 
+// Date suggestions come from processing, not AI analysis, so they're kept
 func (q *Queries) DeleteDocumentSuggestions(ctx context.Context, documentID uuid.UUID) error {
 	_, err := q.db.Exec(ctx, deleteDocumentSuggestions, documentID)
 	return err
 }
 
+const deletePendingDateSuggestions = `-- name: DeletePendingDateSuggestions :exec
+DELETE FROM ai_suggestions
+WHERE document_id = $1 AND suggestion_type = 'date' AND status = 'pending'
+`
+
+func (q *Queries) DeletePendingDateSuggestions(ctx context.Context, documentID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deletePendingDateSuggestions, documentID)
+	return err
+}
+
 const getAISettings = `-- name: GetAISettings :one
 
 SELECT id, preferred_provider, max_pages, auto_process, auto_apply_threshold, review_threshold, updated_at, min_word_count FROM ai_settings WHERE id = 1
@@ -372,7 +386,7 @@ const listPendingSuggestions = `-- name: ListPendingSuggestions :many
 SELECT s.id, s.document_id, s.job_id, s.suggestion_type, s.value, s.confidence, s.reasoning, s.is_new, s.status, s.created_at, s.resolved_at, s.resolved_by, d.original_filename
 FROM ai_suggestions s
 JOIN documents d ON s.document_id = d.id
-WHERE s.status = 'pending'
+WHERE s.status = 'pending' AND d.deleted_at IS NULL
 ORDER BY s.created_at DESC
 LIMIT $1 OFFSET $2
 `
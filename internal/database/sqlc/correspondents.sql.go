@@ -110,6 +110,31 @@ func (q *Queries) GetCorrespondent(ctx context.Context, id uuid.UUID) (GetCorres
 	return i, err
 }
 
+const getCorrespondentByName = `-- name: GetCorrespondentByName :one
+SELECT id, name, notes, created_at FROM correspondents
+WHERE lower(name) = lower($1)
+LIMIT 1
+`
+
+type GetCorrespondentByNameRow struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	Notes     *string   `json:"notes"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (q *Queries) GetCorrespondentByName(ctx context.Context, lower string) (GetCorrespondentByNameRow, error) {
+	row := q.db.QueryRow(ctx, getCorrespondentByName, lower)
+	var i GetCorrespondentByNameRow
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Notes,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const getCorrespondentsNotes = `-- name: GetCorrespondentsNotes :many
 SELECT id, name, notes FROM correspondents
 WHERE id = ANY($1::uuid[]) AND notes IS NOT NULL AND notes != ''
@@ -160,9 +185,10 @@ func (q *Queries) GetDocumentCorrespondent(ctx context.Context, documentID uuid.
 }
 
 const listCorrespondentsWithCounts = `-- name: ListCorrespondentsWithCounts :many
-SELECT c.id, c.name, c.notes, c.created_at, COUNT(dc.document_id)::int AS document_count
+SELECT c.id, c.name, c.notes, c.created_at, COUNT(d.id)::int AS document_count
 FROM correspondents c
 LEFT JOIN document_correspondents dc ON dc.correspondent_id = c.id
+LEFT JOIN documents d ON d.id = dc.document_id AND d.deleted_at IS NULL
 GROUP BY c.id
 ORDER BY c.name
 `
@@ -39,6 +39,7 @@ SELECT
     COUNT(*) FILTER (WHERE processing_status = 'failed')::int AS failed,
     COUNT(*) FILTER (WHERE created_at >= CURRENT_DATE)::int AS today
 FROM documents
+WHERE deleted_at IS NULL
 `
 
 type GetDashboardDocumentStatsRow struct {
@@ -19,18 +19,50 @@ FROM documents d
 LEFT JOIN document_correspondents dc ON dc.document_id = d.id
 LEFT JOIN correspondents c ON c.id = dc.correspondent_id
 WHERE
-    ($1::text IS NULL OR $1::text = ''
+    d.deleted_at IS NULL
+    AND ($1::text IS NULL OR $1::text = ''
         OR d.search_vector @@ websearch_to_tsquery('english', $1::text))
     AND (NOT $2::boolean OR c.id = $3::uuid)
-    AND (NOT $4::boolean OR d.document_date >= $5::timestamptz)
-    AND (NOT $6::boolean OR d.document_date <= $7::timestamptz)
-    AND (NOT $8::boolean
+    AND (NOT $4::boolean OR d.document_type_id = $5::uuid)
+    AND (NOT $6::boolean OR d.document_date >= $7::timestamptz)
+    AND (NOT $8::boolean OR d.document_date <= $9::timestamptz)
+    AND (NOT $10::boolean OR d.page_count >= $11::int)
+    AND (NOT $12::boolean OR d.page_count <= $13::int)
+    AND (NOT $14::boolean
         OR d.id IN (
             SELECT dt.document_id
             FROM document_tags dt
-            WHERE dt.tag_id = ANY($9::uuid[])
+            WHERE dt.tag_id = ANY($15::uuid[])
             GROUP BY dt.document_id
-            HAVING COUNT(DISTINCT dt.tag_id) = $10::int
+            HAVING COUNT(DISTINCT dt.tag_id) = $16::int
+        ))
+    -- Custom field filters (optional - AND logic: must match ALL).
+    -- Ops are type-specific so values are only cast to their field's type.
+    AND (NOT $17::boolean
+        OR d.id IN (
+            SELECT dcf.document_id
+            FROM document_custom_fields dcf
+            INNER JOIN jsonb_to_recordset($18::jsonb)
+                AS f(field_id uuid, op text, val text) ON f.field_id = dcf.field_id
+            WHERE CASE f.op
+                WHEN 'set' THEN true
+                WHEN 'text_eq' THEN lower(dcf.value_text) = lower(f.val)
+                WHEN 'text_contains' THEN strpos(lower(dcf.value_text), lower(f.val)) > 0
+                WHEN 'num_eq' THEN dcf.value_number = f.val::numeric
+                WHEN 'num_gt' THEN dcf.value_number > f.val::numeric
+                WHEN 'num_gte' THEN dcf.value_number >= f.val::numeric
+                WHEN 'num_lt' THEN dcf.value_number < f.val::numeric
+                WHEN 'num_lte' THEN dcf.value_number <= f.val::numeric
+                WHEN 'date_eq' THEN dcf.value_date = f.val::date
+                WHEN 'date_before' THEN dcf.value_date < f.val::date
+                WHEN 'date_after' THEN dcf.value_date > f.val::date
+                WHEN 'date_next' THEN dcf.value_date BETWEEN CURRENT_DATE AND CURRENT_DATE + f.val::int
+                WHEN 'date_last' THEN dcf.value_date BETWEEN CURRENT_DATE - f.val::int AND CURRENT_DATE
+                WHEN 'bool_eq' THEN dcf.value_bool = f.val::boolean
+                WHEN 'link_eq' THEN dcf.value_document_id = f.val::uuid
+                ELSE false END
+            GROUP BY dcf.document_id
+            HAVING COUNT(*) = $19::int
         ))
 `
 
@@ -38,13 +70,22 @@ type CountSearchDocumentsParams struct {
 	Query            *string     `json:"query"`
 	HasCorrespondent bool        `json:"has_correspondent"`
 	CorrespondentID  uuid.UUID   `json:"correspondent_id"`
+	HasDocumentType  bool        `json:"has_document_type"`
+	DocumentTypeID   uuid.UUID   `json:"document_type_id"`
 	HasDateFrom      bool        `json:"has_date_from"`
 	DateFrom         time.Time   `json:"date_from"`
 	HasDateTo        bool        `json:"has_date_to"`
 	DateTo           time.Time   `json:"date_to"`
+	HasPagesMin      bool        `json:"has_pages_min"`
+	PagesMin         int32       `json:"pages_min"`
+	HasPagesMax      bool        `json:"has_pages_max"`
+	PagesMax         int32       `json:"pages_max"`
 	HasTags          bool        `json:"has_tags"`
 	TagIds           []uuid.UUID `json:"tag_ids"`
 	TagCount         int32       `json:"tag_count"`
+	HasFields        bool        `json:"has_fields"`
+	FieldFilters     []byte      `json:"field_filters"`
+	FieldCount       int32       `json:"field_count"`
 }
 
 func (q *Queries) CountSearchDocuments(ctx context.Context, arg CountSearchDocumentsParams) (int32, error) {
@@ -52,23 +93,44 @@ func (q *Queries) CountSearchDocuments(ctx context.Context, arg CountSearchDocum
 		arg.Query,
 		arg.HasCorrespondent,
 		arg.CorrespondentID,
+		arg.HasDocumentType,
+		arg.DocumentTypeID,
 		arg.HasDateFrom,
 		arg.DateFrom,
 		arg.HasDateTo,
 		arg.DateTo,
+		arg.HasPagesMin,
+		arg.PagesMin,
+		arg.HasPagesMax,
+		arg.PagesMax,
 		arg.HasTags,
 		arg.TagIds,
 		arg.TagCount,
+		arg.HasFields,
+		arg.FieldFilters,
+		arg.FieldCount,
 	)
 	var total int32
 	err := row.Scan(&total)
 	return total, err
 }
 
+const countTrashedDocuments = `-- name: CountTrashedDocuments :one
+SELECT COUNT(*)::int FROM documents WHERE deleted_at IS NOT NULL
+`
+
+func (q *Queries) CountTrashedDocuments(ctx context.Context) (int32, error) {
+	row := q.db.QueryRow(ctx, countTrashedDocuments)
+	var column_1 int32
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
 const createDocument = `-- name: CreateDocument :one
-INSERT INTO documents (id, original_filename, content_hash, file_size, page_count, pdf_title, pdf_author, pdf_created_at, document_date)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, COALESCE($9, NOW()))
-RETURNING id, original_filename, content_hash, file_size, page_count, pdf_title, pdf_author, pdf_created_at, document_date, created_at, updated_at, processing_status, text_content, thumbnail_generated, processing_error, processed_at, search_vector
+INSERT INTO documents (id, original_filename, content_hash, file_size, page_count, pdf_title, pdf_author, pdf_created_at, document_date, content_type, parent_document_id, date_confidence)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8,
+    COALESCE($9::timestamptz, NOW()), $10, $11, $12)
+RETURNING id, original_filename, content_hash, file_size, page_count, pdf_title, pdf_author, pdf_created_at, document_date, created_at, updated_at, processing_status, text_content, thumbnail_generated, processing_error, processed_at, content_type, parent_document_id, deleted_at, revision, text_simhash, image_hash, edit_version, archive_checksum, date_confidence, title, notes, search_vector, document_type_id
 `
 
 type CreateDocumentParams struct {
@@ -80,7 +142,10 @@ type CreateDocumentParams struct {
 	PdfTitle         *string            `json:"pdf_title"`
 	PdfAuthor        *string            `json:"pdf_author"`
 	PdfCreatedAt     pgtype.Timestamptz `json:"pdf_created_at"`
-	Column9          pgtype.Timestamptz `json:"column_9"`
+	DocumentDate     pgtype.Timestamptz `json:"document_date"`
+	ContentType      string             `json:"content_type"`
+	ParentDocumentID pgtype.UUID        `json:"parent_document_id"`
+	DateConfidence   pgtype.Numeric     `json:"date_confidence"`
 }
 
 func (q *Queries) CreateDocument(ctx context.Context, arg CreateDocumentParams) (Document, error) {
@@ -93,7 +158,10 @@ func (q *Queries) CreateDocument(ctx context.Context, arg CreateDocumentParams)
 		arg.PdfTitle,
 		arg.PdfAuthor,
 		arg.PdfCreatedAt,
-		arg.Column9,
+		arg.DocumentDate,
+		arg.ContentType,
+		arg.ParentDocumentID,
+		arg.DateConfidence,
 	)
 	var i Document
 	err := row.Scan(
@@ -113,7 +181,19 @@ func (q *Queries) CreateDocument(ctx context.Context, arg CreateDocumentParams)
 		&i.ThumbnailGenerated,
 		&i.ProcessingError,
 		&i.ProcessedAt,
+		&i.ContentType,
+		&i.ParentDocumentID,
+		&i.DeletedAt,
+		&i.Revision,
+		&i.TextSimhash,
+		&i.ImageHash,
+		&i.EditVersion,
+		&i.ArchiveChecksum,
+		&i.DateConfidence,
+		&i.Title,
+		&i.Notes,
 		&i.SearchVector,
+		&i.DocumentTypeID,
 	)
 	return i, err
 }
@@ -163,7 +243,7 @@ func (q *Queries) DeleteDocument(ctx context.Context, id uuid.UUID) error {
 }
 
 const getDocument = `-- name: GetDocument :one
-SELECT id, original_filename, content_hash, file_size, page_count, pdf_title, pdf_author, pdf_created_at, document_date, created_at, updated_at, processing_status, text_content, thumbnail_generated, processing_error, processed_at, search_vector FROM documents WHERE id = $1
+SELECT id, original_filename, content_hash, file_size, page_count, pdf_title, pdf_author, pdf_created_at, document_date, created_at, updated_at, processing_status, text_content, thumbnail_generated, processing_error, processed_at, content_type, parent_document_id, deleted_at, revision, text_simhash, image_hash, edit_version, archive_checksum, date_confidence, title, notes, search_vector, document_type_id FROM documents WHERE id = $1
 `
 
 func (q *Queries) GetDocument(ctx context.Context, id uuid.UUID) (Document, error) {
@@ -186,13 +266,25 @@ func (q *Queries) GetDocument(ctx context.Context, id uuid.UUID) (Document, erro
 		&i.ThumbnailGenerated,
 		&i.ProcessingError,
 		&i.ProcessedAt,
+		&i.ContentType,
+		&i.ParentDocumentID,
+		&i.DeletedAt,
+		&i.Revision,
+		&i.TextSimhash,
+		&i.ImageHash,
+		&i.EditVersion,
+		&i.ArchiveChecksum,
+		&i.DateConfidence,
+		&i.Title,
+		&i.Notes,
 		&i.SearchVector,
+		&i.DocumentTypeID,
 	)
 	return i, err
 }
 
 const getDocumentByHash = `-- name: GetDocumentByHash :one
-SELECT id, original_filename, content_hash, file_size, page_count, pdf_title, pdf_author, pdf_created_at, document_date, created_at, updated_at, processing_status, text_content, thumbnail_generated, processing_error, processed_at, search_vector FROM documents WHERE content_hash = $1
+SELECT id, original_filename, content_hash, file_size, page_count, pdf_title, pdf_author, pdf_created_at, document_date, created_at, updated_at, processing_status, text_content, thumbnail_generated, processing_error, processed_at, content_type, parent_document_id, deleted_at, revision, text_simhash, image_hash, edit_version, archive_checksum, date_confidence, title, notes, search_vector, document_type_id FROM documents WHERE content_hash = $1
 `
 
 func (q *Queries) GetDocumentByHash(ctx context.Context, contentHash string) (Document, error) {
@@ -215,7 +307,19 @@ func (q *Queries) GetDocumentByHash(ctx context.Context, contentHash string) (Do
 		&i.ThumbnailGenerated,
 		&i.ProcessingError,
 		&i.ProcessedAt,
+		&i.ContentType,
+		&i.ParentDocumentID,
+		&i.DeletedAt,
+		&i.Revision,
+		&i.TextSimhash,
+		&i.ImageHash,
+		&i.EditVersion,
+		&i.ArchiveChecksum,
+		&i.DateConfidence,
+		&i.Title,
+		&i.Notes,
 		&i.SearchVector,
+		&i.DocumentTypeID,
 	)
 	return i, err
 }
@@ -272,7 +376,7 @@ func (q *Queries) GetLatestDocumentEvent(ctx context.Context, documentID uuid.UU
 }
 
 const getPendingProcessingDocuments = `-- name: GetPendingProcessingDocuments :many
-SELECT id, original_filename, content_hash, file_size, page_count, pdf_title, pdf_author, pdf_created_at, document_date, created_at, updated_at, processing_status, text_content, thumbnail_generated, processing_error, processed_at, search_vector FROM documents
+SELECT id, original_filename, content_hash, file_size, page_count, pdf_title, pdf_author, pdf_created_at, document_date, created_at, updated_at, processing_status, text_content, thumbnail_generated, processing_error, processed_at, content_type, parent_document_id, deleted_at, revision, text_simhash, image_hash, edit_version, archive_checksum, date_confidence, title, notes, search_vector, document_type_id FROM documents
 WHERE processing_status = 'pending'
 ORDER BY created_at ASC
 LIMIT $1
@@ -304,7 +408,73 @@ func (q *Queries) GetPendingProcessingDocuments(ctx context.Context, limit int64
 			&i.ThumbnailGenerated,
 			&i.ProcessingError,
 			&i.ProcessedAt,
+			&i.ContentType,
+			&i.ParentDocumentID,
+			&i.DeletedAt,
+			&i.Revision,
+			&i.TextSimhash,
+			&i.ImageHash,
+			&i.EditVersion,
+			&i.ArchiveChecksum,
+			&i.DateConfidence,
+			&i.Title,
+			&i.Notes,
+			&i.SearchVector,
+			&i.DocumentTypeID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listChildDocuments = `-- name: ListChildDocuments :many
+SELECT id, original_filename, content_hash, file_size, page_count, pdf_title, pdf_author, pdf_created_at, document_date, created_at, updated_at, processing_status, text_content, thumbnail_generated, processing_error, processed_at, content_type, parent_document_id, deleted_at, revision, text_simhash, image_hash, edit_version, archive_checksum, date_confidence, title, notes, search_vector, document_type_id FROM documents WHERE parent_document_id = $1 ORDER BY created_at
+`
+
+func (q *Queries) ListChildDocuments(ctx context.Context, parentDocumentID pgtype.UUID) ([]Document, error) {
+	rows, err := q.db.Query(ctx, listChildDocuments, parentDocumentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Document{}
+	for rows.Next() {
+		var i Document
+		if err := rows.Scan(
+			&i.ID,
+			&i.OriginalFilename,
+			&i.ContentHash,
+			&i.FileSize,
+			&i.PageCount,
+			&i.PdfTitle,
+			&i.PdfAuthor,
+			&i.PdfCreatedAt,
+			&i.DocumentDate,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ProcessingStatus,
+			&i.TextContent,
+			&i.ThumbnailGenerated,
+			&i.ProcessingError,
+			&i.ProcessedAt,
+			&i.ContentType,
+			&i.ParentDocumentID,
+			&i.DeletedAt,
+			&i.Revision,
+			&i.TextSimhash,
+			&i.ImageHash,
+			&i.EditVersion,
+			&i.ArchiveChecksum,
+			&i.DateConfidence,
+			&i.Title,
+			&i.Notes,
 			&i.SearchVector,
+			&i.DocumentTypeID,
 		); err != nil {
 			return nil, err
 		}
@@ -317,7 +487,7 @@ func (q *Queries) GetPendingProcessingDocuments(ctx context.Context, limit int64
 }
 
 const listDocuments = `-- name: ListDocuments :many
-SELECT id, original_filename, content_hash, file_size, page_count, pdf_title, pdf_author, pdf_created_at, document_date, created_at, updated_at, processing_status, text_content, thumbnail_generated, processing_error, processed_at, search_vector FROM documents ORDER BY created_at DESC LIMIT $1 OFFSET $2
+SELECT id, original_filename, content_hash, file_size, page_count, pdf_title, pdf_author, pdf_created_at, document_date, created_at, updated_at, processing_status, text_content, thumbnail_generated, processing_error, processed_at, content_type, parent_document_id, deleted_at, revision, text_simhash, image_hash, edit_version, archive_checksum, date_confidence, title, notes, search_vector, document_type_id FROM documents WHERE deleted_at IS NULL ORDER BY created_at DESC LIMIT $1 OFFSET $2
 `
 
 type ListDocumentsParams struct {
@@ -351,7 +521,19 @@ func (q *Queries) ListDocuments(ctx context.Context, arg ListDocumentsParams) ([
 			&i.ThumbnailGenerated,
 			&i.ProcessingError,
 			&i.ProcessedAt,
+			&i.ContentType,
+			&i.ParentDocumentID,
+			&i.DeletedAt,
+			&i.Revision,
+			&i.TextSimhash,
+			&i.ImageHash,
+			&i.EditVersion,
+			&i.ArchiveChecksum,
+			&i.DateConfidence,
+			&i.Title,
+			&i.Notes,
 			&i.SearchVector,
+			&i.DocumentTypeID,
 		); err != nil {
 			return nil, err
 		}
@@ -364,10 +546,11 @@ func (q *Queries) ListDocuments(ctx context.Context, arg ListDocumentsParams) ([
 }
 
 const listDocumentsWithCorrespondent = `-- name: ListDocumentsWithCorrespondent :many
-SELECT d.id, d.original_filename, d.content_hash, d.file_size, d.page_count, d.pdf_title, d.pdf_author, d.pdf_created_at, d.document_date, d.created_at, d.updated_at, d.processing_status, d.text_content, d.thumbnail_generated, d.processing_error, d.processed_at, d.search_vector, c.id as correspondent_id, c.name as correspondent_name
+SELECT d.id, d.original_filename, d.content_hash, d.file_size, d.page_count, d.pdf_title, d.pdf_author, d.pdf_created_at, d.document_date, d.created_at, d.updated_at, d.processing_status, d.text_content, d.thumbnail_generated, d.processing_error, d.processed_at, d.content_type, d.parent_document_id, d.deleted_at, d.revision, d.text_simhash, d.image_hash, d.edit_version, d.archive_checksum, d.date_confidence, d.title, d.notes, d.search_vector, d.document_type_id, c.id as correspondent_id, c.name as correspondent_name
 FROM documents d
 LEFT JOIN document_correspondents dc ON dc.document_id = d.id
 LEFT JOIN correspondents c ON c.id = dc.correspondent_id
+WHERE d.deleted_at IS NULL
 ORDER BY d.created_at DESC
 LIMIT $1 OFFSET $2
 `
@@ -394,7 +577,19 @@ type ListDocumentsWithCorrespondentRow struct {
 	ThumbnailGenerated bool               `json:"thumbnail_generated"`
 	ProcessingError    *string            `json:"processing_error"`
 	ProcessedAt        pgtype.Timestamptz `json:"processed_at"`
+	ContentType        string             `json:"content_type"`
+	ParentDocumentID   pgtype.UUID        `json:"parent_document_id"`
+	DeletedAt          pgtype.Timestamptz `json:"deleted_at"`
+	Revision           int32              `json:"revision"`
+	TextSimhash        *int64             `json:"text_simhash"`
+	ImageHash          *int64             `json:"image_hash"`
+	EditVersion        int32              `json:"edit_version"`
+	ArchiveChecksum    *string            `json:"archive_checksum"`
+	DateConfidence     pgtype.Numeric     `json:"date_confidence"`
+	Title              *string            `json:"title"`
+	Notes              *string            `json:"notes"`
 	SearchVector       interface{}        `json:"search_vector"`
+	DocumentTypeID     pgtype.UUID        `json:"document_type_id"`
 	CorrespondentID    pgtype.UUID        `json:"correspondent_id"`
 	CorrespondentName  *string            `json:"correspondent_name"`
 }
@@ -425,7 +620,19 @@ func (q *Queries) ListDocumentsWithCorrespondent(ctx context.Context, arg ListDo
 			&i.ThumbnailGenerated,
 			&i.ProcessingError,
 			&i.ProcessedAt,
+			&i.ContentType,
+			&i.ParentDocumentID,
+			&i.DeletedAt,
+			&i.Revision,
+			&i.TextSimhash,
+			&i.ImageHash,
+			&i.EditVersion,
+			&i.ArchiveChecksum,
+			&i.DateConfidence,
+			&i.Title,
+			&i.Notes,
 			&i.SearchVector,
+			&i.DocumentTypeID,
 			&i.CorrespondentID,
 			&i.CorrespondentName,
 		); err != nil {
@@ -439,11 +646,295 @@ func (q *Queries) ListDocumentsWithCorrespondent(ctx context.Context, arg ListDo
 	return items, nil
 }
 
+const listTrashedDocuments = `-- name: ListTrashedDocuments :many
+SELECT id, original_filename, content_hash, file_size, page_count, pdf_title, pdf_author, pdf_created_at, document_date, created_at, updated_at, processing_status, text_content, thumbnail_generated, processing_error, processed_at, content_type, parent_document_id, deleted_at, revision, text_simhash, image_hash, edit_version, archive_checksum, date_confidence, title, notes, search_vector, document_type_id FROM documents WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC
+`
+
+func (q *Queries) ListTrashedDocuments(ctx context.Context) ([]Document, error) {
+	rows, err := q.db.Query(ctx, listTrashedDocuments)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Document{}
+	for rows.Next() {
+		var i Document
+		if err := rows.Scan(
+			&i.ID,
+			&i.OriginalFilename,
+			&i.ContentHash,
+			&i.FileSize,
+			&i.PageCount,
+			&i.PdfTitle,
+			&i.PdfAuthor,
+			&i.PdfCreatedAt,
+			&i.DocumentDate,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ProcessingStatus,
+			&i.TextContent,
+			&i.ThumbnailGenerated,
+			&i.ProcessingError,
+			&i.ProcessedAt,
+			&i.ContentType,
+			&i.ParentDocumentID,
+			&i.DeletedAt,
+			&i.Revision,
+			&i.TextSimhash,
+			&i.ImageHash,
+			&i.EditVersion,
+			&i.ArchiveChecksum,
+			&i.DateConfidence,
+			&i.Title,
+			&i.Notes,
+			&i.SearchVector,
+			&i.DocumentTypeID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTrashedDocumentsBefore = `-- name: ListTrashedDocumentsBefore :many
+SELECT id, original_filename, content_hash, file_size, page_count, pdf_title, pdf_author, pdf_created_at, document_date, created_at, updated_at, processing_status, text_content, thumbnail_generated, processing_error, processed_at, content_type, parent_document_id, deleted_at, revision, text_simhash, image_hash, edit_version, archive_checksum, date_confidence, title, notes, search_vector, document_type_id FROM documents WHERE deleted_at IS NOT NULL AND deleted_at < $1 ORDER BY deleted_at
+`
+
+func (q *Queries) ListTrashedDocumentsBefore(ctx context.Context, deletedAt pgtype.Timestamptz) ([]Document, error) {
+	rows, err := q.db.Query(ctx, listTrashedDocumentsBefore, deletedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Document{}
+	for rows.Next() {
+		var i Document
+		if err := rows.Scan(
+			&i.ID,
+			&i.OriginalFilename,
+			&i.ContentHash,
+			&i.FileSize,
+			&i.PageCount,
+			&i.PdfTitle,
+			&i.PdfAuthor,
+			&i.PdfCreatedAt,
+			&i.DocumentDate,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ProcessingStatus,
+			&i.TextContent,
+			&i.ThumbnailGenerated,
+			&i.ProcessingError,
+			&i.ProcessedAt,
+			&i.ContentType,
+			&i.ParentDocumentID,
+			&i.DeletedAt,
+			&i.Revision,
+			&i.TextSimhash,
+			&i.ImageHash,
+			&i.EditVersion,
+			&i.ArchiveChecksum,
+			&i.DateConfidence,
+			&i.Title,
+			&i.Notes,
+			&i.SearchVector,
+			&i.DocumentTypeID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockDocument = `-- name: LockDocument :one
+SELECT id, original_filename, content_hash, file_size, page_count, pdf_title, pdf_author, pdf_created_at, document_date, created_at, updated_at, processing_status, text_content, thumbnail_generated, processing_error, processed_at, content_type, parent_document_id, deleted_at, revision, text_simhash, image_hash, edit_version, archive_checksum, date_confidence, title, notes, search_vector, document_type_id FROM documents WHERE id = $1 FOR UPDATE
+`
+
+// Locks a document's row until the transaction ends, serializing edits of its files
+func (q *Queries) LockDocument(ctx context.Context, id uuid.UUID) (Document, error) {
+	row := q.db.QueryRow(ctx, lockDocument, id)
+	var i Document
+	err := row.Scan(
+		&i.ID,
+		&i.OriginalFilename,
+		&i.ContentHash,
+		&i.FileSize,
+		&i.PageCount,
+		&i.PdfTitle,
+		&i.PdfAuthor,
+		&i.PdfCreatedAt,
+		&i.DocumentDate,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ProcessingStatus,
+		&i.TextContent,
+		&i.ThumbnailGenerated,
+		&i.ProcessingError,
+		&i.ProcessedAt,
+		&i.ContentType,
+		&i.ParentDocumentID,
+		&i.DeletedAt,
+		&i.Revision,
+		&i.TextSimhash,
+		&i.ImageHash,
+		&i.EditVersion,
+		&i.ArchiveChecksum,
+		&i.DateConfidence,
+		&i.Title,
+		&i.Notes,
+		&i.SearchVector,
+		&i.DocumentTypeID,
+	)
+	return i, err
+}
+
+const lockName = `-- name: LockName :exec
+SELECT pg_advisory_xact_lock($1::int, hashtext(lower($2::text)))
+`
+
+type LockNameParams struct {
+	Kind int32  `json:"kind"`
+	Name string `json:"name"`
+}
+
+// Serializes find-or-create of a named tag, correspondent or document type
+// across concurrent ingests; the lock is held until the transaction ends
+func (q *Queries) LockName(ctx context.Context, arg LockNameParams) error {
+	_, err := q.db.Exec(ctx, lockName, arg.Kind, arg.Name)
+	return err
+}
+
+const replaceDocumentFile = `-- name: ReplaceDocumentFile :one
+UPDATE documents SET
+    original_filename = $2,
+    content_hash = $3,
+    file_size = $4,
+    content_type = $5,
+    revision = revision + 1,
+    edit_version = 0,
+    archive_checksum = NULL,
+    page_count = NULL,
+    pdf_title = NULL,
+    pdf_author = NULL,
+    pdf_created_at = NULL,
+    text_content = NULL,
+    thumbnail_generated = false,
+    processing_status = 'pending',
+    processing_error = NULL,
+    processed_at = NULL,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING id, original_filename, content_hash, file_size, page_count, pdf_title, pdf_author, pdf_created_at, document_date, created_at, updated_at, processing_status, text_content, thumbnail_generated, processing_error, processed_at, content_type, parent_document_id, deleted_at, revision, text_simhash, image_hash, edit_version, archive_checksum, date_confidence, title, notes, search_vector, document_type_id
+`
+
+type ReplaceDocumentFileParams struct {
+	ID               uuid.UUID `json:"id"`
+	OriginalFilename string    `json:"original_filename"`
+	ContentHash      string    `json:"content_hash"`
+	FileSize         int64     `json:"file_size"`
+	ContentType      string    `json:"content_type"`
+}
+
+func (q *Queries) ReplaceDocumentFile(ctx context.Context, arg ReplaceDocumentFileParams) (Document, error) {
+	row := q.db.QueryRow(ctx, replaceDocumentFile,
+		arg.ID,
+		arg.OriginalFilename,
+		arg.ContentHash,
+		arg.FileSize,
+		arg.ContentType,
+	)
+	var i Document
+	err := row.Scan(
+		&i.ID,
+		&i.OriginalFilename,
+		&i.ContentHash,
+		&i.FileSize,
+		&i.PageCount,
+		&i.PdfTitle,
+		&i.PdfAuthor,
+		&i.PdfCreatedAt,
+		&i.DocumentDate,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ProcessingStatus,
+		&i.TextContent,
+		&i.ThumbnailGenerated,
+		&i.ProcessingError,
+		&i.ProcessedAt,
+		&i.ContentType,
+		&i.ParentDocumentID,
+		&i.DeletedAt,
+		&i.Revision,
+		&i.TextSimhash,
+		&i.ImageHash,
+		&i.EditVersion,
+		&i.ArchiveChecksum,
+		&i.DateConfidence,
+		&i.Title,
+		&i.Notes,
+		&i.SearchVector,
+		&i.DocumentTypeID,
+	)
+	return i, err
+}
+
+const restoreDocument = `-- name: RestoreDocument :one
+UPDATE documents SET deleted_at = NULL, updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING id, original_filename, content_hash, file_size, page_count, pdf_title, pdf_author, pdf_created_at, document_date, created_at, updated_at, processing_status, text_content, thumbnail_generated, processing_error, processed_at, content_type, parent_document_id, deleted_at, revision, text_simhash, image_hash, edit_version, archive_checksum, date_confidence, title, notes, search_vector, document_type_id
+`
+
+func (q *Queries) RestoreDocument(ctx context.Context, id uuid.UUID) (Document, error) {
+	row := q.db.QueryRow(ctx, restoreDocument, id)
+	var i Document
+	err := row.Scan(
+		&i.ID,
+		&i.OriginalFilename,
+		&i.ContentHash,
+		&i.FileSize,
+		&i.PageCount,
+		&i.PdfTitle,
+		&i.PdfAuthor,
+		&i.PdfCreatedAt,
+		&i.DocumentDate,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ProcessingStatus,
+		&i.TextContent,
+		&i.ThumbnailGenerated,
+		&i.ProcessingError,
+		&i.ProcessedAt,
+		&i.ContentType,
+		&i.ParentDocumentID,
+		&i.DeletedAt,
+		&i.Revision,
+		&i.TextSimhash,
+		&i.ImageHash,
+		&i.EditVersion,
+		&i.ArchiveChecksum,
+		&i.DateConfidence,
+		&i.Title,
+		&i.Notes,
+		&i.SearchVector,
+		&i.DocumentTypeID,
+	)
+	return i, err
+}
+
 const searchDocuments = `-- name: SearchDocuments :many
 SELECT
-    d.id, d.original_filename, d.content_hash, d.file_size, d.page_count, d.pdf_title, d.pdf_author, d.pdf_created_at, d.document_date, d.created_at, d.updated_at, d.processing_status, d.text_content, d.thumbnail_generated, d.processing_error, d.processed_at, d.search_vector,
+    d.id, d.original_filename, d.content_hash, d.file_size, d.page_count, d.pdf_title, d.pdf_author, d.pdf_created_at, d.document_date, d.created_at, d.updated_at, d.processing_status, d.text_content, d.thumbnail_generated, d.processing_error, d.processed_at, d.content_type, d.parent_document_id, d.deleted_at, d.revision, d.text_simhash, d.image_hash, d.edit_version, d.archive_checksum, d.date_confidence, d.title, d.notes, d.search_vector, d.document_type_id,
     c.id as correspondent_id,
     c.name as correspondent_name,
+    dty.name as document_type_name,
     CASE WHEN $1::text IS NOT NULL AND $1::text != ''
          THEN ts_rank(d.search_vector, websearch_to_tsquery('english', $1::text))
          ELSE 0 END as rank,
@@ -454,43 +945,88 @@ SELECT
 FROM documents d
 LEFT JOIN document_correspondents dc ON dc.document_id = d.id
 LEFT JOIN correspondents c ON c.id = dc.correspondent_id
+LEFT JOIN document_types dty ON dty.id = d.document_type_id
 WHERE
+    -- Trashed documents are only listed on the trash page
+    d.deleted_at IS NULL
     -- Full-text search (optional - empty/null matches all)
-    ($1::text IS NULL OR $1::text = ''
+    AND ($1::text IS NULL OR $1::text = ''
         OR d.search_vector @@ websearch_to_tsquery('english', $1::text))
     -- Correspondent filter (optional)
     AND (NOT $2::boolean OR c.id = $3::uuid)
+    -- Document type filter (optional)
+    AND (NOT $4::boolean OR d.document_type_id = $5::uuid)
     -- Date range filter (optional)
-    AND (NOT $4::boolean OR d.document_date >= $5::timestamptz)
-    AND (NOT $6::boolean OR d.document_date <= $7::timestamptz)
+    AND (NOT $6::boolean OR d.document_date >= $7::timestamptz)
+    AND (NOT $8::boolean OR d.document_date <= $9::timestamptz)
+    -- Page count range filter (optional)
+    AND (NOT $10::boolean OR d.page_count >= $11::int)
+    AND (NOT $12::boolean OR d.page_count <= $13::int)
     -- Tag filter (optional - AND logic: must have ALL selected tags)
-    AND (NOT $8::boolean
+    AND (NOT $14::boolean
         OR d.id IN (
             SELECT dt.document_id
             FROM document_tags dt
-            WHERE dt.tag_id = ANY($9::uuid[])
+            WHERE dt.tag_id = ANY($15::uuid[])
             GROUP BY dt.document_id
-            HAVING COUNT(DISTINCT dt.tag_id) = $10::int
+            HAVING COUNT(DISTINCT dt.tag_id) = $16::int
+        ))
+    -- Custom field filters (optional - AND logic: must match ALL).
+    -- Ops are type-specific so values are only cast to their field's type.
+    AND (NOT $17::boolean
+        OR d.id IN (
+            SELECT dcf.document_id
+            FROM document_custom_fields dcf
+            INNER JOIN jsonb_to_recordset($18::jsonb)
+                AS f(field_id uuid, op text, val text) ON f.field_id = dcf.field_id
+            WHERE CASE f.op
+                WHEN 'set' THEN true
+                WHEN 'text_eq' THEN lower(dcf.value_text) = lower(f.val)
+                WHEN 'text_contains' THEN strpos(lower(dcf.value_text), lower(f.val)) > 0
+                WHEN 'num_eq' THEN dcf.value_number = f.val::numeric
+                WHEN 'num_gt' THEN dcf.value_number > f.val::numeric
+                WHEN 'num_gte' THEN dcf.value_number >= f.val::numeric
+                WHEN 'num_lt' THEN dcf.value_number < f.val::numeric
+                WHEN 'num_lte' THEN dcf.value_number <= f.val::numeric
+                WHEN 'date_eq' THEN dcf.value_date = f.val::date
+                WHEN 'date_before' THEN dcf.value_date < f.val::date
+                WHEN 'date_after' THEN dcf.value_date > f.val::date
+                WHEN 'date_next' THEN dcf.value_date BETWEEN CURRENT_DATE AND CURRENT_DATE + f.val::int
+                WHEN 'date_last' THEN dcf.value_date BETWEEN CURRENT_DATE - f.val::int AND CURRENT_DATE
+                WHEN 'bool_eq' THEN dcf.value_bool = f.val::boolean
+                WHEN 'link_eq' THEN dcf.value_document_id = f.val::uuid
+                ELSE false END
+            GROUP BY dcf.document_id
+            HAVING COUNT(*) = $19::int
         ))
 ORDER BY
     CASE WHEN $1::text IS NOT NULL AND $1::text != ''
          THEN ts_rank(d.search_vector, websearch_to_tsquery('english', $1::text))
          ELSE 0 END DESC,
     d.document_date DESC NULLS LAST
-LIMIT $12 OFFSET $11
+LIMIT $21 OFFSET $20
 `
 
 type SearchDocumentsParams struct {
 	Query            *string     `json:"query"`
 	HasCorrespondent bool        `json:"has_correspondent"`
 	CorrespondentID  uuid.UUID   `json:"correspondent_id"`
+	HasDocumentType  bool        `json:"has_document_type"`
+	DocumentTypeID   uuid.UUID   `json:"document_type_id"`
 	HasDateFrom      bool        `json:"has_date_from"`
 	DateFrom         time.Time   `json:"date_from"`
 	HasDateTo        bool        `json:"has_date_to"`
 	DateTo           time.Time   `json:"date_to"`
+	HasPagesMin      bool        `json:"has_pages_min"`
+	PagesMin         int32       `json:"pages_min"`
+	HasPagesMax      bool        `json:"has_pages_max"`
+	PagesMax         int32       `json:"pages_max"`
 	HasTags          bool        `json:"has_tags"`
 	TagIds           []uuid.UUID `json:"tag_ids"`
 	TagCount         int32       `json:"tag_count"`
+	HasFields        bool        `json:"has_fields"`
+	FieldFilters     []byte      `json:"field_filters"`
+	FieldCount       int32       `json:"field_count"`
 	OffsetCount      int64       `json:"offset_count"`
 	LimitCount       int64       `json:"limit_count"`
 }
@@ -512,9 +1048,22 @@ type SearchDocumentsRow struct {
 	ThumbnailGenerated bool               `json:"thumbnail_generated"`
 	ProcessingError    *string            `json:"processing_error"`
 	ProcessedAt        pgtype.Timestamptz `json:"processed_at"`
+	ContentType        string             `json:"content_type"`
+	ParentDocumentID   pgtype.UUID        `json:"parent_document_id"`
+	DeletedAt          pgtype.Timestamptz `json:"deleted_at"`
+	Revision           int32              `json:"revision"`
+	TextSimhash        *int64             `json:"text_simhash"`
+	ImageHash          *int64             `json:"image_hash"`
+	EditVersion        int32              `json:"edit_version"`
+	ArchiveChecksum    *string            `json:"archive_checksum"`
+	DateConfidence     pgtype.Numeric     `json:"date_confidence"`
+	Title              *string            `json:"title"`
+	Notes              *string            `json:"notes"`
 	SearchVector       interface{}        `json:"search_vector"`
+	DocumentTypeID     pgtype.UUID        `json:"document_type_id"`
 	CorrespondentID    pgtype.UUID        `json:"correspondent_id"`
 	CorrespondentName  *string            `json:"correspondent_name"`
+	DocumentTypeName   *string            `json:"document_type_name"`
 	Rank               float32            `json:"rank"`
 	Headline           string             `json:"headline"`
 }
@@ -524,13 +1073,22 @@ func (q *Queries) SearchDocuments(ctx context.Context, arg SearchDocumentsParams
 		arg.Query,
 		arg.HasCorrespondent,
 		arg.CorrespondentID,
+		arg.HasDocumentType,
+		arg.DocumentTypeID,
 		arg.HasDateFrom,
 		arg.DateFrom,
 		arg.HasDateTo,
 		arg.DateTo,
+		arg.HasPagesMin,
+		arg.PagesMin,
+		arg.HasPagesMax,
+		arg.PagesMax,
 		arg.HasTags,
 		arg.TagIds,
 		arg.TagCount,
+		arg.HasFields,
+		arg.FieldFilters,
+		arg.FieldCount,
 		arg.OffsetCount,
 		arg.LimitCount,
 	)
@@ -558,9 +1116,22 @@ func (q *Queries) SearchDocuments(ctx context.Context, arg SearchDocumentsParams
 			&i.ThumbnailGenerated,
 			&i.ProcessingError,
 			&i.ProcessedAt,
+			&i.ContentType,
+			&i.ParentDocumentID,
+			&i.DeletedAt,
+			&i.Revision,
+			&i.TextSimhash,
+			&i.ImageHash,
+			&i.EditVersion,
+			&i.ArchiveChecksum,
+			&i.DateConfidence,
+			&i.Title,
+			&i.Notes,
 			&i.SearchVector,
+			&i.DocumentTypeID,
 			&i.CorrespondentID,
 			&i.CorrespondentName,
+			&i.DocumentTypeName,
 			&i.Rank,
 			&i.Headline,
 		); err != nil {
@@ -574,12 +1145,123 @@ func (q *Queries) SearchDocuments(ctx context.Context, arg SearchDocumentsParams
 	return items, nil
 }
 
+const setDetectedDocumentDate = `-- name: SetDetectedDocumentDate :exec
+UPDATE documents SET
+    document_date = $2,
+    date_confidence = $3,
+    updated_at = NOW()
+WHERE id = $1 AND (date_confidence IS NULL OR date_confidence < 1)
+`
+
+type SetDetectedDocumentDateParams struct {
+	ID             uuid.UUID      `json:"id"`
+	DocumentDate   time.Time      `json:"document_date"`
+	DateConfidence pgtype.Numeric `json:"date_confidence"`
+}
+
+// Dates from the source or a person (confidence 1.00) are never overwritten
+func (q *Queries) SetDetectedDocumentDate(ctx context.Context, arg SetDetectedDocumentDateParams) error {
+	_, err := q.db.Exec(ctx, setDetectedDocumentDate, arg.ID, arg.DocumentDate, arg.DateConfidence)
+	return err
+}
+
+const setDocumentArchive = `-- name: SetDocumentArchive :exec
+UPDATE documents SET archive_checksum = $2, updated_at = NOW()
+WHERE id = $1
+`
+
+type SetDocumentArchiveParams struct {
+	ID              uuid.UUID `json:"id"`
+	ArchiveChecksum *string   `json:"archive_checksum"`
+}
+
+func (q *Queries) SetDocumentArchive(ctx context.Context, arg SetDocumentArchiveParams) error {
+	_, err := q.db.Exec(ctx, setDocumentArchive, arg.ID, arg.ArchiveChecksum)
+	return err
+}
+
+const setDocumentDate = `-- name: SetDocumentDate :exec
+UPDATE documents SET
+    document_date = $2,
+    date_confidence = 1,
+    updated_at = NOW()
+WHERE id = $1
+`
+
+type SetDocumentDateParams struct {
+	ID           uuid.UUID `json:"id"`
+	DocumentDate time.Time `json:"document_date"`
+}
+
+func (q *Queries) SetDocumentDate(ctx context.Context, arg SetDocumentDateParams) error {
+	_, err := q.db.Exec(ctx, setDocumentDate, arg.ID, arg.DocumentDate)
+	return err
+}
+
+const setDocumentEditVersion = `-- name: SetDocumentEditVersion :one
+UPDATE documents SET
+    edit_version = $2,
+    page_count = $3,
+    archive_checksum = NULL,
+    text_content = NULL,
+    thumbnail_generated = false,
+    processing_status = 'pending',
+    processing_error = NULL,
+    processed_at = NULL,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING id, original_filename, content_hash, file_size, page_count, pdf_title, pdf_author, pdf_created_at, document_date, created_at, updated_at, processing_status, text_content, thumbnail_generated, processing_error, processed_at, content_type, parent_document_id, deleted_at, revision, text_simhash, image_hash, edit_version, archive_checksum, date_confidence, title, notes, search_vector, document_type_id
+`
+
+type SetDocumentEditVersionParams struct {
+	ID          uuid.UUID `json:"id"`
+	EditVersion int32     `json:"edit_version"`
+	PageCount   *int32    `json:"page_count"`
+}
+
+func (q *Queries) SetDocumentEditVersion(ctx context.Context, arg SetDocumentEditVersionParams) (Document, error) {
+	row := q.db.QueryRow(ctx, setDocumentEditVersion, arg.ID, arg.EditVersion, arg.PageCount)
+	var i Document
+	err := row.Scan(
+		&i.ID,
+		&i.OriginalFilename,
+		&i.ContentHash,
+		&i.FileSize,
+		&i.PageCount,
+		&i.PdfTitle,
+		&i.PdfAuthor,
+		&i.PdfCreatedAt,
+		&i.DocumentDate,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ProcessingStatus,
+		&i.TextContent,
+		&i.ThumbnailGenerated,
+		&i.ProcessingError,
+		&i.ProcessedAt,
+		&i.ContentType,
+		&i.ParentDocumentID,
+		&i.DeletedAt,
+		&i.Revision,
+		&i.TextSimhash,
+		&i.ImageHash,
+		&i.EditVersion,
+		&i.ArchiveChecksum,
+		&i.DateConfidence,
+		&i.Title,
+		&i.Notes,
+		&i.SearchVector,
+		&i.DocumentTypeID,
+	)
+	return i, err
+}
+
 const setDocumentProcessingStatus = `-- name: SetDocumentProcessingStatus :one
 UPDATE documents SET
     processing_status = $2,
     updated_at = NOW()
 WHERE id = $1
-RETURNING id, original_filename, content_hash, file_size, page_count, pdf_title, pdf_author, pdf_created_at, document_date, created_at, updated_at, processing_status, text_content, thumbnail_generated, processing_error, processed_at, search_vector
+RETURNING id, original_filename, content_hash, file_size, page_count, pdf_title, pdf_author, pdf_created_at, document_date, created_at, updated_at, processing_status, text_content, thumbnail_generated, processing_error, processed_at, content_type, parent_document_id, deleted_at, revision, text_simhash, image_hash, edit_version, archive_checksum, date_confidence, title, notes, search_vector, document_type_id
 `
 
 type SetDocumentProcessingStatusParams struct {
@@ -607,7 +1289,62 @@ func (q *Queries) SetDocumentProcessingStatus(ctx context.Context, arg SetDocume
 		&i.ThumbnailGenerated,
 		&i.ProcessingError,
 		&i.ProcessedAt,
+		&i.ContentType,
+		&i.ParentDocumentID,
+		&i.DeletedAt,
+		&i.Revision,
+		&i.TextSimhash,
+		&i.ImageHash,
+		&i.EditVersion,
+		&i.ArchiveChecksum,
+		&i.DateConfidence,
+		&i.Title,
+		&i.Notes,
 		&i.SearchVector,
+		&i.DocumentTypeID,
+	)
+	return i, err
+}
+
+const trashDocument = `-- name: TrashDocument :one
+UPDATE documents SET deleted_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, original_filename, content_hash, file_size, page_count, pdf_title, pdf_author, pdf_created_at, document_date, created_at, updated_at, processing_status, text_content, thumbnail_generated, processing_error, processed_at, content_type, parent_document_id, deleted_at, revision, text_simhash, image_hash, edit_version, archive_checksum, date_confidence, title, notes, search_vector, document_type_id
+`
+
+func (q *Queries) TrashDocument(ctx context.Context, id uuid.UUID) (Document, error) {
+	row := q.db.QueryRow(ctx, trashDocument, id)
+	var i Document
+	err := row.Scan(
+		&i.ID,
+		&i.OriginalFilename,
+		&i.ContentHash,
+		&i.FileSize,
+		&i.PageCount,
+		&i.PdfTitle,
+		&i.PdfAuthor,
+		&i.PdfCreatedAt,
+		&i.DocumentDate,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ProcessingStatus,
+		&i.TextContent,
+		&i.ThumbnailGenerated,
+		&i.ProcessingError,
+		&i.ProcessedAt,
+		&i.ContentType,
+		&i.ParentDocumentID,
+		&i.DeletedAt,
+		&i.Revision,
+		&i.TextSimhash,
+		&i.ImageHash,
+		&i.EditVersion,
+		&i.ArchiveChecksum,
+		&i.DateConfidence,
+		&i.Title,
+		&i.Notes,
+		&i.SearchVector,
+		&i.DocumentTypeID,
 	)
 	return i, err
 }
@@ -617,7 +1354,7 @@ UPDATE documents SET
   document_date = COALESCE($2, document_date),
   updated_at = NOW()
 WHERE id = $1
-RETURNING id, original_filename, content_hash, file_size, page_count, pdf_title, pdf_author, pdf_created_at, document_date, created_at, updated_at, processing_status, text_content, thumbnail_generated, processing_error, processed_at, search_vector
+RETURNING id, original_filename, content_hash, file_size, page_count, pdf_title, pdf_author, pdf_created_at, document_date, created_at, updated_at, processing_status, text_content, thumbnail_generated, processing_error, processed_at, content_type, parent_document_id, deleted_at, revision, text_simhash, image_hash, edit_version, archive_checksum, date_confidence, title, notes, search_vector, document_type_id
 `
 
 type UpdateDocumentParams struct {
@@ -645,11 +1382,108 @@ func (q *Queries) UpdateDocument(ctx context.Context, arg UpdateDocumentParams)
 		&i.ThumbnailGenerated,
 		&i.ProcessingError,
 		&i.ProcessedAt,
+		&i.ContentType,
+		&i.ParentDocumentID,
+		&i.DeletedAt,
+		&i.Revision,
+		&i.TextSimhash,
+		&i.ImageHash,
+		&i.EditVersion,
+		&i.ArchiveChecksum,
+		&i.DateConfidence,
+		&i.Title,
+		&i.Notes,
 		&i.SearchVector,
+		&i.DocumentTypeID,
 	)
 	return i, err
 }
 
+const updateDocumentDetails = `-- name: UpdateDocumentDetails :one
+UPDATE documents SET
+  title = $2,
+  notes = $3,
+  updated_at = NOW()
+WHERE id = $1
+RETURNING id, original_filename, content_hash, file_size, page_count, pdf_title, pdf_author, pdf_created_at, document_date, created_at, updated_at, processing_status, text_content, thumbnail_generated, processing_error, processed_at, content_type, parent_document_id, deleted_at, revision, text_simhash, image_hash, edit_version, archive_checksum, date_confidence, title, notes, search_vector, document_type_id
+`
+
+type UpdateDocumentDetailsParams struct {
+	ID    uuid.UUID `json:"id"`
+	Title *string   `json:"title"`
+	Notes *string   `json:"notes"`
+}
+
+func (q *Queries) UpdateDocumentDetails(ctx context.Context, arg UpdateDocumentDetailsParams) (Document, error) {
+	row := q.db.QueryRow(ctx, updateDocumentDetails, arg.ID, arg.Title, arg.Notes)
+	var i Document
+	err := row.Scan(
+		&i.ID,
+		&i.OriginalFilename,
+		&i.ContentHash,
+		&i.FileSize,
+		&i.PageCount,
+		&i.PdfTitle,
+		&i.PdfAuthor,
+		&i.PdfCreatedAt,
+		&i.DocumentDate,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ProcessingStatus,
+		&i.TextContent,
+		&i.ThumbnailGenerated,
+		&i.ProcessingError,
+		&i.ProcessedAt,
+		&i.ContentType,
+		&i.ParentDocumentID,
+		&i.DeletedAt,
+		&i.Revision,
+		&i.TextSimhash,
+		&i.ImageHash,
+		&i.EditVersion,
+		&i.ArchiveChecksum,
+		&i.DateConfidence,
+		&i.Title,
+		&i.Notes,
+		&i.SearchVector,
+		&i.DocumentTypeID,
+	)
+	return i, err
+}
+
+const updateDocumentMetadata = `-- name: UpdateDocumentMetadata :exec
+UPDATE documents SET
+    page_count = $2,
+    pdf_title = $3,
+    title = CASE WHEN processed_at IS NULL AND revision = 1 AND edit_version = 0
+        THEN COALESCE(title, $3) ELSE title END,
+    pdf_author = $4,
+    pdf_created_at = $5,
+    updated_at = NOW()
+WHERE id = $1
+`
+
+type UpdateDocumentMetadataParams struct {
+	ID           uuid.UUID          `json:"id"`
+	PageCount    *int32             `json:"page_count"`
+	PdfTitle     *string            `json:"pdf_title"`
+	PdfAuthor    *string            `json:"pdf_author"`
+	PdfCreatedAt pgtype.Timestamptz `json:"pdf_created_at"`
+}
+
+// An unset title is pre-filled from the PDF title when an upload is first
+// processed, so a title cleared by hand stays cleared on reprocessing
+func (q *Queries) UpdateDocumentMetadata(ctx context.Context, arg UpdateDocumentMetadataParams) error {
+	_, err := q.db.Exec(ctx, updateDocumentMetadata,
+		arg.ID,
+		arg.PageCount,
+		arg.PdfTitle,
+		arg.PdfAuthor,
+		arg.PdfCreatedAt,
+	)
+	return err
+}
+
 const updateDocumentProcessing = `-- name: UpdateDocumentProcessing :one
 UPDATE documents SET
     text_content = $2,
@@ -659,7 +1493,7 @@ UPDATE documents SET
     processed_at = $6,
     updated_at = NOW()
 WHERE id = $1
-RETURNING id, original_filename, content_hash, file_size, page_count, pdf_title, pdf_author, pdf_created_at, document_date, created_at, updated_at, processing_status, text_content, thumbnail_generated, processing_error, processed_at, search_vector
+RETURNING id, original_filename, content_hash, file_size, page_count, pdf_title, pdf_author, pdf_created_at, document_date, created_at, updated_at, processing_status, text_content, thumbnail_generated, processing_error, processed_at, content_type, parent_document_id, deleted_at, revision, text_simhash, image_hash, edit_version, archive_checksum, date_confidence, title, notes, search_vector, document_type_id
 `
 
 type UpdateDocumentProcessingParams struct {
@@ -698,7 +1532,19 @@ func (q *Queries) UpdateDocumentProcessing(ctx context.Context, arg UpdateDocume
 		&i.ThumbnailGenerated,
 		&i.ProcessingError,
 		&i.ProcessedAt,
+		&i.ContentType,
+		&i.ParentDocumentID,
+		&i.DeletedAt,
+		&i.Revision,
+		&i.TextSimhash,
+		&i.ImageHash,
+		&i.EditVersion,
+		&i.ArchiveChecksum,
+		&i.DateConfidence,
+		&i.Title,
+		&i.Notes,
 		&i.SearchVector,
+		&i.DocumentTypeID,
 	)
 	return i, err
 }
@@ -14,17 +14,24 @@ import (
 )
 
 const createInbox = `-- name: CreateInbox :one
-INSERT INTO inboxes (path, name, error_path, duplicate_action, enabled)
-VALUES ($1, $2, $3, $4, $5)
-RETURNING id, path, name, enabled, error_path, duplicate_action, last_scan_at, last_error, created_at, updated_at
+INSERT INTO inboxes (
+    path, name, error_path, duplicate_action, enabled, recursive, folder_tags,
+    default_tags, default_correspondent, default_document_type
+) VALUES ($1, $2, $3, $4, $5, $6, $7, COALESCE($10::text[], '{}'), $8, $9)
+RETURNING id, path, name, enabled, error_path, duplicate_action, last_scan_at, last_error, created_at, updated_at, default_tags, default_correspondent, default_document_type, recursive, folder_tags
 `
 
 type CreateInboxParams struct {
-	Path            string          `json:"path"`
-	Name            string          `json:"name"`
-	ErrorPath       *string         `json:"error_path"`
-	DuplicateAction DuplicateAction `json:"duplicate_action"`
-	Enabled         bool            `json:"enabled"`
+	Path                 string          `json:"path"`
+	Name                 string          `json:"name"`
+	ErrorPath            *string         `json:"error_path"`
+	DuplicateAction      DuplicateAction `json:"duplicate_action"`
+	Enabled              bool            `json:"enabled"`
+	Recursive            bool            `json:"recursive"`
+	FolderTags           bool            `json:"folder_tags"`
+	DefaultCorrespondent *string         `json:"default_correspondent"`
+	DefaultDocumentType  *string         `json:"default_document_type"`
+	DefaultTags          []string        `json:"default_tags"`
 }
 
 func (q *Queries) CreateInbox(ctx context.Context, arg CreateInboxParams) (Inbox, error) {
@@ -34,6 +41,11 @@ func (q *Queries) CreateInbox(ctx context.Context, arg CreateInboxParams) (Inbox
 		arg.ErrorPath,
 		arg.DuplicateAction,
 		arg.Enabled,
+		arg.Recursive,
+		arg.FolderTags,
+		arg.DefaultCorrespondent,
+		arg.DefaultDocumentType,
+		arg.DefaultTags,
 	)
 	var i Inbox
 	err := row.Scan(
@@ -47,6 +59,11 @@ func (q *Queries) CreateInbox(ctx context.Context, arg CreateInboxParams) (Inbox
 		&i.LastError,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DefaultTags,
+		&i.DefaultCorrespondent,
+		&i.DefaultDocumentType,
+		&i.Recursive,
+		&i.FolderTags,
 	)
 	return i, err
 }
@@ -96,7 +113,7 @@ func (q *Queries) DeleteInbox(ctx context.Context, id uuid.UUID) error {
 }
 
 const getInbox = `-- name: GetInbox :one
-SELECT id, path, name, enabled, error_path, duplicate_action, last_scan_at, last_error, created_at, updated_at FROM inboxes WHERE id = $1
+SELECT id, path, name, enabled, error_path, duplicate_action, last_scan_at, last_error, created_at, updated_at, default_tags, default_correspondent, default_document_type, recursive, folder_tags FROM inboxes WHERE id = $1
 `
 
 func (q *Queries) GetInbox(ctx context.Context, id uuid.UUID) (Inbox, error) {
@@ -113,12 +130,17 @@ func (q *Queries) GetInbox(ctx context.Context, id uuid.UUID) (Inbox, error) {
 		&i.LastError,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DefaultTags,
+		&i.DefaultCorrespondent,
+		&i.DefaultDocumentType,
+		&i.Recursive,
+		&i.FolderTags,
 	)
 	return i, err
 }
 
 const getInboxByPath = `-- name: GetInboxByPath :one
-SELECT id, path, name, enabled, error_path, duplicate_action, last_scan_at, last_error, created_at, updated_at FROM inboxes WHERE path = $1
+SELECT id, path, name, enabled, error_path, duplicate_action, last_scan_at, last_error, created_at, updated_at, default_tags, default_correspondent, default_document_type, recursive, folder_tags FROM inboxes WHERE path = $1
 `
 
 func (q *Queries) GetInboxByPath(ctx context.Context, path string) (Inbox, error) {
@@ -135,12 +157,17 @@ func (q *Queries) GetInboxByPath(ctx context.Context, path string) (Inbox, error
 		&i.LastError,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DefaultTags,
+		&i.DefaultCorrespondent,
+		&i.DefaultDocumentType,
+		&i.Recursive,
+		&i.FolderTags,
 	)
 	return i, err
 }
 
 const listEnabledInboxes = `-- name: ListEnabledInboxes :many
-SELECT id, path, name, enabled, error_path, duplicate_action, last_scan_at, last_error, created_at, updated_at FROM inboxes WHERE enabled = true ORDER BY created_at ASC
+SELECT id, path, name, enabled, error_path, duplicate_action, last_scan_at, last_error, created_at, updated_at, default_tags, default_correspondent, default_document_type, recursive, folder_tags FROM inboxes WHERE enabled = true ORDER BY created_at ASC
 `
 
 func (q *Queries) ListEnabledInboxes(ctx context.Context) ([]Inbox, error) {
@@ -163,6 +190,11 @@ func (q *Queries) ListEnabledInboxes(ctx context.Context) ([]Inbox, error) {
 			&i.LastError,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DefaultTags,
+			&i.DefaultCorrespondent,
+			&i.DefaultDocumentType,
+			&i.Recursive,
+			&i.FolderTags,
 		); err != nil {
 			return nil, err
 		}
@@ -215,7 +247,7 @@ func (q *Queries) ListInboxEvents(ctx context.Context, arg ListInboxEventsParams
 }
 
 const listInboxes = `-- name: ListInboxes :many
-SELECT id, path, name, enabled, error_path, duplicate_action, last_scan_at, last_error, created_at, updated_at FROM inboxes ORDER BY created_at ASC
+SELECT id, path, name, enabled, error_path, duplicate_action, last_scan_at, last_error, created_at, updated_at, default_tags, default_correspondent, default_document_type, recursive, folder_tags FROM inboxes ORDER BY created_at ASC
 `
 
 func (q *Queries) ListInboxes(ctx context.Context) ([]Inbox, error) {
@@ -238,6 +270,11 @@ func (q *Queries) ListInboxes(ctx context.Context) ([]Inbox, error) {
 			&i.LastError,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DefaultTags,
+			&i.DefaultCorrespondent,
+			&i.DefaultDocumentType,
+			&i.Recursive,
+			&i.FolderTags,
 		); err != nil {
 			return nil, err
 		}
@@ -299,9 +336,10 @@ func (q *Queries) ListRecentInboxEvents(ctx context.Context, limit int64) ([]Lis
 
 const updateInbox = `-- name: UpdateInbox :one
 UPDATE inboxes
-SET name = $2, path = $3, error_path = $4, duplicate_action = $5, enabled = $6, updated_at = NOW()
+SET name = $2, path = $3, error_path = $4, duplicate_action = $5, enabled = $6,
+    recursive = $7, folder_tags = $8, updated_at = NOW()
 WHERE id = $1
-RETURNING id, path, name, enabled, error_path, duplicate_action, last_scan_at, last_error, created_at, updated_at
+RETURNING id, path, name, enabled, error_path, duplicate_action, last_scan_at, last_error, created_at, updated_at, default_tags, default_correspondent, default_document_type, recursive, folder_tags
 `
 
 type UpdateInboxParams struct {
@@ -311,6 +349,8 @@ type UpdateInboxParams struct {
 	ErrorPath       *string         `json:"error_path"`
 	DuplicateAction DuplicateAction `json:"duplicate_action"`
 	Enabled         bool            `json:"enabled"`
+	Recursive       bool            `json:"recursive"`
+	FolderTags      bool            `json:"folder_tags"`
 }
 
 func (q *Queries) UpdateInbox(ctx context.Context, arg UpdateInboxParams) (Inbox, error) {
@@ -321,6 +361,51 @@ func (q *Queries) UpdateInbox(ctx context.Context, arg UpdateInboxParams) (Inbox
 		arg.ErrorPath,
 		arg.DuplicateAction,
 		arg.Enabled,
+		arg.Recursive,
+		arg.FolderTags,
+	)
+	var i Inbox
+	err := row.Scan(
+		&i.ID,
+		&i.Path,
+		&i.Name,
+		&i.Enabled,
+		&i.ErrorPath,
+		&i.DuplicateAction,
+		&i.LastScanAt,
+		&i.LastError,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DefaultTags,
+		&i.DefaultCorrespondent,
+		&i.DefaultDocumentType,
+		&i.Recursive,
+		&i.FolderTags,
+	)
+	return i, err
+}
+
+const updateInboxDefaults = `-- name: UpdateInboxDefaults :one
+UPDATE inboxes
+SET default_tags = COALESCE($4::text[], '{}'), default_correspondent = $2,
+    default_document_type = $3, updated_at = NOW()
+WHERE id = $1
+RETURNING id, path, name, enabled, error_path, duplicate_action, last_scan_at, last_error, created_at, updated_at, default_tags, default_correspondent, default_document_type, recursive, folder_tags
+`
+
+type UpdateInboxDefaultsParams struct {
+	ID                   uuid.UUID `json:"id"`
+	DefaultCorrespondent *string   `json:"default_correspondent"`
+	DefaultDocumentType  *string   `json:"default_document_type"`
+	DefaultTags          []string  `json:"default_tags"`
+}
+
+func (q *Queries) UpdateInboxDefaults(ctx context.Context, arg UpdateInboxDefaultsParams) (Inbox, error) {
+	row := q.db.QueryRow(ctx, updateInboxDefaults,
+		arg.ID,
+		arg.DefaultCorrespondent,
+		arg.DefaultDocumentType,
+		arg.DefaultTags,
 	)
 	var i Inbox
 	err := row.Scan(
@@ -334,6 +419,11 @@ func (q *Queries) UpdateInbox(ctx context.Context, arg UpdateInboxParams) (Inbox
 		&i.LastError,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DefaultTags,
+		&i.DefaultCorrespondent,
+		&i.DefaultDocumentType,
+		&i.Recursive,
+		&i.FolderTags,
 	)
 	return i, err
 }
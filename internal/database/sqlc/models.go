@@ -13,6 +13,53 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type CustomFieldType string
+
+const (
+	CustomFieldTypeString       CustomFieldType = "string"
+	CustomFieldTypeNumber       CustomFieldType = "number"
+	CustomFieldTypeMonetary     CustomFieldType = "monetary"
+	CustomFieldTypeDate         CustomFieldType = "date"
+	CustomFieldTypeBoolean      CustomFieldType = "boolean"
+	CustomFieldTypeUrl          CustomFieldType = "url"
+	CustomFieldTypeDocumentLink CustomFieldType = "document_link"
+)
+
+func (e *CustomFieldType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = CustomFieldType(s)
+	case string:
+		*e = CustomFieldType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for CustomFieldType: %T", src)
+	}
+	return nil
+}
+
+type NullCustomFieldType struct {
+	CustomFieldType CustomFieldType `json:"custom_field_type"`
+	Valid           bool            `json:"valid"` // Valid is true if CustomFieldType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullCustomFieldType) Scan(value interface{}) error {
+	if value == nil {
+		ns.CustomFieldType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.CustomFieldType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullCustomFieldType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.CustomFieldType), nil
+}
+
 type DuplicateAction string
 
 const (
@@ -56,6 +103,49 @@ func (ns NullDuplicateAction) Value() (driver.Value, error) {
 	return string(ns.DuplicateAction), nil
 }
 
+type DuplicateStatus string
+
+const (
+	DuplicateStatusPending  DuplicateStatus = "pending"
+	DuplicateStatusMerged   DuplicateStatus = "merged"
+	DuplicateStatusDistinct DuplicateStatus = "distinct"
+)
+
+func (e *DuplicateStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = DuplicateStatus(s)
+	case string:
+		*e = DuplicateStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for DuplicateStatus: %T", src)
+	}
+	return nil
+}
+
+type NullDuplicateStatus struct {
+	DuplicateStatus DuplicateStatus `json:"duplicate_status"`
+	Valid           bool            `json:"valid"` // Valid is true if DuplicateStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullDuplicateStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.DuplicateStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.DuplicateStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullDuplicateStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.DuplicateStatus), nil
+}
+
 type JobStatus string
 
 const (
@@ -104,8 +194,9 @@ func (ns NullJobStatus) Value() (driver.Value, error) {
 type NetworkProtocol string
 
 const (
-	NetworkProtocolSmb NetworkProtocol = "smb"
-	NetworkProtocolNfs NetworkProtocol = "nfs"
+	NetworkProtocolSmb  NetworkProtocol = "smb"
+	NetworkProtocolNfs  NetworkProtocol = "nfs"
+	NetworkProtocolImap NetworkProtocol = "imap"
 )
 
 func (e *NetworkProtocol) Scan(src interface{}) error {
@@ -189,10 +280,11 @@ func (ns NullPostImportAction) Value() (driver.Value, error) {
 type ProcessingStatus string
 
 const (
-	ProcessingStatusPending    ProcessingStatus = "pending"
-	ProcessingStatusProcessing ProcessingStatus = "processing"
-	ProcessingStatusCompleted  ProcessingStatus = "completed"
-	ProcessingStatusFailed     ProcessingStatus = "failed"
+	ProcessingStatusPending       ProcessingStatus = "pending"
+	ProcessingStatusProcessing    ProcessingStatus = "processing"
+	ProcessingStatusCompleted     ProcessingStatus = "completed"
+	ProcessingStatusFailed        ProcessingStatus = "failed"
+	ProcessingStatusNeedsPassword ProcessingStatus = "needs_password"
 )
 
 func (e *ProcessingStatus) Scan(src interface{}) error {
@@ -279,6 +371,8 @@ type SuggestionType string
 const (
 	SuggestionTypeTag           SuggestionType = "tag"
 	SuggestionTypeCorrespondent SuggestionType = "correspondent"
+	SuggestionTypeDate          SuggestionType = "date"
+	SuggestionTypeDocumentType  SuggestionType = "document_type"
 )
 
 func (e *SuggestionType) Scan(src interface{}) error {
@@ -376,6 +470,13 @@ type Correspondent struct {
 	Notes     *string   `json:"notes"`
 }
 
+type CustomField struct {
+	ID        uuid.UUID       `json:"id"`
+	Name      string          `json:"name"`
+	FieldType CustomFieldType `json:"field_type"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
 type Document struct {
 	ID                 uuid.UUID          `json:"id"`
 	OriginalFilename   string             `json:"original_filename"`
@@ -393,7 +494,19 @@ type Document struct {
 	ThumbnailGenerated bool               `json:"thumbnail_generated"`
 	ProcessingError    *string            `json:"processing_error"`
 	ProcessedAt        pgtype.Timestamptz `json:"processed_at"`
+	ContentType        string             `json:"content_type"`
+	ParentDocumentID   pgtype.UUID        `json:"parent_document_id"`
+	DeletedAt          pgtype.Timestamptz `json:"deleted_at"`
+	Revision           int32              `json:"revision"`
+	TextSimhash        *int64             `json:"text_simhash"`
+	ImageHash          *int64             `json:"image_hash"`
+	EditVersion        int32              `json:"edit_version"`
+	ArchiveChecksum    *string            `json:"archive_checksum"`
+	DateConfidence     pgtype.Numeric     `json:"date_confidence"`
+	Title              *string            `json:"title"`
+	Notes              *string            `json:"notes"`
 	SearchVector       interface{}        `json:"search_vector"`
+	DocumentTypeID     pgtype.UUID        `json:"document_type_id"`
 }
 
 type DocumentCorrespondent struct {
@@ -401,6 +514,17 @@ type DocumentCorrespondent struct {
 	CorrespondentID uuid.UUID `json:"correspondent_id"`
 }
 
+type DocumentCustomField struct {
+	DocumentID      uuid.UUID      `json:"document_id"`
+	FieldID         uuid.UUID      `json:"field_id"`
+	ValueText       *string        `json:"value_text"`
+	ValueNumber     pgtype.Numeric `json:"value_number"`
+	ValueDate       pgtype.Date    `json:"value_date"`
+	ValueBool       *bool          `json:"value_bool"`
+	ValueDocumentID pgtype.UUID    `json:"value_document_id"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+}
+
 type DocumentEvent struct {
 	ID           uuid.UUID `json:"id"`
 	DocumentID   uuid.UUID `json:"document_id"`
@@ -411,11 +535,48 @@ type DocumentEvent struct {
 	CreatedAt    time.Time `json:"created_at"`
 }
 
+type DocumentExport struct {
+	DocumentID     uuid.UUID `json:"document_id"`
+	ExportPath     string    `json:"export_path"`
+	SourcePath     string    `json:"source_path"`
+	SourceRevision int32     `json:"source_revision"`
+	ExportedAt     time.Time `json:"exported_at"`
+}
+
+type DocumentRevision struct {
+	ID               uuid.UUID `json:"id"`
+	DocumentID       uuid.UUID `json:"document_id"`
+	Revision         int32     `json:"revision"`
+	OriginalFilename string    `json:"original_filename"`
+	ContentHash      string    `json:"content_hash"`
+	FileSize         int64     `json:"file_size"`
+	ContentType      string    `json:"content_type"`
+	CreatedAt        time.Time `json:"created_at"`
+	EditVersion      int32     `json:"edit_version"`
+}
+
 type DocumentTag struct {
 	DocumentID uuid.UUID `json:"document_id"`
 	TagID      uuid.UUID `json:"tag_id"`
 }
 
+type DocumentType struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type DuplicateCandidate struct {
+	ID            uuid.UUID          `json:"id"`
+	DocumentID    uuid.UUID          `json:"document_id"`
+	DuplicateOfID uuid.UUID          `json:"duplicate_of_id"`
+	TextDistance  *int32             `json:"text_distance"`
+	ImageDistance *int32             `json:"image_distance"`
+	Status        DuplicateStatus    `json:"status"`
+	CreatedAt     time.Time          `json:"created_at"`
+	ResolvedAt    pgtype.Timestamptz `json:"resolved_at"`
+}
+
 type Example struct {
 	ID          uuid.UUID          `json:"id"`
 	Name        string             `json:"name"`
@@ -425,16 +586,21 @@ type Example struct {
 }
 
 type Inbox struct {
-	ID              uuid.UUID          `json:"id"`
-	Path            string             `json:"path"`
-	Name            string             `json:"name"`
-	Enabled         bool               `json:"enabled"`
-	ErrorPath       *string            `json:"error_path"`
-	DuplicateAction DuplicateAction    `json:"duplicate_action"`
-	LastScanAt      pgtype.Timestamptz `json:"last_scan_at"`
-	LastError       *string            `json:"last_error"`
-	CreatedAt       time.Time          `json:"created_at"`
-	UpdatedAt       time.Time          `json:"updated_at"`
+	ID                   uuid.UUID          `json:"id"`
+	Path                 string             `json:"path"`
+	Name                 string             `json:"name"`
+	Enabled              bool               `json:"enabled"`
+	ErrorPath            *string            `json:"error_path"`
+	DuplicateAction      DuplicateAction    `json:"duplicate_action"`
+	LastScanAt           pgtype.Timestamptz `json:"last_scan_at"`
+	LastError            *string            `json:"last_error"`
+	CreatedAt            time.Time          `json:"created_at"`
+	UpdatedAt            time.Time          `json:"updated_at"`
+	DefaultTags          []string           `json:"default_tags"`
+	DefaultCorrespondent *string            `json:"default_correspondent"`
+	DefaultDocumentType  *string            `json:"default_document_type"`
+	Recursive            bool               `json:"recursive"`
+	FolderTags           bool               `json:"folder_tags"`
 }
 
 type InboxEvent struct {
@@ -447,6 +613,26 @@ type InboxEvent struct {
 	CreatedAt    time.Time   `json:"created_at"`
 }
 
+type IntegrityCheck struct {
+	ID               uuid.UUID          `json:"id"`
+	StartedAt        time.Time          `json:"started_at"`
+	FinishedAt       pgtype.Timestamptz `json:"finished_at"`
+	DocumentsChecked int32              `json:"documents_checked"`
+	FilesChecked     int32              `json:"files_checked"`
+	Error            *string            `json:"error"`
+}
+
+type IntegrityIssue struct {
+	ID         uuid.UUID   `json:"id"`
+	CheckID    uuid.UUID   `json:"check_id"`
+	Kind       string      `json:"kind"`
+	Category   string      `json:"category"`
+	StorageKey string      `json:"storage_key"`
+	DocumentID pgtype.UUID `json:"document_id"`
+	Detail     *string     `json:"detail"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
 type Job struct {
 	ID           uuid.UUID          `json:"id"`
 	QueueName    string             `json:"queue_name"`
@@ -466,26 +652,29 @@ type Job struct {
 }
 
 type NetworkSource struct {
-	ID                  uuid.UUID          `json:"id"`
-	Name                string             `json:"name"`
-	Protocol            NetworkProtocol    `json:"protocol"`
-	Host                string             `json:"host"`
-	SharePath           string             `json:"share_path"`
-	Username            *string            `json:"username"`
-	PasswordEncrypted   *string            `json:"password_encrypted"`
-	Enabled             bool               `json:"enabled"`
-	ContinuousSync      bool               `json:"continuous_sync"`
-	PostImportAction    PostImportAction   `json:"post_import_action"`
-	MoveSubfolder       *string            `json:"move_subfolder"`
-	DuplicateAction     DuplicateAction    `json:"duplicate_action"`
-	BatchSize           int32              `json:"batch_size"`
-	ConnectionState     *string            `json:"connection_state"`
-	ConsecutiveFailures int32              `json:"consecutive_failures"`
-	LastSyncAt          pgtype.Timestamptz `json:"last_sync_at"`
-	LastError           *string            `json:"last_error"`
-	FilesImported       int32              `json:"files_imported"`
-	CreatedAt           time.Time          `json:"created_at"`
-	UpdatedAt           time.Time          `json:"updated_at"`
+	ID                   uuid.UUID          `json:"id"`
+	Name                 string             `json:"name"`
+	Protocol             NetworkProtocol    `json:"protocol"`
+	Host                 string             `json:"host"`
+	SharePath            string             `json:"share_path"`
+	Username             *string            `json:"username"`
+	PasswordEncrypted    *string            `json:"password_encrypted"`
+	Enabled              bool               `json:"enabled"`
+	ContinuousSync       bool               `json:"continuous_sync"`
+	PostImportAction     PostImportAction   `json:"post_import_action"`
+	MoveSubfolder        *string            `json:"move_subfolder"`
+	DuplicateAction      DuplicateAction    `json:"duplicate_action"`
+	BatchSize            int32              `json:"batch_size"`
+	ConnectionState      *string            `json:"connection_state"`
+	ConsecutiveFailures  int32              `json:"consecutive_failures"`
+	LastSyncAt           pgtype.Timestamptz `json:"last_sync_at"`
+	LastError            *string            `json:"last_error"`
+	FilesImported        int32              `json:"files_imported"`
+	CreatedAt            time.Time          `json:"created_at"`
+	UpdatedAt            time.Time          `json:"updated_at"`
+	DefaultTags          []string           `json:"default_tags"`
+	DefaultCorrespondent *string            `json:"default_correspondent"`
+	DefaultDocumentType  *string            `json:"default_document_type"`
 }
 
 type NetworkSourceEvent struct {
@@ -499,6 +688,14 @@ type NetworkSourceEvent struct {
 	CreatedAt    time.Time   `json:"created_at"`
 }
 
+type PdfPassword struct {
+	ID                uuid.UUID          `json:"id"`
+	Label             string             `json:"label"`
+	PasswordEncrypted string             `json:"password_encrypted"`
+	LastUsedAt        pgtype.Timestamptz `json:"last_used_at"`
+	CreatedAt         time.Time          `json:"created_at"`
+}
+
 type Tag struct {
 	ID        uuid.UUID `json:"id"`
 	Name      string    `json:"name"`
@@ -16,24 +16,29 @@ const createNetworkSource = `-- name: CreateNetworkSource :one
 INSERT INTO network_sources (
     name, protocol, host, share_path, username, password_encrypted,
     enabled, continuous_sync, post_import_action, move_subfolder,
-    duplicate_action, batch_size
-) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
-RETURNING id, name, protocol, host, share_path, username, password_encrypted, enabled, continuous_sync, post_import_action, move_subfolder, duplicate_action, batch_size, connection_state, consecutive_failures, last_sync_at, last_error, files_imported, created_at, updated_at
+    duplicate_action, batch_size,
+    default_tags, default_correspondent, default_document_type
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12,
+    COALESCE($15::text[], '{}'), $13, $14)
+RETURNING id, name, protocol, host, share_path, username, password_encrypted, enabled, continuous_sync, post_import_action, move_subfolder, duplicate_action, batch_size, connection_state, consecutive_failures, last_sync_at, last_error, files_imported, created_at, updated_at, default_tags, default_correspondent, default_document_type
 `
 
 type CreateNetworkSourceParams struct {
-	Name              string           `json:"name"`
-	Protocol          NetworkProtocol  `json:"protocol"`
-	Host              string           `json:"host"`
-	SharePath         string           `json:"share_path"`
-	Username          *string          `json:"username"`
-	PasswordEncrypted *string          `json:"password_encrypted"`
-	Enabled           bool             `json:"enabled"`
-	ContinuousSync    bool             `json:"continuous_sync"`
-	PostImportAction  PostImportAction `json:"post_import_action"`
-	MoveSubfolder     *string          `json:"move_subfolder"`
-	DuplicateAction   DuplicateAction  `json:"duplicate_action"`
-	BatchSize         int32            `json:"batch_size"`
+	Name                 string           `json:"name"`
+	Protocol             NetworkProtocol  `json:"protocol"`
+	Host                 string           `json:"host"`
+	SharePath            string           `json:"share_path"`
+	Username             *string          `json:"username"`
+	PasswordEncrypted    *string          `json:"password_encrypted"`
+	Enabled              bool             `json:"enabled"`
+	ContinuousSync       bool             `json:"continuous_sync"`
+	PostImportAction     PostImportAction `json:"post_import_action"`
+	MoveSubfolder        *string          `json:"move_subfolder"`
+	DuplicateAction      DuplicateAction  `json:"duplicate_action"`
+	BatchSize            int32            `json:"batch_size"`
+	DefaultCorrespondent *string          `json:"default_correspondent"`
+	DefaultDocumentType  *string          `json:"default_document_type"`
+	DefaultTags          []string         `json:"default_tags"`
 }
 
 func (q *Queries) CreateNetworkSource(ctx context.Context, arg CreateNetworkSourceParams) (NetworkSource, error) {
@@ -50,6 +55,9 @@ func (q *Queries) CreateNetworkSource(ctx context.Context, arg CreateNetworkSour
 		arg.MoveSubfolder,
 		arg.DuplicateAction,
 		arg.BatchSize,
+		arg.DefaultCorrespondent,
+		arg.DefaultDocumentType,
+		arg.DefaultTags,
 	)
 	var i NetworkSource
 	err := row.Scan(
@@ -73,6 +81,9 @@ func (q *Queries) CreateNetworkSource(ctx context.Context, arg CreateNetworkSour
 		&i.FilesImported,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DefaultTags,
+		&i.DefaultCorrespondent,
+		&i.DefaultDocumentType,
 	)
 	return i, err
 }
@@ -135,7 +146,7 @@ func (q *Queries) DisableNetworkSource(ctx context.Context, id uuid.UUID) error
 }
 
 const getNetworkSource = `-- name: GetNetworkSource :one
-SELECT id, name, protocol, host, share_path, username, password_encrypted, enabled, continuous_sync, post_import_action, move_subfolder, duplicate_action, batch_size, connection_state, consecutive_failures, last_sync_at, last_error, files_imported, created_at, updated_at FROM network_sources WHERE id = $1
+SELECT id, name, protocol, host, share_path, username, password_encrypted, enabled, continuous_sync, post_import_action, move_subfolder, duplicate_action, batch_size, connection_state, consecutive_failures, last_sync_at, last_error, files_imported, created_at, updated_at, default_tags, default_correspondent, default_document_type FROM network_sources WHERE id = $1
 `
 
 func (q *Queries) GetNetworkSource(ctx context.Context, id uuid.UUID) (NetworkSource, error) {
@@ -162,6 +173,9 @@ func (q *Queries) GetNetworkSource(ctx context.Context, id uuid.UUID) (NetworkSo
 		&i.FilesImported,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DefaultTags,
+		&i.DefaultCorrespondent,
+		&i.DefaultDocumentType,
 	)
 	return i, err
 }
@@ -192,7 +206,7 @@ func (q *Queries) IncrementFilesImported(ctx context.Context, id uuid.UUID) erro
 }
 
 const listContinuousSyncSources = `-- name: ListContinuousSyncSources :many
-SELECT id, name, protocol, host, share_path, username, password_encrypted, enabled, continuous_sync, post_import_action, move_subfolder, duplicate_action, batch_size, connection_state, consecutive_failures, last_sync_at, last_error, files_imported, created_at, updated_at FROM network_sources
+SELECT id, name, protocol, host, share_path, username, password_encrypted, enabled, continuous_sync, post_import_action, move_subfolder, duplicate_action, batch_size, connection_state, consecutive_failures, last_sync_at, last_error, files_imported, created_at, updated_at, default_tags, default_correspondent, default_document_type FROM network_sources
 WHERE enabled = true AND continuous_sync = true
 ORDER BY created_at ASC
 `
@@ -227,6 +241,9 @@ func (q *Queries) ListContinuousSyncSources(ctx context.Context) ([]NetworkSourc
 			&i.FilesImported,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DefaultTags,
+			&i.DefaultCorrespondent,
+			&i.DefaultDocumentType,
 		); err != nil {
 			return nil, err
 		}
@@ -239,7 +256,7 @@ func (q *Queries) ListContinuousSyncSources(ctx context.Context) ([]NetworkSourc
 }
 
 const listEnabledNetworkSources = `-- name: ListEnabledNetworkSources :many
-SELECT id, name, protocol, host, share_path, username, password_encrypted, enabled, continuous_sync, post_import_action, move_subfolder, duplicate_action, batch_size, connection_state, consecutive_failures, last_sync_at, last_error, files_imported, created_at, updated_at FROM network_sources WHERE enabled = true ORDER BY created_at ASC
+SELECT id, name, protocol, host, share_path, username, password_encrypted, enabled, continuous_sync, post_import_action, move_subfolder, duplicate_action, batch_size, connection_state, consecutive_failures, last_sync_at, last_error, files_imported, created_at, updated_at, default_tags, default_correspondent, default_document_type FROM network_sources WHERE enabled = true ORDER BY created_at ASC
 `
 
 func (q *Queries) ListEnabledNetworkSources(ctx context.Context) ([]NetworkSource, error) {
@@ -272,6 +289,9 @@ func (q *Queries) ListEnabledNetworkSources(ctx context.Context) ([]NetworkSourc
 			&i.FilesImported,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DefaultTags,
+			&i.DefaultCorrespondent,
+			&i.DefaultDocumentType,
 		); err != nil {
 			return nil, err
 		}
@@ -325,7 +345,7 @@ func (q *Queries) ListNetworkSourceEvents(ctx context.Context, arg ListNetworkSo
 }
 
 const listNetworkSources = `-- name: ListNetworkSources :many
-SELECT id, name, protocol, host, share_path, username, password_encrypted, enabled, continuous_sync, post_import_action, move_subfolder, duplicate_action, batch_size, connection_state, consecutive_failures, last_sync_at, last_error, files_imported, created_at, updated_at FROM network_sources ORDER BY created_at ASC
+SELECT id, name, protocol, host, share_path, username, password_encrypted, enabled, continuous_sync, post_import_action, move_subfolder, duplicate_action, batch_size, connection_state, consecutive_failures, last_sync_at, last_error, files_imported, created_at, updated_at, default_tags, default_correspondent, default_document_type FROM network_sources ORDER BY created_at ASC
 `
 
 func (q *Queries) ListNetworkSources(ctx context.Context) ([]NetworkSource, error) {
@@ -358,6 +378,9 @@ func (q *Queries) ListNetworkSources(ctx context.Context) ([]NetworkSource, erro
 			&i.FilesImported,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.DefaultTags,
+			&i.DefaultCorrespondent,
+			&i.DefaultDocumentType,
 		); err != nil {
 			return nil, err
 		}
@@ -387,7 +410,7 @@ UPDATE network_sources SET
     continuous_sync = $9, post_import_action = $10, move_subfolder = $11,
     duplicate_action = $12, batch_size = $13, updated_at = NOW()
 WHERE id = $1
-RETURNING id, name, protocol, host, share_path, username, password_encrypted, enabled, continuous_sync, post_import_action, move_subfolder, duplicate_action, batch_size, connection_state, consecutive_failures, last_sync_at, last_error, files_imported, created_at, updated_at
+RETURNING id, name, protocol, host, share_path, username, password_encrypted, enabled, continuous_sync, post_import_action, move_subfolder, duplicate_action, batch_size, connection_state, consecutive_failures, last_sync_at, last_error, files_imported, created_at, updated_at, default_tags, default_correspondent, default_document_type
 `
 
 type UpdateNetworkSourceParams struct {
@@ -444,6 +467,60 @@ func (q *Queries) UpdateNetworkSource(ctx context.Context, arg UpdateNetworkSour
 		&i.FilesImported,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DefaultTags,
+		&i.DefaultCorrespondent,
+		&i.DefaultDocumentType,
+	)
+	return i, err
+}
+
+const updateNetworkSourceDefaults = `-- name: UpdateNetworkSourceDefaults :one
+UPDATE network_sources SET
+    default_tags = COALESCE($4::text[], '{}'), default_correspondent = $2,
+    default_document_type = $3, updated_at = NOW()
+WHERE id = $1
+RETURNING id, name, protocol, host, share_path, username, password_encrypted, enabled, continuous_sync, post_import_action, move_subfolder, duplicate_action, batch_size, connection_state, consecutive_failures, last_sync_at, last_error, files_imported, created_at, updated_at, default_tags, default_correspondent, default_document_type
+`
+
+type UpdateNetworkSourceDefaultsParams struct {
+	ID                   uuid.UUID `json:"id"`
+	DefaultCorrespondent *string   `json:"default_correspondent"`
+	DefaultDocumentType  *string   `json:"default_document_type"`
+	DefaultTags          []string  `json:"default_tags"`
+}
+
+func (q *Queries) UpdateNetworkSourceDefaults(ctx context.Context, arg UpdateNetworkSourceDefaultsParams) (NetworkSource, error) {
+	row := q.db.QueryRow(ctx, updateNetworkSourceDefaults,
+		arg.ID,
+		arg.DefaultCorrespondent,
+		arg.DefaultDocumentType,
+		arg.DefaultTags,
+	)
+	var i NetworkSource
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Protocol,
+		&i.Host,
+		&i.SharePath,
+		&i.Username,
+		&i.PasswordEncrypted,
+		&i.Enabled,
+		&i.ContinuousSync,
+		&i.PostImportAction,
+		&i.MoveSubfolder,
+		&i.DuplicateAction,
+		&i.BatchSize,
+		&i.ConnectionState,
+		&i.ConsecutiveFailures,
+		&i.LastSyncAt,
+		&i.LastError,
+		&i.FilesImported,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DefaultTags,
+		&i.DefaultCorrespondent,
+		&i.DefaultDocumentType,
 	)
 	return i, err
 }
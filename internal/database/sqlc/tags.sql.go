@@ -109,6 +109,24 @@ func (q *Queries) GetTag(ctx context.Context, id uuid.UUID) (Tag, error) {
 	return i, err
 }
 
+const getTagByName = `-- name: GetTagByName :one
+SELECT id, name, color, created_at FROM tags
+WHERE lower(name) = lower($1)
+LIMIT 1
+`
+
+func (q *Queries) GetTagByName(ctx context.Context, lower string) (Tag, error) {
+	row := q.db.QueryRow(ctx, getTagByName, lower)
+	var i Tag
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Color,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const getTagsForDocuments = `-- name: GetTagsForDocuments :many
 SELECT dt.document_id, t.id, t.name, t.color, t.created_at
 FROM document_tags dt
@@ -152,9 +170,10 @@ func (q *Queries) GetTagsForDocuments(ctx context.Context, dollar_1 []uuid.UUID)
 }
 
 const listTagsWithCounts = `-- name: ListTagsWithCounts :many
-SELECT t.id, t.name, t.color, t.created_at, COUNT(dt.document_id)::int AS document_count
+SELECT t.id, t.name, t.color, t.created_at, COUNT(d.id)::int AS document_count
 FROM tags t
 LEFT JOIN document_tags dt ON t.id = dt.tag_id
+LEFT JOIN documents d ON d.id = dt.document_id AND d.deleted_at IS NULL
 GROUP BY t.id
 ORDER BY t.name
 `
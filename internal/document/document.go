@@ -3,28 +3,48 @@ package document
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 
 	"github.com/bketelsen/docko/internal/database"
 	"github.com/bketelsen/docko/internal/database/sqlc"
+	"github.com/bketelsen/docko/internal/email"
+	"github.com/bketelsen/docko/internal/pdf"
 	"github.com/bketelsen/docko/internal/queue"
 	"github.com/bketelsen/docko/internal/storage"
 )
 
 // Event types for audit trail
 const (
-	EventIngested           = "ingested"
-	EventHashed             = "hashed"
-	EventDuplicateFound     = "duplicate_found"
-	EventTextExtracted      = "text_extracted"
-	EventThumbnailGenerated = "thumbnail_generated"
-	EventFailed             = "failed"
+	EventIngested            = "ingested"
+	EventHashed              = "hashed"
+	EventDuplicateFound      = "duplicate_found"
+	EventTextExtracted       = "text_extracted"
+	EventThumbnailGenerated  = "thumbnail_generated"
+	EventConverted           = "converted"
+	EventAttachmentExtracted = "attachment_extracted"
+	EventTrashed             = "trashed"
+	EventRestored            = "restored"
+	EventRevised             = "revised"
+	EventNearDuplicateFound  = "near_duplicate_found"
+	EventMerged              = "merged"
+	EventSplit               = "split"
+	EventSplitFrom           = "split_from"
+	EventPagesEdited         = "pages_edited"
+	EventNeedsPassword       = "needs_password"
+	EventUnlocked            = "unlocked"
+	EventFailed              = "failed"
 )
 
 // Queue names
@@ -34,12 +54,23 @@ const (
 
 // Job types
 const (
-	JobTypeProcess = "process_document"
+	JobTypeProcess   = "process_document"
+	JobTypeConvert   = "convert_document"
+	JobTypeThumbnail = "generate_thumbnail"
 )
 
+// ErrUnsupportedType is returned when a file is neither a PDF nor a supported image
+var ErrUnsupportedType = errors.New("unsupported file type")
+
 // IngestPayload is the job payload for document processing
 type IngestPayload struct {
 	DocumentID uuid.UUID `json:"document_id"`
+	// Text imported with the document; processing uses it instead of
+	// extracting or OCR'ing the PDF
+	Text string `json:"text,omitempty"`
+	// Set on a new upload's first processing job only, so batch scan
+	// splitting never touches documents going through processing again
+	Split bool `json:"split,omitempty"`
 }
 
 // Service handles document operations
@@ -47,6 +78,8 @@ type Service struct {
 	db      *database.DB
 	storage *storage.Storage
 	queue   *queue.Queue
+
+	integrityMu sync.Mutex // Held while an integrity check runs
 }
 
 // New creates a new document Service
@@ -58,26 +91,182 @@ func New(db *database.DB, storage *storage.Storage, queue *queue.Queue) *Service
 	}
 }
 
+// IngestOptions carries metadata applied in the same transaction that creates the document
+type IngestOptions struct {
+	ParentID          *uuid.UUID // Document this one was extracted from
+	DocumentDate      *time.Time // Overrides the default document date (ingest time)
+	CorrespondentName string     // Assigned correspondent, created if it doesn't exist
+	TagNames          []string   // Assigned tags, created if they don't exist
+	DocumentTypeName  string     // Assigned document type, created if it doesn't exist
+	Title             string
+	Notes             string
+
+	// Text already extracted by another archive, used instead of extracting
+	// or OCR'ing it again. ArchivePath optionally names its searchable PDF,
+	// kept as the archive version.
+	Text        string
+	ArchivePath string
+
+	Source *IngestSource // Inbox or network source the document arrived from
+}
+
+// Ingest sources
+const (
+	SourceInbox   = "inbox"
+	SourceNetwork = "network_source"
+)
+
+// IngestSource is the inbox or network source a document arrived from, with
+// the metadata it assigns to everything it imports
+// The defaults are applied in the ingest transaction and recorded with the
+// source in the ingested event.
+type IngestSource struct {
+	Type              string // SourceInbox or SourceNetwork
+	ID                uuid.UUID
+	Name              string
+	TagNames          []string
+	CorrespondentName string
+	DocumentTypeName  string
+}
+
+// applySource adds the source's defaults to opts and returns what was applied,
+// for the ingested event
+// A correspondent or document type already in opts takes precedence.
+func (o *IngestOptions) applySource() map[string]any {
+	src := o.Source
+	if src == nil {
+		return nil
+	}
+	applied := map[string]any{
+		"type": src.Type,
+		"id":   src.ID.String(),
+		"name": src.Name,
+	}
+	if len(src.TagNames) > 0 {
+		tags := slices.Clone(o.TagNames)
+		for _, name := range src.TagNames {
+			// Attachments inherit tags the source already added to their message
+			if !slices.ContainsFunc(tags, func(t string) bool { return strings.EqualFold(t, name) }) {
+				tags = append(tags, name)
+			}
+		}
+		o.TagNames = tags
+		applied["tags"] = src.TagNames
+	}
+	if src.CorrespondentName != "" && o.CorrespondentName == "" {
+		o.CorrespondentName = src.CorrespondentName
+		applied["correspondent"] = src.CorrespondentName
+	}
+	if src.DocumentTypeName != "" && o.DocumentTypeName == "" {
+		o.DocumentTypeName = src.DocumentTypeName
+		applied["document_type"] = src.DocumentTypeName
+	}
+	return applied
+}
+
+// ChildOptions returns ingest options for a document carved out of doc: it
+// links back to doc and keeps doc's tags, correspondent and document type,
+// including any an inbox or network source assigned when doc arrived
+func (s *Service) ChildOptions(ctx context.Context, doc *sqlc.Document) (IngestOptions, error) {
+	opts := IngestOptions{ParentID: &doc.ID}
+
+	tags, err := s.db.Queries.GetDocumentTags(ctx, doc.ID)
+	if err != nil {
+		return opts, fmt.Errorf("get tags: %w", err)
+	}
+	for _, tag := range tags {
+		opts.TagNames = append(opts.TagNames, tag.Name)
+	}
+
+	corr, err := s.db.Queries.GetDocumentCorrespondent(ctx, doc.ID)
+	switch {
+	case err == nil:
+		opts.CorrespondentName = corr.Name
+	case !errors.Is(err, pgx.ErrNoRows):
+		return opts, fmt.Errorf("get correspondent: %w", err)
+	}
+
+	if doc.DocumentTypeID.Valid {
+		docType, err := s.db.Queries.GetDocumentType(ctx, doc.DocumentTypeID.Bytes)
+		if err != nil {
+			return opts, fmt.Errorf("get document type: %w", err)
+		}
+		opts.DocumentTypeName = docType.Name
+	}
+	return opts, nil
+}
+
 // Ingest stores a new document from a source file path
+// Images are kept as the stored original and converted to a PDF rendition.
+// Office documents and emails are kept as the original and queued for conversion.
 // Returns the document ID, or existing document ID if duplicate
 func (s *Service) Ingest(ctx context.Context, sourcePath, originalFilename string) (*sqlc.Document, bool, error) {
+	return s.IngestWithOptions(ctx, sourcePath, originalFilename, IngestOptions{})
+}
+
+// IngestWithOptions stores a new document like Ingest, applying opts
+// Emails fill in opts from their headers and ingest PDF attachments as
+// child documents; mbox files ingest each message in turn and return the first.
+func (s *Service) IngestWithOptions(ctx context.Context, sourcePath, originalFilename string, opts IngestOptions) (*sqlc.Document, bool, error) {
 	start := time.Now()
 	docID := uuid.New()
 
-	// Compute destination path
-	destPath := s.storage.PathForUUID(storage.CategoryOriginals, docID, filepath.Ext(originalFilename))
+	// Compute destination keys
+	destKey := storage.KeyForUUID(storage.CategoryOriginals, docID, filepath.Ext(originalFilename))
+	renditionKey := storage.KeyForUUID(storage.CategoryRenditions, docID, ".pdf")
+	archiveKey := storage.KeyForUUID(storage.CategoryArchive, docID, ".pdf")
+	cleanup := func() {
+		_ = s.storage.Delete(ctx, destKey)
+		_ = s.storage.Delete(ctx, renditionKey)
+		_ = s.storage.Delete(ctx, archiveKey)
+	}
 
-	// Copy file and compute hash in single pass
-	contentHash, fileSize, err := s.storage.CopyAndHash(destPath, sourcePath)
+	// Store file and compute hash in single pass
+	contentHash, fileSize, err := s.storage.StoreAndHash(ctx, destKey, sourcePath)
 	if err != nil {
-		return nil, false, fmt.Errorf("copy and hash file: %w", err)
+		return nil, false, fmt.Errorf("store and hash file: %w", err)
+	}
+
+	contentType, err := DetectContentType(sourcePath)
+	if err != nil {
+		cleanup()
+		return nil, false, fmt.Errorf("detect content type: %w", err)
+	}
+	if contentType == "" {
+		cleanup()
+		return nil, false, ErrUnsupportedType
+	}
+
+	// Mailboxes aren't stored themselves, only the messages in them
+	if contentType == ContentTypeMbox {
+		cleanup()
+		return s.ingestMbox(ctx, sourcePath, originalFilename, opts)
+	}
+
+	// Source defaults win over what an email's headers suggest
+	source := opts.applySource()
+
+	// Emails pre-fill the correspondent and document date from their headers
+	var msg *email.Message
+	if contentType == ContentTypeEML {
+		msg, err = email.ParseFile(sourcePath)
+		if err != nil {
+			cleanup()
+			return nil, false, fmt.Errorf("parse email: %w", err)
+		}
+		if opts.CorrespondentName == "" {
+			opts.CorrespondentName = msg.From
+		}
+		if opts.DocumentDate == nil && !msg.Date.IsZero() {
+			opts.DocumentDate = &msg.Date
+		}
 	}
 
 	// Check for duplicate
 	existing, err := s.db.Queries.GetDocumentByHash(ctx, contentHash)
 	if err == nil {
 		// Duplicate found - clean up copied file and return existing
-		_ = s.storage.Delete(destPath)
+		cleanup()
 		slog.Info("duplicate document detected", "existing_id", existing.ID, "hash", contentHash[:16]+"...")
 
 		// Log duplicate event on existing document
@@ -86,44 +275,150 @@ func (s *Service) Ingest(ctx context.Context, sourcePath, originalFilename strin
 			"source_path":        sourcePath,
 		}, nil, time.Since(start))
 
+		// Importing a trashed document again brings it back
+		if existing.DeletedAt.Valid {
+			restored, err := s.Restore(ctx, existing.ID)
+			if err != nil {
+				return nil, false, fmt.Errorf("restore trashed duplicate: %w", err)
+			}
+			existing = *restored
+		}
+
 		return &existing, true, nil
 	}
 	if err != pgx.ErrNoRows {
 		// Unexpected error
-		_ = s.storage.Delete(destPath)
+		cleanup()
 		return nil, false, fmt.Errorf("check duplicate: %w", err)
 	}
 
+	// Images get a PDF rendition that processing and the viewer work from
+	if IsImage(contentType) {
+		if err := s.storeImageRendition(ctx, renditionKey, contentType, sourcePath); err != nil {
+			cleanup()
+			return nil, false, err
+		}
+	}
+
+	// An imported searchable PDF stands in for the one OCR would produce
+	var archiveChecksum *string
+	if opts.Text != "" && opts.ArchivePath != "" {
+		checksum, _, err := s.storage.StoreAndHash(ctx, archiveKey, opts.ArchivePath)
+		if err != nil {
+			cleanup()
+			return nil, false, fmt.Errorf("store archive: %w", err)
+		}
+		archiveChecksum = &checksum
+	}
+
 	// Start transaction for document + job creation
 	tx, err := s.db.Pool.Begin(ctx)
 	if err != nil {
-		_ = s.storage.Delete(destPath)
+		cleanup()
 		return nil, false, fmt.Errorf("begin transaction: %w", err)
 	}
 	defer func() { _ = tx.Rollback(ctx) }()
 
 	qtx := s.db.Queries.WithTx(tx)
 
-	// Create document record
-	doc, err := qtx.CreateDocument(ctx, sqlc.CreateDocumentParams{
+	params := sqlc.CreateDocumentParams{
 		ID:               docID,
 		OriginalFilename: originalFilename,
 		ContentHash:      contentHash,
 		FileSize:         fileSize,
+		ContentType:      contentType,
 		// page_count, pdf_title, pdf_author, pdf_created_at filled by processing job
-	})
+	}
+	if opts.DocumentDate != nil {
+		params.DocumentDate = pgtype.Timestamptz{Time: *opts.DocumentDate, Valid: true}
+		// A date from the source is trusted over one detected in the text
+		_ = params.DateConfidence.Scan("1")
+	}
+	if opts.ParentID != nil {
+		params.ParentDocumentID = pgtype.UUID{Bytes: *opts.ParentID, Valid: true}
+	}
+
+	// Create document record
+	doc, err := qtx.CreateDocument(ctx, params)
 	if err != nil {
-		_ = s.storage.Delete(destPath)
+		cleanup()
 		return nil, false, fmt.Errorf("create document: %w", err)
 	}
 
+	// Assign correspondent by name, creating it on first sight
+	if opts.CorrespondentName != "" {
+		if err := assignCorrespondent(ctx, qtx, doc.ID, opts.CorrespondentName); err != nil {
+			cleanup()
+			return nil, false, err
+		}
+	}
+
+	// Assign tags by name, creating them on first sight
+	for _, name := range lockOrder(opts.TagNames) {
+		if err := assignTag(ctx, qtx, doc.ID, name); err != nil {
+			cleanup()
+			return nil, false, err
+		}
+	}
+
+	if opts.DocumentTypeName != "" {
+		if err := assignDocumentType(ctx, qtx, doc.ID, opts.DocumentTypeName); err != nil {
+			cleanup()
+			return nil, false, err
+		}
+	}
+
+	if opts.Title != "" || opts.Notes != "" {
+		doc, err = qtx.UpdateDocumentDetails(ctx, sqlc.UpdateDocumentDetailsParams{
+			ID:    doc.ID,
+			Title: nilIfEmpty(opts.Title),
+			Notes: nilIfEmpty(opts.Notes),
+		})
+		if err != nil {
+			cleanup()
+			return nil, false, fmt.Errorf("set details: %w", err)
+		}
+	}
+
+	if archiveChecksum != nil {
+		if err := qtx.SetDocumentArchive(ctx, sqlc.SetDocumentArchiveParams{
+			ID:              doc.ID,
+			ArchiveChecksum: archiveChecksum,
+		}); err != nil {
+			cleanup()
+			return nil, false, fmt.Errorf("set archive: %w", err)
+		}
+		doc.ArchiveChecksum = archiveChecksum
+	}
+
 	// Log ingested event
-	eventPayload, _ := json.Marshal(map[string]any{
-		"source_path": sourcePath,
-		"dest_path":   destPath,
-		"file_size":   fileSize,
-		"hash":        contentHash,
-	})
+	ingestedPayload := map[string]any{
+		"source_path":  sourcePath,
+		"dest_path":    destKey,
+		"file_size":    fileSize,
+		"hash":         contentHash,
+		"content_type": contentType,
+	}
+	if opts.ParentID != nil {
+		ingestedPayload["parent_id"] = opts.ParentID.String()
+	}
+	if opts.CorrespondentName != "" {
+		ingestedPayload["correspondent"] = opts.CorrespondentName
+	}
+	if len(opts.TagNames) > 0 {
+		ingestedPayload["tags"] = opts.TagNames
+	}
+	if opts.DocumentTypeName != "" {
+		ingestedPayload["document_type"] = opts.DocumentTypeName
+	}
+	if source != nil {
+		ingestedPayload["source"] = source
+	}
+	if opts.Text != "" {
+		ingestedPayload["imported_text"] = true
+		ingestedPayload["imported_archive"] = archiveChecksum != nil
+	}
+	eventPayload, _ := json.Marshal(ingestedPayload)
 	_, err = qtx.CreateDocumentEvent(ctx, sqlc.CreateDocumentEventParams{
 		DocumentID:   doc.ID,
 		EventType:    EventIngested,
@@ -131,29 +426,283 @@ func (s *Service) Ingest(ctx context.Context, sourcePath, originalFilename strin
 		DurationMs:   intPtr(int32(time.Since(start).Milliseconds())),
 	})
 	if err != nil {
-		_ = s.storage.Delete(destPath)
+		cleanup()
 		return nil, false, fmt.Errorf("create event: %w", err)
 	}
 
-	// Enqueue processing job
-	_, err = s.queue.EnqueueTx(ctx, qtx, QueueDefault, JobTypeProcess, IngestPayload{
+	// Enqueue processing job, converting office documents and emails to PDF first
+	jobType := JobTypeProcess
+	if NeedsConversion(contentType) {
+		jobType = JobTypeConvert
+	}
+	_, err = s.queue.EnqueueTx(ctx, qtx, QueueDefault, jobType, IngestPayload{
 		DocumentID: doc.ID,
+		Text:       opts.Text,
+		Split:      true,
 	})
 	if err != nil {
-		_ = s.storage.Delete(destPath)
+		cleanup()
 		return nil, false, fmt.Errorf("enqueue job: %w", err)
 	}
 
 	if err := tx.Commit(ctx); err != nil {
-		_ = s.storage.Delete(destPath)
+		cleanup()
 		return nil, false, fmt.Errorf("commit transaction: %w", err)
 	}
 
-	slog.Info("document ingested", "id", doc.ID, "filename", originalFilename, "size", fileSize, "hash", contentHash[:16]+"...")
+	slog.Info("document ingested", "id", doc.ID, "filename", originalFilename, "type", contentType, "size", fileSize, "hash", contentHash[:16]+"...")
+
+	if msg != nil {
+		s.ingestAttachments(ctx, &doc, msg, opts)
+	}
 
 	return &doc, false, nil
 }
 
+// Lock kinds for LockName
+const (
+	lockCorrespondent = 1
+	lockTag           = 2
+	lockDocumentType  = 3
+)
+
+// assignCorrespondent links a document to the named correspondent, creating it if needed
+func assignCorrespondent(ctx context.Context, qtx *sqlc.Queries, docID uuid.UUID, name string) error {
+	// Concurrent ingests would otherwise each create the correspondent
+	if err := qtx.LockName(ctx, sqlc.LockNameParams{Kind: lockCorrespondent, Name: name}); err != nil {
+		return fmt.Errorf("lock correspondent: %w", err)
+	}
+
+	var correspondentID uuid.UUID
+	existing, err := qtx.GetCorrespondentByName(ctx, name)
+	switch {
+	case err == nil:
+		correspondentID = existing.ID
+	case errors.Is(err, pgx.ErrNoRows):
+		created, err := qtx.CreateCorrespondent(ctx, sqlc.CreateCorrespondentParams{Name: name})
+		if err != nil {
+			return fmt.Errorf("create correspondent: %w", err)
+		}
+		correspondentID = created.ID
+	default:
+		return fmt.Errorf("get correspondent: %w", err)
+	}
+
+	if err := qtx.SetDocumentCorrespondent(ctx, sqlc.SetDocumentCorrespondentParams{
+		DocumentID:      docID,
+		CorrespondentID: correspondentID,
+	}); err != nil {
+		return fmt.Errorf("set correspondent: %w", err)
+	}
+	return nil
+}
+
+// lockOrder sorts and dedupes names case-insensitively, keeping the first
+// spelling of each. Concurrent ingests taking name locks in this order can't
+// deadlock on each other, as a/b/x.pdf and b/a/y.pdf would in folder order.
+func lockOrder(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	var ordered []string
+	for _, name := range names {
+		key := strings.ToLower(name)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		ordered = append(ordered, name)
+	}
+	slices.SortStableFunc(ordered, func(a, b string) int {
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	})
+	return ordered
+}
+
+// assignTag adds the named tag to a document, creating the tag if needed
+func assignTag(ctx context.Context, qtx *sqlc.Queries, docID uuid.UUID, name string) error {
+	if err := qtx.LockName(ctx, sqlc.LockNameParams{Kind: lockTag, Name: name}); err != nil {
+		return fmt.Errorf("lock tag: %w", err)
+	}
+
+	var tagID uuid.UUID
+	existing, err := qtx.GetTagByName(ctx, name)
+	switch {
+	case err == nil:
+		tagID = existing.ID
+	case errors.Is(err, pgx.ErrNoRows):
+		created, err := qtx.CreateTag(ctx, sqlc.CreateTagParams{Name: name})
+		if err != nil {
+			return fmt.Errorf("create tag: %w", err)
+		}
+		tagID = created.ID
+	default:
+		return fmt.Errorf("get tag: %w", err)
+	}
+
+	if err := qtx.AddDocumentTag(ctx, sqlc.AddDocumentTagParams{
+		DocumentID: docID,
+		TagID:      tagID,
+	}); err != nil {
+		return fmt.Errorf("add tag: %w", err)
+	}
+	return nil
+}
+
+// assignDocumentType sets a document's type by name, creating the type if needed
+func assignDocumentType(ctx context.Context, qtx *sqlc.Queries, docID uuid.UUID, name string) error {
+	if err := qtx.LockName(ctx, sqlc.LockNameParams{Kind: lockDocumentType, Name: name}); err != nil {
+		return fmt.Errorf("lock document type: %w", err)
+	}
+
+	var typeID uuid.UUID
+	existing, err := qtx.GetDocumentTypeByName(ctx, name)
+	switch {
+	case err == nil:
+		typeID = existing.ID
+	case errors.Is(err, pgx.ErrNoRows):
+		created, err := qtx.CreateDocumentType(ctx, name)
+		if err != nil {
+			return fmt.Errorf("create document type: %w", err)
+		}
+		typeID = created.ID
+	default:
+		return fmt.Errorf("get document type: %w", err)
+	}
+
+	if err := qtx.SetDocumentType(ctx, sqlc.SetDocumentTypeParams{
+		ID:             docID,
+		DocumentTypeID: pgtype.UUID{Bytes: typeID, Valid: true},
+	}); err != nil {
+		return fmt.Errorf("set document type: %w", err)
+	}
+	return nil
+}
+
+// FolderTags returns the folder names between root and the file at path,
+// for tagging documents by the folders they were filed in
+// inbox/medical/2024/scan.pdf under inbox yields "medical" and "2024".
+func FolderTags(root, path string) []string {
+	rel, err := filepath.Rel(root, filepath.Dir(path))
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return nil
+	}
+	var tags []string
+	for _, name := range strings.Split(filepath.ToSlash(rel), "/") {
+		if name = strings.TrimSpace(name); name != "" {
+			tags = append(tags, name)
+		}
+	}
+	return tags
+}
+
+// ingestAttachments ingests an email's PDF attachments as children of the message document
+// Failures are logged; the message document itself is already stored.
+func (s *Service) ingestAttachments(ctx context.Context, parent *sqlc.Document, msg *email.Message, opts IngestOptions) {
+	childOpts := attachmentOptions(parent.ID, opts)
+
+	for i, att := range msg.Attachments {
+		if !att.IsPDF() {
+			continue
+		}
+
+		filename := att.Filename
+		if filename == "" {
+			filename = fmt.Sprintf("attachment-%d.pdf", i+1)
+		}
+
+		child, isDuplicate, err := s.ingestBytes(ctx, att.Data, filename, childOpts)
+		if err != nil {
+			slog.Warn("failed to ingest email attachment", "parent_id", parent.ID, "filename", filename, "error", err)
+			continue
+		}
+
+		_ = s.LogEvent(ctx, parent.ID, EventAttachmentExtracted, map[string]any{
+			"filename":     filename,
+			"child_id":     child.ID.String(),
+			"is_duplicate": isDuplicate,
+		}, nil, 0)
+	}
+}
+
+// attachmentOptions returns ingest options for an email's attachments from
+// the options its message was ingested with: they keep the message's date,
+// correspondent, tags (folder tags included) and document type
+func attachmentOptions(parentID uuid.UUID, opts IngestOptions) IngestOptions {
+	return IngestOptions{
+		ParentID:          &parentID,
+		DocumentDate:      opts.DocumentDate,
+		CorrespondentName: opts.CorrespondentName,
+		TagNames:          slices.Clone(opts.TagNames),
+		DocumentTypeName:  opts.DocumentTypeName,
+		Source:            opts.Source,
+	}
+}
+
+// ingestMbox splits a mailbox and ingests every message as an email document
+// Returns the first message's document; the call fails only if no message could be ingested.
+func (s *Service) ingestMbox(ctx context.Context, sourcePath, originalFilename string, opts IngestOptions) (*sqlc.Document, bool, error) {
+	f, err := os.Open(sourcePath)
+	if err != nil {
+		return nil, false, fmt.Errorf("open mbox: %w", err)
+	}
+	messages, err := email.SplitMbox(f)
+	_ = f.Close()
+	if err != nil {
+		return nil, false, err
+	}
+	if len(messages) == 0 {
+		return nil, false, fmt.Errorf("mbox contains no messages")
+	}
+
+	base := strings.TrimSuffix(originalFilename, filepath.Ext(originalFilename))
+
+	// Imported details describe the mailbox, not each message in it
+	opts.Title, opts.Notes, opts.Text, opts.ArchivePath = "", "", "", ""
+
+	var first *sqlc.Document
+	var firstDuplicate bool
+	var firstErr error
+	for i, raw := range messages {
+		filename := fmt.Sprintf("%s-%03d.eml", base, i+1)
+		doc, isDuplicate, err := s.ingestBytes(ctx, raw, filename, opts)
+		if err != nil {
+			slog.Warn("failed to ingest mbox message", "mbox", originalFilename, "index", i+1, "error", err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if first == nil {
+			first, firstDuplicate = doc, isDuplicate
+		}
+	}
+
+	if first == nil {
+		return nil, false, fmt.Errorf("ingest mbox messages: %w", firstErr)
+	}
+	slog.Info("mbox ingested", "filename", originalFilename, "messages", len(messages))
+	return first, firstDuplicate, nil
+}
+
+// ingestBytes writes data to a temp file named like filename and ingests it
+func (s *Service) ingestBytes(ctx context.Context, data []byte, filename string, opts IngestOptions) (*sqlc.Document, bool, error) {
+	tmpFile, err := os.CreateTemp("", "ingest-*"+filepath.Ext(filename))
+	if err != nil {
+		return nil, false, fmt.Errorf("create temp file: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+	defer func() { _ = os.Remove(tmpPath) }()
+
+	if _, err := tmpFile.Write(data); err != nil {
+		_ = tmpFile.Close()
+		return nil, false, fmt.Errorf("write temp file: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return nil, false, fmt.Errorf("close temp file: %w", err)
+	}
+
+	return s.IngestWithOptions(ctx, tmpPath, filename, opts)
+}
+
 // GetByID retrieves a document by ID
 func (s *Service) GetByID(ctx context.Context, id uuid.UUID) (*sqlc.Document, error) {
 	doc, err := s.db.Queries.GetDocument(ctx, id)
@@ -201,26 +750,129 @@ func (s *Service) LogEvent(ctx context.Context, docID uuid.UUID, eventType strin
 	return nil
 }
 
-// OriginalPath returns the file path for a document's original file
-func (s *Service) OriginalPath(doc *sqlc.Document) string {
-	return s.storage.PathForUUID(storage.CategoryOriginals, doc.ID, filepath.Ext(doc.OriginalFilename))
+// IngestImages combines images into one PDF document, a page per image in
+// the order given, and ingests it as filename, for documents scanned or
+// photographed a page at a time
+// Returns ErrUnsupportedType if any file isn't an image.
+func (s *Service) IngestImages(ctx context.Context, imagePaths []string, filename string) (*sqlc.Document, bool, error) {
+	tmpDir, err := os.MkdirTemp("", "combine-*")
+	if err != nil {
+		return nil, false, fmt.Errorf("create temp dir: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	combined := filepath.Join(tmpDir, "combined.pdf")
+	if err := combineImages(ctx, combined, imagePaths); err != nil {
+		return nil, false, err
+	}
+	return s.Ingest(ctx, combined, filename)
 }
 
-// ThumbnailPath returns the file path for a document's thumbnail
-func (s *Service) ThumbnailPath(doc *sqlc.Document) string {
-	return s.storage.PathForUUID(storage.CategoryThumbnails, doc.ID, ".webp")
+// combineImages converts the images at imagePaths into a single PDF at dstPath
+func combineImages(ctx context.Context, dstPath string, imagePaths []string) error {
+	images := make([]pdf.Image, 0, len(imagePaths))
+	for _, path := range imagePaths {
+		contentType, err := DetectContentType(path)
+		if err != nil {
+			return fmt.Errorf("detect content type: %w", err)
+		}
+		if !IsImage(contentType) {
+			return ErrUnsupportedType
+		}
+		images = append(images, pdf.Image{Path: path, Ext: imageExtension(contentType)})
+	}
+	if err := pdf.CombineImages(ctx, dstPath, images); err != nil {
+		return fmt.Errorf("convert images to pdf: %w", err)
+	}
+	return nil
 }
 
-// TextPath returns the file path for a document's extracted text
-func (s *Service) TextPath(doc *sqlc.Document) string {
-	return s.storage.PathForUUID(storage.CategoryText, doc.ID, ".txt")
+// storeImageRendition converts an image to a PDF and stores it as the rendition
+func (s *Service) storeImageRendition(ctx context.Context, key, contentType, imagePath string) error {
+	tmpDir, err := os.MkdirTemp("", "rendition-*")
+	if err != nil {
+		return fmt.Errorf("create temp dir: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	rendition := filepath.Join(tmpDir, "rendition.pdf")
+	if err := pdf.FromImages(ctx, rendition, imageExtension(contentType), imagePath); err != nil {
+		return fmt.Errorf("convert image to pdf: %w", err)
+	}
+	if err := s.storage.Store(ctx, key, rendition); err != nil {
+		return fmt.Errorf("store rendition: %w", err)
+	}
+	return nil
+}
+
+// OriginalKey returns the storage key for a document's original file
+func (s *Service) OriginalKey(doc *sqlc.Document) string {
+	return storage.KeyForUUID(storage.CategoryOriginals, doc.ID, filepath.Ext(doc.OriginalFilename))
 }
 
-// FileExists checks if a file exists at the given path
-func (s *Service) FileExists(path string) bool {
-	return s.storage.FileExists(path)
+// RenditionKey returns the storage key for the PDF rendition of a non-PDF original
+func (s *Service) RenditionKey(doc *sqlc.Document) string {
+	return storage.KeyForUUID(storage.CategoryRenditions, doc.ID, ".pdf")
+}
+
+// PDFKey returns the PDF to view and process: the latest page edit if the
+// document was edited, else the original for PDFs, otherwise the rendition
+func (s *Service) PDFKey(doc *sqlc.Document) string {
+	if doc.EditVersion > 0 {
+		return s.editedKey(doc.ID, doc.Revision, doc.EditVersion)
+	}
+	if doc.ContentType == ContentTypePDF {
+		return s.OriginalKey(doc)
+	}
+	return s.RenditionKey(doc)
+}
+
+// ArchiveKey returns the storage key for a document's searchable PDF/A archive version
+func (s *Service) ArchiveKey(doc *sqlc.Document) string {
+	return storage.KeyForUUID(storage.CategoryArchive, doc.ID, ".pdf")
+}
+
+// PDFFilename returns the filename to present for a document's PDF
+func PDFFilename(doc *sqlc.Document) string {
+	if doc.ContentType == ContentTypePDF {
+		return doc.OriginalFilename
+	}
+	return strings.TrimSuffix(doc.OriginalFilename, filepath.Ext(doc.OriginalFilename)) + ".pdf"
+}
+
+// ThumbnailKey returns the storage key for a document's thumbnail
+func (s *Service) ThumbnailKey(doc *sqlc.Document) string {
+	return storage.KeyForUUID(storage.CategoryThumbnails, doc.ID, ".webp")
+}
+
+// TextKey returns the storage key for a document's extracted text
+func (s *Service) TextKey(doc *sqlc.Document) string {
+	return storage.KeyForUUID(storage.CategoryText, doc.ID, ".txt")
+}
+
+// FileExists checks if a file is stored under key
+func (s *Service) FileExists(ctx context.Context, key string) bool {
+	return s.storage.FileExists(ctx, key)
+}
+
+// OpenFile opens a stored file for reading
+func (s *Service) OpenFile(ctx context.Context, key string) (storage.Object, storage.ObjectInfo, error) {
+	return s.storage.Open(ctx, key)
+}
+
+// FetchFile makes a stored file available at a local path until release is called
+func (s *Service) FetchFile(ctx context.Context, key string) (string, func(), error) {
+	return s.storage.Fetch(ctx, key)
 }
 
 func intPtr(i int32) *int32 {
 	return &i
 }
+
+// nilIfEmpty returns nil for an empty string, for optional text columns
+func nilIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,120 @@
+package document
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestFolderTags(t *testing.T) {
+	root := filepath.FromSlash("/scans/inbox")
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"/scans/inbox/scan.pdf", nil},
+		{"/scans/inbox/medical/2024/scan.pdf", []string{"medical", "2024"}},
+		{"/scans/inbox/Tax Returns/scan.pdf", []string{"Tax Returns"}},
+		{"/scans/other/scan.pdf", nil},
+	}
+
+	for _, tt := range tests {
+		got := FolderTags(root, filepath.FromSlash(tt.path))
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("FolderTags(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestApplySource(t *testing.T) {
+	source := &IngestSource{
+		Type:              SourceInbox,
+		ID:                uuid.New(),
+		Name:              "Taxes",
+		TagNames:          []string{"tax"},
+		CorrespondentName: "Accountant",
+		DocumentTypeName:  "Statement",
+	}
+
+	// Defaults fill in what's unset
+	opts := IngestOptions{TagNames: []string{"scanned"}, Source: source}
+	applied := opts.applySource()
+	if !slices.Equal(opts.TagNames, []string{"scanned", "tax"}) || opts.CorrespondentName != "Accountant" || opts.DocumentTypeName != "Statement" {
+		t.Errorf("applySource() left opts %+v", opts)
+	}
+	if applied["name"] != "Taxes" || applied["correspondent"] != "Accountant" || applied["document_type"] != "Statement" {
+		t.Errorf("applySource() = %v", applied)
+	}
+
+	// Explicit metadata wins and isn't recorded as applied
+	opts = IngestOptions{CorrespondentName: "City Bank", Source: source}
+	applied = opts.applySource()
+	if opts.CorrespondentName != "City Bank" {
+		t.Errorf("CorrespondentName = %q, want the explicit one", opts.CorrespondentName)
+	}
+	if _, ok := applied["correspondent"]; ok {
+		t.Errorf("applySource() recorded an unapplied correspondent: %v", applied)
+	}
+
+	opts = IngestOptions{}
+	if applied := opts.applySource(); applied != nil {
+		t.Errorf("applySource() without a source = %v, want nil", applied)
+	}
+}
+
+func TestAttachmentOptions(t *testing.T) {
+	root := filepath.FromSlash("/scans/inbox")
+	source := &IngestSource{
+		Type:             SourceInbox,
+		ID:               uuid.New(),
+		Name:             "Scans",
+		TagNames:         []string{"inbox"},
+		DocumentTypeName: "Letter",
+	}
+
+	// An email dropped into a subfolder of an inbox with folder tags
+	opts := IngestOptions{
+		TagNames: FolderTags(root, filepath.FromSlash("/scans/inbox/medical/2024/results.eml")),
+		Source:   source,
+	}
+	opts.applySource()
+	opts.CorrespondentName = "clinic@example.com"
+
+	parentID := uuid.New()
+	child := attachmentOptions(parentID, opts)
+	child.applySource()
+
+	if child.ParentID == nil || *child.ParentID != parentID {
+		t.Errorf("ParentID = %v, want %v", child.ParentID, parentID)
+	}
+	if want := []string{"medical", "2024", "inbox"}; !slices.Equal(child.TagNames, want) {
+		t.Errorf("TagNames = %q, want %q", child.TagNames, want)
+	}
+	if child.DocumentTypeName != "Letter" {
+		t.Errorf("DocumentTypeName = %q, want %q", child.DocumentTypeName, "Letter")
+	}
+	if child.CorrespondentName != "clinic@example.com" {
+		t.Errorf("CorrespondentName = %q, want the message's", child.CorrespondentName)
+	}
+
+	// The message's options are left alone
+	if want := []string{"medical", "2024", "inbox"}; !slices.Equal(opts.TagNames, want) {
+		t.Errorf("message TagNames = %q, want %q", opts.TagNames, want)
+	}
+}
+
+func TestLockOrder(t *testing.T) {
+	got := lockOrder([]string{"medical", "2024", "Tax", "Medical", "tax", "b"})
+	want := []string{"2024", "b", "medical", "Tax"}
+	if !slices.Equal(got, want) {
+		t.Errorf("lockOrder() = %q, want %q", got, want)
+	}
+
+	// Folder paths in opposite orders lock in the same order
+	if a, b := lockOrder([]string{"a", "b"}), lockOrder([]string{"b", "a"}); !slices.Equal(a, b) {
+		t.Errorf("lockOrder() = %q and %q, want the same order", a, b)
+	}
+}
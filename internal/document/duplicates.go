@@ -0,0 +1,143 @@
+package document
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/bketelsen/docko/internal/database/sqlc"
+)
+
+// ErrCandidateResolved is returned when reviewing a duplicate candidate that was already reviewed
+var ErrCandidateResolved = errors.New("duplicate candidate already resolved")
+
+// ErrNotInCandidate is returned when the document to keep isn't part of the candidate pair
+var ErrNotInCandidate = errors.New("document is not part of the duplicate candidate")
+
+// MergeDuplicate resolves a duplicate candidate by keeping one document.
+// The other document's tags are added to the kept one, its correspondent and
+// document type are used if the kept document has none, and it is moved to the trash.
+func (s *Service) MergeDuplicate(ctx context.Context, candidateID, keepID uuid.UUID) (*sqlc.Document, error) {
+	candidate, err := s.pendingCandidate(ctx, candidateID)
+	if err != nil {
+		return nil, err
+	}
+
+	var dropID uuid.UUID
+	switch keepID {
+	case candidate.DocumentID:
+		dropID = candidate.DuplicateOfID
+	case candidate.DuplicateOfID:
+		dropID = candidate.DocumentID
+	default:
+		return nil, ErrNotInCandidate
+	}
+
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	qtx := s.db.Queries.WithTx(tx)
+
+	// Claim the candidate first: a second submit waits on the row, then
+	// finds it resolved and merges nothing
+	if err := resolveCandidate(ctx, qtx, candidateID, sqlc.DuplicateStatusMerged); err != nil {
+		return nil, err
+	}
+
+	if err := qtx.CopyDocumentTags(ctx, sqlc.CopyDocumentTagsParams{TargetID: keepID, SourceID: dropID}); err != nil {
+		return nil, fmt.Errorf("merge tags: %w", err)
+	}
+
+	if _, err := qtx.GetDocumentCorrespondent(ctx, keepID); errors.Is(err, pgx.ErrNoRows) {
+		if corr, err := qtx.GetDocumentCorrespondent(ctx, dropID); err == nil {
+			if err := qtx.SetDocumentCorrespondent(ctx, sqlc.SetDocumentCorrespondentParams{
+				DocumentID:      keepID,
+				CorrespondentID: corr.ID,
+			}); err != nil {
+				return nil, fmt.Errorf("merge correspondent: %w", err)
+			}
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("get correspondent: %w", err)
+	}
+
+	if err := qtx.CopyDocumentType(ctx, sqlc.CopyDocumentTypeParams{TargetID: keepID, SourceID: dropID}); err != nil {
+		return nil, fmt.Errorf("merge document type: %w", err)
+	}
+
+	dropped, err := qtx.TrashDocument(ctx, dropID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("trash duplicate: %w", err)
+	}
+
+	eventPayload, _ := json.Marshal(map[string]any{
+		"merged_id":       dropID,
+		"merged_filename": dropped.OriginalFilename,
+	})
+	if _, err := qtx.CreateDocumentEvent(ctx, sqlc.CreateDocumentEventParams{
+		DocumentID: keepID,
+		EventType:  EventMerged,
+		Payload:    eventPayload,
+	}); err != nil {
+		return nil, fmt.Errorf("create event: %w", err)
+	}
+	if _, err := qtx.CreateDocumentEvent(ctx, sqlc.CreateDocumentEventParams{
+		DocumentID: dropID,
+		EventType:  EventTrashed,
+	}); err != nil {
+		return nil, fmt.Errorf("create event: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	slog.Info("duplicate merged", "kept", keepID, "trashed", dropID)
+	return s.GetByID(ctx, keepID)
+}
+
+// MarkDistinct resolves a duplicate candidate as two different documents
+// The pair is not flagged again.
+func (s *Service) MarkDistinct(ctx context.Context, candidateID uuid.UUID) error {
+	if _, err := s.pendingCandidate(ctx, candidateID); err != nil {
+		return err
+	}
+
+	return resolveCandidate(ctx, s.db.Queries, candidateID, sqlc.DuplicateStatusDistinct)
+}
+
+// resolveCandidate records the review of a pending duplicate candidate
+// Returns ErrCandidateResolved if it was reviewed in the meantime.
+func resolveCandidate(ctx context.Context, q *sqlc.Queries, id uuid.UUID, status sqlc.DuplicateStatus) error {
+	_, err := q.ResolveDuplicateCandidate(ctx, sqlc.ResolveDuplicateCandidateParams{
+		ID:     id,
+		Status: status,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrCandidateResolved
+	}
+	if err != nil {
+		return fmt.Errorf("resolve candidate: %w", err)
+	}
+	return nil
+}
+
+// pendingCandidate retrieves a duplicate candidate that is still awaiting review
+func (s *Service) pendingCandidate(ctx context.Context, id uuid.UUID) (*sqlc.DuplicateCandidate, error) {
+	candidate, err := s.db.Queries.GetDuplicateCandidate(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get duplicate candidate: %w", err)
+	}
+	if candidate.Status != sqlc.DuplicateStatusPending {
+		return nil, ErrCandidateResolved
+	}
+	return &candidate, nil
+}
@@ -0,0 +1,287 @@
+package document
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/bketelsen/docko/internal/database/sqlc"
+)
+
+// ErrInvalidFieldValue is returned for values that don't fit a custom field's type
+var ErrInvalidFieldValue = errors.New("invalid field value")
+
+// maxFilterDays bounds "next" and "last" date filters to a century, well
+// inside the range Postgres can add to a date
+const maxFilterDays = 36500
+
+// FieldTypes lists the custom field types in display order
+var FieldTypes = []sqlc.CustomFieldType{
+	sqlc.CustomFieldTypeString,
+	sqlc.CustomFieldTypeNumber,
+	sqlc.CustomFieldTypeMonetary,
+	sqlc.CustomFieldTypeDate,
+	sqlc.CustomFieldTypeBoolean,
+	sqlc.CustomFieldTypeUrl,
+	sqlc.CustomFieldTypeDocumentLink,
+}
+
+// SetCustomField sets a document's value for a custom field from user input
+// An empty value removes the field from the document.
+func (s *Service) SetCustomField(ctx context.Context, docID, fieldID uuid.UUID, raw string) error {
+	field, err := s.db.Queries.GetCustomField(ctx, fieldID)
+	if err != nil {
+		return fmt.Errorf("get custom field: %w", err)
+	}
+
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return s.db.Queries.RemoveDocumentCustomField(ctx, sqlc.RemoveDocumentCustomFieldParams{
+			DocumentID: docID,
+			FieldID:    fieldID,
+		})
+	}
+
+	params, err := ParseFieldValue(field.FieldType, raw)
+	if err != nil {
+		return err
+	}
+	if params.ValueDocumentID.Valid {
+		if params.ValueDocumentID.Bytes == docID {
+			return fmt.Errorf("%w: a document can't link to itself", ErrInvalidFieldValue)
+		}
+		if _, err := s.GetByID(ctx, params.ValueDocumentID.Bytes); err != nil {
+			return fmt.Errorf("%w: linked document not found", ErrInvalidFieldValue)
+		}
+	}
+
+	params.DocumentID = docID
+	params.FieldID = fieldID
+	if err := s.db.Queries.SetDocumentCustomField(ctx, params); err != nil {
+		return fmt.Errorf("set custom field: %w", err)
+	}
+	return nil
+}
+
+// ParseFieldValue converts user input to the typed column for a field type
+// The returned params have no document or field ID set.
+func ParseFieldValue(fieldType sqlc.CustomFieldType, raw string) (sqlc.SetDocumentCustomFieldParams, error) {
+	var params sqlc.SetDocumentCustomFieldParams
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return params, fmt.Errorf("%w: value is empty", ErrInvalidFieldValue)
+	}
+
+	switch fieldType {
+	case sqlc.CustomFieldTypeString:
+		params.ValueText = &raw
+	case sqlc.CustomFieldTypeUrl:
+		u, err := url.Parse(raw)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return params, fmt.Errorf("%w: %q is not an http(s) URL", ErrInvalidFieldValue, raw)
+		}
+		params.ValueText = &raw
+	case sqlc.CustomFieldTypeNumber, sqlc.CustomFieldTypeMonetary:
+		n, err := parseNumber(fieldType, raw)
+		if err != nil {
+			return params, err
+		}
+		params.ValueNumber = n
+	case sqlc.CustomFieldTypeDate:
+		d, err := parseDate(raw)
+		if err != nil {
+			return params, err
+		}
+		params.ValueDate = pgtype.Date{Time: d, Valid: true}
+	case sqlc.CustomFieldTypeBoolean:
+		b, err := parseBool(raw)
+		if err != nil {
+			return params, err
+		}
+		params.ValueBool = &b
+	case sqlc.CustomFieldTypeDocumentLink:
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return params, fmt.Errorf("%w: %q is not a document ID", ErrInvalidFieldValue, raw)
+		}
+		params.ValueDocumentID = pgtype.UUID{Bytes: id, Valid: true}
+	default:
+		return params, fmt.Errorf("%w: unknown field type %q", ErrInvalidFieldValue, fieldType)
+	}
+	return params, nil
+}
+
+// FieldFilter matches documents on one custom field value in SearchDocuments
+// Op is specific to the field's column type (e.g. num_gt, date_next) so the
+// query only casts Value to the type it was validated as.
+type FieldFilter struct {
+	FieldID uuid.UUID `json:"field_id"`
+	Op      string    `json:"op"`
+	Value   string    `json:"val"`
+}
+
+// Filter operators offered for each field type
+var fieldFilterOps = map[sqlc.CustomFieldType][]string{
+	sqlc.CustomFieldTypeString:       {"set", "eq", "contains"},
+	sqlc.CustomFieldTypeUrl:          {"set", "eq", "contains"},
+	sqlc.CustomFieldTypeNumber:       {"set", "eq", "gt", "gte", "lt", "lte"},
+	sqlc.CustomFieldTypeMonetary:     {"set", "eq", "gt", "gte", "lt", "lte"},
+	sqlc.CustomFieldTypeDate:         {"set", "eq", "before", "after", "next", "last"},
+	sqlc.CustomFieldTypeBoolean:      {"set", "eq"},
+	sqlc.CustomFieldTypeDocumentLink: {"set", "eq"},
+}
+
+// FieldFilterOps returns the filter operators available for a field type
+func FieldFilterOps(fieldType sqlc.CustomFieldType) []string {
+	return fieldFilterOps[fieldType]
+}
+
+// ParseFieldFilter validates a filter such as "gt 100" on a field
+// op is one of FieldFilterOps for the field's type; value is ignored for "set".
+func ParseFieldFilter(field sqlc.CustomField, op, value string) (FieldFilter, error) {
+	filter := FieldFilter{FieldID: field.ID}
+	value = strings.TrimSpace(value)
+
+	valid := false
+	for _, o := range fieldFilterOps[field.FieldType] {
+		valid = valid || o == op
+	}
+	if !valid {
+		return filter, fmt.Errorf("%w: can't filter %s fields with %q", ErrInvalidFieldValue, field.FieldType, op)
+	}
+	if op == "set" {
+		filter.Op = "set"
+		return filter, nil
+	}
+
+	switch field.FieldType {
+	case sqlc.CustomFieldTypeString, sqlc.CustomFieldTypeUrl:
+		if value == "" {
+			return filter, fmt.Errorf("%w: value is empty", ErrInvalidFieldValue)
+		}
+		filter.Op, filter.Value = "text_"+op, value
+	case sqlc.CustomFieldTypeNumber, sqlc.CustomFieldTypeMonetary:
+		n, err := parseNumber(field.FieldType, value)
+		if err != nil {
+			return filter, err
+		}
+		f, _ := n.Float64Value()
+		filter.Op, filter.Value = "num_"+op, strconv.FormatFloat(f.Float64, 'f', -1, 64)
+	case sqlc.CustomFieldTypeDate:
+		if op == "next" || op == "last" {
+			days, err := strconv.Atoi(value)
+			if err != nil || days < 0 {
+				return filter, fmt.Errorf("%w: %q is not a number of days", ErrInvalidFieldValue, value)
+			}
+			if days > maxFilterDays {
+				return filter, fmt.Errorf("%w: %d days is more than %d", ErrInvalidFieldValue, days, maxFilterDays)
+			}
+			filter.Op, filter.Value = "date_"+op, strconv.Itoa(days)
+			break
+		}
+		d, err := parseDate(value)
+		if err != nil {
+			return filter, err
+		}
+		filter.Op, filter.Value = "date_"+op, d.Format("2006-01-02")
+	case sqlc.CustomFieldTypeBoolean:
+		b, err := parseBool(value)
+		if err != nil {
+			return filter, err
+		}
+		filter.Op, filter.Value = "bool_eq", strconv.FormatBool(b)
+	case sqlc.CustomFieldTypeDocumentLink:
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return filter, fmt.Errorf("%w: %q is not a document ID", ErrInvalidFieldValue, value)
+		}
+		filter.Op, filter.Value = "link_eq", id.String()
+	}
+	return filter, nil
+}
+
+// FieldFilterLabel describes a filter for display, e.g. "Amount > 100"
+func FieldFilterLabel(name, op, value string) string {
+	switch op {
+	case "set":
+		return name + " is set"
+	case "eq":
+		return name + " = " + value
+	case "contains":
+		return fmt.Sprintf("%s contains %q", name, value)
+	case "gt":
+		return name + " > " + value
+	case "gte":
+		return name + " ≥ " + value
+	case "lt":
+		return name + " < " + value
+	case "lte":
+		return name + " ≤ " + value
+	case "before":
+		return name + " before " + value
+	case "after":
+		return name + " after " + value
+	case "next":
+		return fmt.Sprintf("%s in next %s days", name, value)
+	case "last":
+		return fmt.Sprintf("%s in last %s days", name, value)
+	}
+	return name
+}
+
+// parseNumber parses a number; monetary values may include a currency
+// symbol and thousands separators, e.g. "$1,234.50"
+func parseNumber(fieldType sqlc.CustomFieldType, raw string) (pgtype.Numeric, error) {
+	var n pgtype.Numeric
+	s := raw
+	if fieldType == sqlc.CustomFieldTypeMonetary {
+		s = strings.Map(func(r rune) rune {
+			if (r >= '0' && r <= '9') || r == '.' || r == '-' {
+				return r
+			}
+			return -1
+		}, s)
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return n, fmt.Errorf("%w: %q is not a number", ErrInvalidFieldValue, raw)
+	}
+	if fieldType == sqlc.CustomFieldTypeMonetary {
+		s = strconv.FormatFloat(f, 'f', 2, 64)
+	}
+	if err := n.Scan(s); err != nil {
+		return n, fmt.Errorf("%w: %q is not a number", ErrInvalidFieldValue, raw)
+	}
+	return n, nil
+}
+
+// parseDate parses a YYYY-MM-DD date
+func parseDate(raw string) (time.Time, error) {
+	d, err := time.Parse("2006-01-02", raw)
+	if err != nil {
+		return d, fmt.Errorf("%w: %q is not a date (YYYY-MM-DD)", ErrInvalidFieldValue, raw)
+	}
+	return d, nil
+}
+
+// parseBool parses yes/no style booleans as well as strconv.ParseBool values
+func parseBool(raw string) (bool, error) {
+	switch strings.ToLower(raw) {
+	case "yes", "y", "on":
+		return true, nil
+	case "no", "n", "off":
+		return false, nil
+	}
+	b, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, fmt.Errorf("%w: %q is not yes or no", ErrInvalidFieldValue, raw)
+	}
+	return b, nil
+}
@@ -0,0 +1,130 @@
+package document
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/bketelsen/docko/internal/database/sqlc"
+)
+
+func TestParseFieldValue(t *testing.T) {
+	tests := []struct {
+		name      string
+		fieldType sqlc.CustomFieldType
+		raw       string
+		check     func(sqlc.SetDocumentCustomFieldParams) bool
+	}{
+		{"string", sqlc.CustomFieldTypeString, " INV-2024-001 ", func(p sqlc.SetDocumentCustomFieldParams) bool {
+			return p.ValueText != nil && *p.ValueText == "INV-2024-001"
+		}},
+		{"url", sqlc.CustomFieldTypeUrl, "https://example.com/bill", func(p sqlc.SetDocumentCustomFieldParams) bool {
+			return p.ValueText != nil && *p.ValueText == "https://example.com/bill"
+		}},
+		{"number", sqlc.CustomFieldTypeNumber, "42.5", func(p sqlc.SetDocumentCustomFieldParams) bool {
+			f, _ := p.ValueNumber.Float64Value()
+			return p.ValueNumber.Valid && f.Float64 == 42.5
+		}},
+		{"monetary with symbol", sqlc.CustomFieldTypeMonetary, "$1,234.5", func(p sqlc.SetDocumentCustomFieldParams) bool {
+			f, _ := p.ValueNumber.Float64Value()
+			return p.ValueNumber.Valid && f.Float64 == 1234.5
+		}},
+		{"date", sqlc.CustomFieldTypeDate, "2024-03-15", func(p sqlc.SetDocumentCustomFieldParams) bool {
+			return p.ValueDate.Valid && p.ValueDate.Time.Format("2006-01-02") == "2024-03-15"
+		}},
+		{"boolean yes", sqlc.CustomFieldTypeBoolean, "Yes", func(p sqlc.SetDocumentCustomFieldParams) bool {
+			return p.ValueBool != nil && *p.ValueBool
+		}},
+		{"boolean false", sqlc.CustomFieldTypeBoolean, "false", func(p sqlc.SetDocumentCustomFieldParams) bool {
+			return p.ValueBool != nil && !*p.ValueBool
+		}},
+		{"document link", sqlc.CustomFieldTypeDocumentLink, "0b6f2a3e-8f3c-4a51-9d7e-2f1c0a9b8e7d", func(p sqlc.SetDocumentCustomFieldParams) bool {
+			return p.ValueDocumentID.Valid && uuid.UUID(p.ValueDocumentID.Bytes).String() == "0b6f2a3e-8f3c-4a51-9d7e-2f1c0a9b8e7d"
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFieldValue(tt.fieldType, tt.raw)
+			if err != nil {
+				t.Fatalf("ParseFieldValue(%q) failed: %v", tt.raw, err)
+			}
+			if !tt.check(got) {
+				t.Errorf("ParseFieldValue(%q) = %+v", tt.raw, got)
+			}
+		})
+	}
+}
+
+func TestParseFieldValue_Invalid(t *testing.T) {
+	tests := []struct {
+		fieldType sqlc.CustomFieldType
+		raw       string
+	}{
+		{sqlc.CustomFieldTypeString, "  "},
+		{sqlc.CustomFieldTypeUrl, "example.com"},
+		{sqlc.CustomFieldTypeUrl, "javascript:alert(1)"},
+		{sqlc.CustomFieldTypeNumber, "$12"},
+		{sqlc.CustomFieldTypeMonetary, "twelve"},
+		{sqlc.CustomFieldTypeDate, "15/03/2024"},
+		{sqlc.CustomFieldTypeBoolean, "maybe"},
+		{sqlc.CustomFieldTypeDocumentLink, "not-a-uuid"},
+	}
+
+	for _, tt := range tests {
+		if _, err := ParseFieldValue(tt.fieldType, tt.raw); !errors.Is(err, ErrInvalidFieldValue) {
+			t.Errorf("ParseFieldValue(%s, %q) error = %v, want ErrInvalidFieldValue", tt.fieldType, tt.raw, err)
+		}
+	}
+}
+
+func TestParseFieldFilter(t *testing.T) {
+	amount := sqlc.CustomField{ID: uuid.New(), Name: "Amount", FieldType: sqlc.CustomFieldTypeMonetary}
+	due := sqlc.CustomField{ID: uuid.New(), Name: "Due Date", FieldType: sqlc.CustomFieldTypeDate}
+	paid := sqlc.CustomField{ID: uuid.New(), Name: "Paid", FieldType: sqlc.CustomFieldTypeBoolean}
+
+	tests := []struct {
+		name    string
+		field   sqlc.CustomField
+		op      string
+		value   string
+		wantOp  string
+		wantVal string
+	}{
+		{"amount greater than", amount, "gt", "$100", "num_gt", "100"},
+		{"due in next days", due, "next", "14", "date_next", "14"},
+		{"due within a century", due, "last", "36500", "date_last", "36500"},
+		{"due before", due, "before", "2024-12-31", "date_before", "2024-12-31"},
+		{"paid", paid, "eq", "no", "bool_eq", "false"},
+		{"is set ignores value", amount, "set", "", "set", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFieldFilter(tt.field, tt.op, tt.value)
+			if err != nil {
+				t.Fatalf("ParseFieldFilter failed: %v", err)
+			}
+			if got.FieldID != tt.field.ID || got.Op != tt.wantOp || got.Value != tt.wantVal {
+				t.Errorf("ParseFieldFilter = %+v, want op %q value %q", got, tt.wantOp, tt.wantVal)
+			}
+		})
+	}
+
+	for _, bad := range []struct {
+		field     sqlc.CustomField
+		op, value string
+	}{
+		{amount, "contains", "100"}, // Not a numeric operator
+		{amount, "gt", "lots"},
+		{amount, "next", "-3"},
+		{due, "next", "-3"},
+		{due, "next", "36501"},
+		{due, "last", "99999999999"},
+	} {
+		if _, err := ParseFieldFilter(bad.field, bad.op, bad.value); !errors.Is(err, ErrInvalidFieldValue) {
+			t.Errorf("ParseFieldFilter(%s, %q, %q) error = %v, want ErrInvalidFieldValue", bad.field.Name, bad.op, bad.value, err)
+		}
+	}
+}
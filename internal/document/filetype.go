@@ -0,0 +1,187 @@
+package document
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/h2non/filetype"
+)
+
+// Content types accepted for ingestion
+const (
+	ContentTypePDF  = "application/pdf"
+	ContentTypeJPEG = "image/jpeg"
+	ContentTypePNG  = "image/png"
+	ContentTypeTIFF = "image/tiff"
+	ContentTypeHEIC = "image/heic"
+	ContentTypeDOCX = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	ContentTypeXLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	ContentTypeODT  = "application/vnd.oasis.opendocument.text"
+	ContentTypeODS  = "application/vnd.oasis.opendocument.spreadsheet"
+	ContentTypeRTF  = "application/rtf"
+	ContentTypeEML  = "message/rfc822"
+	ContentTypeMbox = "application/mbox"
+)
+
+// supportedExtensions maps accepted filename extensions to their content type
+var supportedExtensions = map[string]string{
+	".pdf":  ContentTypePDF,
+	".jpg":  ContentTypeJPEG,
+	".jpeg": ContentTypeJPEG,
+	".png":  ContentTypePNG,
+	".tif":  ContentTypeTIFF,
+	".tiff": ContentTypeTIFF,
+	".heic": ContentTypeHEIC,
+	".heif": ContentTypeHEIC,
+	".docx": ContentTypeDOCX,
+	".xlsx": ContentTypeXLSX,
+	".odt":  ContentTypeODT,
+	".ods":  ContentTypeODS,
+	".rtf":  ContentTypeRTF,
+	".eml":  ContentTypeEML,
+	".mbox": ContentTypeMbox,
+}
+
+// odfMimePrefix is found at offset 30 of OpenDocument files, where the
+// uncompressed "mimetype" entry is required to be the first zip member
+var odfMimePrefix = []byte("mimetypeapplication/vnd.oasis.opendocument.")
+
+// IsSupportedFilename checks if a filename has an extension we can ingest (case-insensitive)
+func IsSupportedFilename(path string) bool {
+	_, ok := supportedExtensions[strings.ToLower(filepath.Ext(path))]
+	return ok
+}
+
+// IsImage reports whether a content type is an image that needs a PDF rendition
+func IsImage(contentType string) bool {
+	return strings.HasPrefix(contentType, "image/")
+}
+
+// IsOffice reports whether a content type is an office document converted by the convert job
+func IsOffice(contentType string) bool {
+	switch contentType {
+	case ContentTypeDOCX, ContentTypeXLSX, ContentTypeODT, ContentTypeODS, ContentTypeRTF:
+		return true
+	default:
+		return false
+	}
+}
+
+// NeedsConversion reports whether a content type gets its PDF rendition from the convert job
+func NeedsConversion(contentType string) bool {
+	return IsOffice(contentType) || contentType == ContentTypeEML
+}
+
+// DetectContentType identifies a file by its magic bytes
+// Zip-based office formats and text-based email files, which magic bytes
+// can't pin down, also consider the path's extension.
+// Returns an empty string if the file is not a supported type
+func DetectContentType(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("open file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	// Read enough of the header to see past the first zip entries of office files
+	head := make([]byte, 8192)
+	n, err := f.Read(head)
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("read file header: %w", err)
+	}
+
+	kind, err := filetype.Match(head[:n])
+	if err != nil {
+		return "", fmt.Errorf("match file type: %w", err)
+	}
+
+	switch kind.MIME.Value {
+	case ContentTypePDF, ContentTypeJPEG, ContentTypePNG, ContentTypeTIFF:
+		return kind.MIME.Value, nil
+	case "image/heif":
+		return ContentTypeHEIC, nil
+	case ContentTypeDOCX, ContentTypeXLSX, ContentTypeRTF:
+		return kind.MIME.Value, nil
+	case "application/zip":
+		return zipContentType(head[:n], path), nil
+	case "":
+		return mailContentType(head[:n], path), nil
+	default:
+		return "", nil
+	}
+}
+
+// zipContentType identifies office formats stored in a zip container
+func zipContentType(head []byte, path string) string {
+	if len(head) > 30 && bytes.HasPrefix(head[30:], odfMimePrefix) {
+		rest := head[30+len(odfMimePrefix):]
+		switch {
+		case bytes.HasPrefix(rest, []byte("text")):
+			return ContentTypeODT
+		case bytes.HasPrefix(rest, []byte("spreadsheet")):
+			return ContentTypeODS
+		}
+		return ""
+	}
+
+	// OOXML member order isn't fixed, so trust the extension for zips
+	// the magic matchers missed
+	switch ct := supportedExtensions[strings.ToLower(filepath.Ext(path))]; ct {
+	case ContentTypeDOCX, ContentTypeXLSX:
+		return ct
+	default:
+		return ""
+	}
+}
+
+// headerLinePattern matches an RFC 5322 header field at the start of a file
+var headerLinePattern = regexp.MustCompile(`^[!-9;-~]+:[ \t]`)
+
+// mailContentType identifies mbox files by their "From " separator and
+// .eml files by a leading header field
+func mailContentType(head []byte, path string) string {
+	if bytes.HasPrefix(head, []byte("From ")) {
+		return ContentTypeMbox
+	}
+	if strings.EqualFold(filepath.Ext(path), ".eml") && headerLinePattern.Match(head) {
+		return ContentTypeEML
+	}
+	return ""
+}
+
+// OfficeExtension returns the canonical file extension for an office content type
+func OfficeExtension(contentType string) string {
+	switch contentType {
+	case ContentTypeDOCX:
+		return ".docx"
+	case ContentTypeXLSX:
+		return ".xlsx"
+	case ContentTypeODT:
+		return ".odt"
+	case ContentTypeODS:
+		return ".ods"
+	case ContentTypeRTF:
+		return ".rtf"
+	default:
+		return ""
+	}
+}
+
+// imageExtension returns the file extension identifying an image content type's format
+func imageExtension(contentType string) string {
+	switch contentType {
+	case ContentTypePNG:
+		return ".png"
+	case ContentTypeTIFF:
+		return ".tif"
+	case ContentTypeHEIC:
+		return ".heic"
+	default:
+		return ".jpg"
+	}
+}
@@ -0,0 +1,156 @@
+package document
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bketelsen/docko/internal/pdf"
+)
+
+// writeZip creates a zip file whose first member is stored uncompressed, like ODF requires
+func writeZip(t *testing.T, path string, first, firstContent string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("create zip: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	zw := zip.NewWriter(f)
+	w, err := zw.CreateHeader(&zip.FileHeader{Name: first, Method: zip.Store})
+	if err != nil {
+		t.Fatalf("create entry: %v", err)
+	}
+	if _, err := w.Write([]byte(firstContent)); err != nil {
+		t.Fatalf("write entry: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("close zip: %v", err)
+	}
+}
+
+func TestDetectContentType(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	write := func(name string, data []byte) string {
+		p := filepath.Join(tmpDir, name)
+		if err := os.WriteFile(p, data, 0644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+		return p
+	}
+
+	odt := filepath.Join(tmpDir, "letter")
+	writeZip(t, odt, "mimetype", "application/vnd.oasis.opendocument.text")
+	ods := filepath.Join(tmpDir, "budget")
+	writeZip(t, ods, "mimetype", "application/vnd.oasis.opendocument.spreadsheet")
+	docx := filepath.Join(tmpDir, "report.docx")
+	writeZip(t, docx, "docProps/app.xml", "<Properties/>")
+	plainZip := filepath.Join(tmpDir, "archive.zip")
+	writeZip(t, plainZip, "readme.txt", "hello")
+
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{"pdf", write("doc.pdf", []byte("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n")), ContentTypePDF},
+		{"png", write("image", []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")), ContentTypePNG},
+		{"rtf", write("note.rtf", []byte(`{\rtf1\ansi Hello}`)), ContentTypeRTF},
+		{"odt", odt, ContentTypeODT},
+		{"ods", ods, ContentTypeODS},
+		{"docx by extension", docx, ContentTypeDOCX},
+		{"plain zip", plainZip, ""},
+		{"text", write("notes.txt", []byte("just some text")), ""},
+		{"eml", write("bill.eml", []byte("Return-Path: <a@example.com>\r\nFrom: a@example.com\r\n\r\nHi")), ContentTypeEML},
+		{"eml without extension", write("bill", []byte("From: a@example.com\r\n\r\nHi")), ""},
+		{"mbox", write("archive.mbox", []byte("From a@example.com Mon Jan  1 00:00:00 2024\nSubject: x\n\nHi\n")), ContentTypeMbox},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DetectContentType(tt.path)
+			if err != nil {
+				t.Fatalf("DetectContentType error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("DetectContentType = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsSupportedFilename(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"scan.pdf", true},
+		{"SCAN.PDF", true},
+		{"photo.HEIC", true},
+		{"letter.docx", true},
+		{"sheet.ods", true},
+		{"bill.eml", true},
+		{"Archive.MBOX", true},
+		{"notes.txt", false},
+		{"noext", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsSupportedFilename(tt.name); got != tt.want {
+			t.Errorf("IsSupportedFilename(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCombineImages(t *testing.T) {
+	tmpDir := t.TempDir()
+	img := image.NewGray(image.Rect(0, 0, 60, 90))
+
+	// Pages of one document photographed and scanned, named like upload temp files
+	var paths []string
+	for i, encode := range []func(*os.File) error{
+		func(f *os.File) error { return jpeg.Encode(f, img, nil) },
+		func(f *os.File) error { return png.Encode(f, img) },
+		func(f *os.File) error { return jpeg.Encode(f, img, nil) },
+	} {
+		f, err := os.CreateTemp(tmpDir, "upload-*")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := encode(f); err != nil {
+			t.Fatalf("encode page %d: %v", i+1, err)
+		}
+		_ = f.Close()
+		paths = append(paths, f.Name())
+	}
+
+	dst := filepath.Join(tmpDir, "combined.pdf")
+	if err := combineImages(context.Background(), dst, paths); err != nil {
+		t.Fatalf("combineImages() error = %v", err)
+	}
+	n, err := pdf.PageCount(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Errorf("page count = %d, want 3", n)
+	}
+
+	// Anything but images is refused
+	notImage := filepath.Join(tmpDir, "notes.pdf")
+	if err := os.WriteFile(notImage, []byte("%PDF-1.4\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	err = combineImages(context.Background(), filepath.Join(tmpDir, "mixed.pdf"), append(paths, notImage))
+	if !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("combineImages() with a PDF error = %v, want ErrUnsupportedType", err)
+	}
+}
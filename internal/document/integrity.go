@@ -0,0 +1,252 @@
+package document
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/bketelsen/docko/internal/database/sqlc"
+	"github.com/bketelsen/docko/internal/storage"
+)
+
+// Integrity issue kinds
+const (
+	IssueMissing       = "missing"        // An expected file isn't in storage
+	IssueCorrupt       = "corrupt"        // A file doesn't match its recorded hash
+	IssueOrphaned      = "orphaned"       // A file belongs to no document
+	IssueThumbnailFlag = "thumbnail_flag" // thumbnail_generated disagrees with storage
+)
+
+// ErrIntegrityCheckRunning is returned when an integrity check is already in progress
+var ErrIntegrityCheckRunning = errors.New("integrity check already running")
+
+// integrityIssue is a problem found by an integrity check
+type integrityIssue struct {
+	kind     string
+	category string
+	key      string
+	docID    *uuid.UUID
+	detail   string
+}
+
+// integrityScan accumulates the results of an integrity check
+type integrityScan struct {
+	stored map[string]bool
+	issues []integrityIssue
+	docs   int
+	files  int
+}
+
+// CheckIntegrity verifies stored files against the documents table
+// Originals, earlier revisions and archive versions are re-hashed and compared
+// to their recorded checksums. Missing and corrupt files, files that belong to
+// no document, and thumbnails that disagree with thumbnail_generated are
+// recorded as issues of a new check, replacing the previous check's results.
+func (s *Service) CheckIntegrity(ctx context.Context) (*sqlc.IntegrityCheck, error) {
+	if !s.integrityMu.TryLock() {
+		return nil, ErrIntegrityCheckRunning
+	}
+	defer s.integrityMu.Unlock()
+
+	check, err := s.db.Queries.CreateIntegrityCheck(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("create integrity check: %w", err)
+	}
+
+	scan, scanErr := s.scanIntegrity(ctx)
+	for _, issue := range scan.issues {
+		params := sqlc.CreateIntegrityIssueParams{
+			CheckID:    check.ID,
+			Kind:       issue.kind,
+			Category:   issue.category,
+			StorageKey: issue.key,
+			Detail:     &issue.detail,
+		}
+		if issue.docID != nil {
+			params.DocumentID = pgtype.UUID{Bytes: *issue.docID, Valid: true}
+		}
+		if err := s.db.Queries.CreateIntegrityIssue(ctx, params); err != nil {
+			return nil, fmt.Errorf("create integrity issue: %w", err)
+		}
+	}
+
+	var errMsg *string
+	if scanErr != nil {
+		msg := scanErr.Error()
+		errMsg = &msg
+	}
+	finished, err := s.db.Queries.FinishIntegrityCheck(ctx, sqlc.FinishIntegrityCheckParams{
+		ID:               check.ID,
+		DocumentsChecked: int32(scan.docs),
+		FilesChecked:     int32(scan.files),
+		Error:            errMsg,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("finish integrity check: %w", err)
+	}
+	if err := s.db.Queries.DeleteOtherIntegrityChecks(ctx, check.ID); err != nil {
+		slog.Warn("failed to delete old integrity checks", "error", err)
+	}
+
+	if scanErr != nil {
+		return &finished, scanErr
+	}
+	slog.Info("integrity check complete", "documents", scan.docs, "files", scan.files, "issues", len(scan.issues))
+	return &finished, nil
+}
+
+// scanIntegrity compares storage with the documents and revisions tables
+// Storage is listed before the tables are read, so a document ingested
+// during the scan can't have its file reported as orphaned.
+func (s *Service) scanIntegrity(ctx context.Context) (*integrityScan, error) {
+	scan := &integrityScan{stored: make(map[string]bool)}
+	for _, cat := range storage.Categories {
+		keys, err := s.storage.List(ctx, cat+"/")
+		if err != nil {
+			return scan, fmt.Errorf("list %s files: %w", cat, err)
+		}
+		for _, key := range keys {
+			scan.stored[key] = true
+		}
+	}
+
+	docs, err := s.db.Queries.ListAllDocuments(ctx)
+	if err != nil {
+		return scan, fmt.Errorf("list documents: %w", err)
+	}
+	revisions, err := s.db.Queries.ListAllDocumentRevisions(ctx)
+	if err != nil {
+		return scan, fmt.Errorf("list document revisions: %w", err)
+	}
+
+	known := make(map[uuid.UUID]bool, len(docs))
+	for i := range docs {
+		if err := ctx.Err(); err != nil {
+			return scan, err
+		}
+		doc := &docs[i]
+		known[doc.ID] = true
+		scan.docs++
+
+		s.expectFile(ctx, scan, doc.ID, storage.CategoryOriginals, s.OriginalKey(doc), doc.ContentHash)
+
+		// Renditions, edits and archives only exist once processing has finished
+		if doc.ProcessingStatus == sqlc.ProcessingStatusCompleted {
+			if doc.ContentType != ContentTypePDF {
+				s.expectFile(ctx, scan, doc.ID, storage.CategoryRenditions, s.RenditionKey(doc), "")
+			}
+			if doc.EditVersion > 0 {
+				s.expectFile(ctx, scan, doc.ID, storage.CategoryRenditions, s.PDFKey(doc), "")
+			}
+			if doc.ArchiveChecksum != nil {
+				s.expectFile(ctx, scan, doc.ID, storage.CategoryArchive, s.ArchiveKey(doc), *doc.ArchiveChecksum)
+			}
+		}
+
+		thumbKey := s.ThumbnailKey(doc)
+		hasThumb := scan.stored[thumbKey]
+		if hasThumb {
+			scan.files++
+		}
+		switch {
+		case doc.ThumbnailGenerated && !hasThumb:
+			scan.add(IssueThumbnailFlag, storage.CategoryThumbnails, thumbKey, &doc.ID, "marked as generated but the thumbnail is missing")
+		case !doc.ThumbnailGenerated && hasThumb && doc.ProcessingStatus == sqlc.ProcessingStatusCompleted:
+			scan.add(IssueThumbnailFlag, storage.CategoryThumbnails, thumbKey, &doc.ID, "thumbnail exists but is not marked as generated")
+		}
+	}
+
+	for i := range revisions {
+		rev := &revisions[i]
+		s.expectFile(ctx, scan, rev.DocumentID, storage.CategoryOriginals, s.RevisionOriginalKey(rev), rev.ContentHash)
+		if rev.EditVersion > 0 {
+			s.expectFile(ctx, scan, rev.DocumentID, storage.CategoryRenditions, s.RevisionPDFKey(rev), "")
+		}
+	}
+
+	// Files are named after their document, so anything else is orphaned
+	for key := range scan.stored {
+		if id, ok := IDFromKey(key); ok && known[id] {
+			continue
+		}
+		category, _, _ := strings.Cut(key, "/")
+		scan.add(IssueOrphaned, category, key, nil, "no document owns this file")
+	}
+
+	return scan, nil
+}
+
+// expectFile records an issue if key is missing or, when wantHash is set,
+// doesn't hash to it
+func (s *Service) expectFile(ctx context.Context, scan *integrityScan, docID uuid.UUID, category, key, wantHash string) {
+	if !scan.stored[key] {
+		scan.add(IssueMissing, category, key, &docID, "file not found in storage")
+		return
+	}
+	scan.files++
+	if wantHash == "" {
+		return
+	}
+
+	hash, err := s.storage.Hash(ctx, key)
+	switch {
+	case err != nil:
+		scan.add(IssueCorrupt, category, key, &docID, fmt.Sprintf("read failed: %v", err))
+	case hash != wantHash:
+		scan.add(IssueCorrupt, category, key, &docID, fmt.Sprintf("hash %.16s... does not match recorded %.16s...", hash, wantHash))
+	}
+}
+
+func (scan *integrityScan) add(kind, category, key string, docID *uuid.UUID, detail string) {
+	scan.issues = append(scan.issues, integrityIssue{kind: kind, category: category, key: key, docID: docID, detail: detail})
+}
+
+// IDFromKey returns the document ID a storage key is named after
+func IDFromKey(key string) (uuid.UUID, bool) {
+	base := path.Base(key)
+	if len(base) < 36 {
+		return uuid.Nil, false
+	}
+	id, err := uuid.Parse(base[:36])
+	return id, err == nil
+}
+
+// Reprocess resets a document to pending and queues it for processing,
+// converting it to PDF first if its type needs conversion
+func (s *Service) Reprocess(ctx context.Context, id uuid.UUID) error {
+	doc, err := s.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Queries.SetDocumentProcessingStatus(ctx, sqlc.SetDocumentProcessingStatusParams{
+		ID:               id,
+		ProcessingStatus: sqlc.ProcessingStatusPending,
+	})
+	if err != nil {
+		return fmt.Errorf("reset status: %w", err)
+	}
+
+	jobType := JobTypeProcess
+	if NeedsConversion(doc.ContentType) {
+		jobType = JobTypeConvert
+	}
+	if _, err := s.queue.Enqueue(ctx, QueueDefault, jobType, IngestPayload{DocumentID: id}); err != nil {
+		return fmt.Errorf("enqueue job: %w", err)
+	}
+	return nil
+}
+
+// RegenerateThumbnail queues a job that renders a document's thumbnail again
+func (s *Service) RegenerateThumbnail(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.queue.Enqueue(ctx, QueueDefault, JobTypeThumbnail, IngestPayload{DocumentID: id}); err != nil {
+		return fmt.Errorf("enqueue job: %w", err)
+	}
+	return nil
+}
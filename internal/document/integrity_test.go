@@ -0,0 +1,29 @@
+package document
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestIDFromKey(t *testing.T) {
+	id := uuid.MustParse("0b6f2a3e-8f3c-4a51-9d7e-2f1c0a9b8e7d")
+
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{"originals/0b/6f/0b6f2a3e-8f3c-4a51-9d7e-2f1c0a9b8e7d.pdf", true},
+		{"originals/0b/6f/0b6f2a3e-8f3c-4a51-9d7e-2f1c0a9b8e7d.v2.docx", true},
+		{"renditions/0b/6f/0b6f2a3e-8f3c-4a51-9d7e-2f1c0a9b8e7d.v1.e3.pdf", true},
+		{"thumbnails/0b/6f/.put-123456", false},
+		{"archive/notes.txt", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := IDFromKey(tt.key)
+		if ok != tt.want || (ok && got != id) {
+			t.Errorf("IDFromKey(%q) = %v, %v; want %v", tt.key, got, ok, tt.want)
+		}
+	}
+}
@@ -0,0 +1,383 @@
+package document
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/bketelsen/docko/internal/database/sqlc"
+	"github.com/bketelsen/docko/internal/pdf"
+	"github.com/bketelsen/docko/internal/storage"
+)
+
+// Page operations recorded in pages_edited events
+const (
+	PageOpRotate  = "rotate"
+	PageOpDelete  = "delete"
+	PageOpReorder = "reorder"
+	PageOpSplit   = "split"
+	PageOpMerge   = "merge"
+)
+
+// ErrSplitDuplicate is returned when the pages split off a document already
+// exist as another document
+var ErrSplitDuplicate = errors.New("split pages already exist as another document")
+
+// ErrEditConflict is returned when a document's pages changed while an edit
+// of them was being prepared
+var ErrEditConflict = errors.New("document was edited concurrently")
+
+// PageCount returns the number of pages in a document's current PDF
+func (s *Service) PageCount(ctx context.Context, doc *sqlc.Document) (int, error) {
+	src, release, err := s.storage.Fetch(ctx, s.PDFKey(doc))
+	if err != nil {
+		return 0, fmt.Errorf("fetch pdf: %w", err)
+	}
+	defer release()
+	return pdf.PageCount(src)
+}
+
+// RotatePages rotates the selected pages clockwise by degrees
+func (s *Service) RotatePages(ctx context.Context, id uuid.UUID, pages []int, degrees int) (*sqlc.Document, error) {
+	doc, err := s.getEditable(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.editPages(ctx, doc, PageOpRotate, map[string]any{"pages": pages, "degrees": degrees},
+		func(src, dst string) error { return pdf.Rotate(src, dst, degrees, pages) }, nil)
+}
+
+// DeletePages removes the selected pages; at least one page must remain
+func (s *Service) DeletePages(ctx context.Context, id uuid.UUID, pages []int) (*sqlc.Document, error) {
+	doc, err := s.getEditable(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	pageCount, err := s.PageCount(ctx, doc)
+	if err != nil {
+		return nil, err
+	}
+	if len(pages) >= pageCount {
+		return nil, fmt.Errorf("%w: can't delete every page", pdf.ErrInvalidPages)
+	}
+	return s.editPages(ctx, doc, PageOpDelete, map[string]any{"pages": pages},
+		func(src, dst string) error { return pdf.RemovePages(src, dst, pages) }, nil)
+}
+
+// ReorderPages puts the pages in the given order, which must list every page once
+func (s *Service) ReorderPages(ctx context.Context, id uuid.UUID, order []int) (*sqlc.Document, error) {
+	doc, err := s.getEditable(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.editPages(ctx, doc, PageOpReorder, map[string]any{"order": order},
+		func(src, dst string) error { return pdf.Reorder(src, dst, order) }, nil)
+}
+
+// SplitDocument splits a document after the given page. The document keeps
+// the pages up to and including afterPage; the rest become a new document
+// with the same tags and correspondent, which is returned.
+func (s *Service) SplitDocument(ctx context.Context, id uuid.UUID, afterPage int) (*sqlc.Document, error) {
+	doc, err := s.getEditable(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	pageCount, err := s.PageCount(ctx, doc)
+	if err != nil {
+		return nil, err
+	}
+	if afterPage < 1 || afterPage >= pageCount {
+		return nil, fmt.Errorf("%w: split must come after a page between 1 and %d", pdf.ErrInvalidPages, pageCount-1)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "split-*")
+	if err != nil {
+		return nil, fmt.Errorf("create temp dir: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	src, release, err := s.storage.Fetch(ctx, s.PDFKey(doc))
+	if err != nil {
+		return nil, fmt.Errorf("fetch pdf: %w", err)
+	}
+	defer release()
+
+	first := pdf.PageRange{First: 1, Last: afterPage}
+	second := pdf.PageRange{First: afterPage + 1, Last: pageCount}
+	parts, err := pdf.Split(src, tmpDir, []pdf.PageRange{first, second})
+	if err != nil {
+		return nil, err
+	}
+
+	// The second part must become a new document: an existing one with the
+	// same pages isn't ours to copy metadata onto, or to restore from the trash
+	hash, err := s.storage.HashFile(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("hash split part: %w", err)
+	}
+	existing, err := s.db.Queries.GetDocumentByHash(ctx, hash)
+	if err == nil {
+		return nil, fmt.Errorf("%w: %s", ErrSplitDuplicate, existing.ID)
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("check duplicate: %w", err)
+	}
+
+	base := strings.TrimSuffix(doc.OriginalFilename, filepath.Ext(doc.OriginalFilename))
+	newDoc, isDuplicate, err := s.Ingest(ctx, parts[1], base+"-part2.pdf")
+	if err != nil {
+		return nil, fmt.Errorf("ingest split part: %w", err)
+	}
+	if isDuplicate {
+		// Ingested by someone else since the check above
+		return nil, fmt.Errorf("%w: %s", ErrSplitDuplicate, newDoc.ID)
+	}
+
+	_, err = s.editPages(ctx, doc, PageOpSplit, map[string]any{"pages": first.String(), "split_into": newDoc.ID},
+		func(_, dst string) error { return os.Rename(parts[0], dst) },
+		func(ctx context.Context, qtx *sqlc.Queries) error {
+			if err := qtx.CopyDocumentTags(ctx, sqlc.CopyDocumentTagsParams{TargetID: newDoc.ID, SourceID: doc.ID}); err != nil {
+				return fmt.Errorf("copy tags: %w", err)
+			}
+			if corr, err := qtx.GetDocumentCorrespondent(ctx, doc.ID); err == nil {
+				if err := qtx.SetDocumentCorrespondent(ctx, sqlc.SetDocumentCorrespondentParams{
+					DocumentID:      newDoc.ID,
+					CorrespondentID: corr.ID,
+				}); err != nil {
+					return fmt.Errorf("copy correspondent: %w", err)
+				}
+			} else if !errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("get correspondent: %w", err)
+			}
+			if err := qtx.CopyDocumentType(ctx, sqlc.CopyDocumentTypeParams{TargetID: newDoc.ID, SourceID: doc.ID}); err != nil {
+				return fmt.Errorf("copy document type: %w", err)
+			}
+			payload, _ := json.Marshal(map[string]any{
+				"parent_id":       doc.ID,
+				"parent_filename": doc.OriginalFilename,
+				"pages":           second.String(),
+			})
+			_, err := qtx.CreateDocumentEvent(ctx, sqlc.CreateDocumentEventParams{
+				DocumentID: newDoc.ID,
+				EventType:  EventSplitFrom,
+				Payload:    payload,
+			})
+			return err
+		})
+	if err != nil {
+		// Don't leave the second part behind as a copy of pages the
+		// document still has
+		s.discard(ctx, newDoc.ID)
+		return nil, err
+	}
+	return newDoc, nil
+}
+
+// discard trashes and purges a document created by an operation that then
+// failed, logging failures
+func (s *Service) discard(ctx context.Context, id uuid.UUID) {
+	if _, err := s.Trash(ctx, id); err != nil {
+		slog.Warn("failed to trash discarded document", "id", id, "error", err)
+		return
+	}
+	if err := s.Purge(ctx, id); err != nil {
+		slog.Warn("failed to purge discarded document", "id", id, "error", err)
+	}
+}
+
+// MergeDocuments appends the pages of the other documents, in order, to the
+// first one. Their tags are added to the first document and they are moved
+// to the trash. Documents already in the trash can't be merged.
+func (s *Service) MergeDocuments(ctx context.Context, ids []uuid.UUID) (*sqlc.Document, error) {
+	if len(ids) < 2 {
+		return nil, fmt.Errorf("merge needs at least two documents")
+	}
+
+	docs := make([]*sqlc.Document, len(ids))
+	srcs := make([]string, len(ids))
+	seen := make(map[uuid.UUID]bool)
+	for i, id := range ids {
+		if seen[id] {
+			return nil, fmt.Errorf("document %s selected twice", id)
+		}
+		seen[id] = true
+		doc, err := s.getEditable(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		docs[i] = doc
+		src, release, err := s.storage.Fetch(ctx, s.PDFKey(doc))
+		if err != nil {
+			return nil, fmt.Errorf("fetch pdf: %w", err)
+		}
+		defer release()
+		srcs[i] = src
+	}
+	target, others := docs[0], docs[1:]
+
+	mergedIDs := make([]uuid.UUID, len(others))
+	for i, other := range others {
+		mergedIDs[i] = other.ID
+	}
+
+	return s.editPages(ctx, target, PageOpMerge, map[string]any{"merged_ids": mergedIDs},
+		func(_, dst string) error { return pdf.Merge(dst, srcs...) },
+		func(ctx context.Context, qtx *sqlc.Queries) error {
+			for _, other := range others {
+				if err := qtx.CopyDocumentTags(ctx, sqlc.CopyDocumentTagsParams{TargetID: target.ID, SourceID: other.ID}); err != nil {
+					return fmt.Errorf("merge tags: %w", err)
+				}
+				if _, err := qtx.TrashDocument(ctx, other.ID); err != nil && !errors.Is(err, pgx.ErrNoRows) {
+					return fmt.Errorf("trash merged document: %w", err)
+				}
+				payload, _ := json.Marshal(map[string]any{
+					"merged_into": target.ID,
+				})
+				if _, err := qtx.CreateDocumentEvent(ctx, sqlc.CreateDocumentEventParams{
+					DocumentID: other.ID,
+					EventType:  EventTrashed,
+					Payload:    payload,
+				}); err != nil {
+					return fmt.Errorf("create event: %w", err)
+				}
+			}
+			return nil
+		})
+}
+
+// getEditable returns a document whose pages may be edited
+// Returns ErrTrashed for documents in the trash.
+func (s *Service) getEditable(ctx context.Context, id uuid.UUID) (*sqlc.Document, error) {
+	doc, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if doc.DeletedAt.Valid {
+		return nil, fmt.Errorf("%w: %s", ErrTrashed, id)
+	}
+	return doc, nil
+}
+
+// editPages writes a new edit version of a document's PDF with apply, which
+// reads the current PDF from the local file src and writes the result to the
+// local file dst. In one transaction it records the version, logs a
+// pages_edited event, runs inTx (if set) and queues the document for
+// reprocessing. The document's row stays locked throughout, so concurrent
+// edits can't both write the same version; an edit prepared against an
+// older version fails with ErrEditConflict.
+func (s *Service) editPages(ctx context.Context, doc *sqlc.Document, op string, details map[string]any, apply func(src, dst string) error, inTx func(context.Context, *sqlc.Queries) error) (*sqlc.Document, error) {
+	start := time.Now()
+
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	qtx := s.db.Queries.WithTx(tx)
+
+	current, err := qtx.LockDocument(ctx, doc.ID)
+	if err != nil {
+		return nil, fmt.Errorf("lock document: %w", err)
+	}
+	if current.DeletedAt.Valid {
+		return nil, fmt.Errorf("%w: %s", ErrTrashed, doc.ID)
+	}
+	if current.Revision != doc.Revision || current.EditVersion != doc.EditVersion {
+		return nil, fmt.Errorf("%w: %s", ErrEditConflict, doc.ID)
+	}
+
+	src, release, err := s.storage.Fetch(ctx, s.PDFKey(doc))
+	if err != nil {
+		return nil, fmt.Errorf("fetch pdf: %w", err)
+	}
+	defer release()
+
+	tmpDir, err := os.MkdirTemp("", "edit-*")
+	if err != nil {
+		return nil, fmt.Errorf("create temp dir: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	version := doc.EditVersion + 1
+	dst := filepath.Join(tmpDir, "edited.pdf")
+	dstKey := s.editedKey(doc.ID, doc.Revision, version)
+
+	if err := apply(src, dst); err != nil {
+		return nil, fmt.Errorf("%s pages: %w", op, err)
+	}
+
+	pageCount, err := pdf.PageCount(dst)
+	if err != nil {
+		return nil, err
+	}
+	pages := int32(pageCount)
+
+	if err := s.storage.Store(ctx, dstKey, dst); err != nil {
+		return nil, fmt.Errorf("store edited pdf: %w", err)
+	}
+
+	updated, err := qtx.SetDocumentEditVersion(ctx, sqlc.SetDocumentEditVersionParams{
+		ID:          doc.ID,
+		EditVersion: version,
+		PageCount:   &pages,
+	})
+	if err != nil {
+		_ = s.storage.Delete(ctx, dstKey)
+		return nil, fmt.Errorf("set edit version: %w", err)
+	}
+
+	if inTx != nil {
+		if err := inTx(ctx, qtx); err != nil {
+			_ = s.storage.Delete(ctx, dstKey)
+			return nil, err
+		}
+	}
+
+	payload := map[string]any{
+		"operation":    op,
+		"edit_version": version,
+		"page_count":   pageCount,
+	}
+	for k, v := range details {
+		payload[k] = v
+	}
+	eventPayload, _ := json.Marshal(payload)
+	_, err = qtx.CreateDocumentEvent(ctx, sqlc.CreateDocumentEventParams{
+		DocumentID: doc.ID,
+		EventType:  EventPagesEdited,
+		Payload:    eventPayload,
+		DurationMs: intPtr(int32(time.Since(start).Milliseconds())),
+	})
+	if err != nil {
+		_ = s.storage.Delete(ctx, dstKey)
+		return nil, fmt.Errorf("create event: %w", err)
+	}
+
+	if _, err := s.queue.EnqueueTx(ctx, qtx, QueueDefault, JobTypeProcess, IngestPayload{DocumentID: doc.ID}); err != nil {
+		_ = s.storage.Delete(ctx, dstKey)
+		return nil, fmt.Errorf("enqueue job: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		_ = s.storage.Delete(ctx, dstKey)
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	slog.Info("document pages edited", "id", doc.ID, "operation", op, "edit_version", version, "pages", pageCount)
+	return &updated, nil
+}
+
+// editedKey returns {uuid}.v{revision}.e{version}.pdf in renditions
+func (s *Service) editedKey(id uuid.UUID, revision, version int32) string {
+	return storage.KeyForUUID(storage.CategoryRenditions, id, fmt.Sprintf(".v%d.e%d.pdf", revision, version))
+}
@@ -0,0 +1,54 @@
+package document
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+
+	"github.com/bketelsen/docko/internal/database/sqlc"
+	"github.com/bketelsen/docko/internal/pdf"
+)
+
+// Unlock decrypts a password-protected PDF with a password entered by hand,
+// keeps the decrypted copy as the archive version and queues the document
+// for processing, which reads the archive instead of the original.
+// Returns pdf.ErrWrongPassword if the password doesn't open it.
+func (s *Service) Unlock(ctx context.Context, id uuid.UUID, password string) error {
+	doc, err := s.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	src, release, err := s.storage.Fetch(ctx, s.PDFKey(doc))
+	if err != nil {
+		return fmt.Errorf("fetch pdf: %w", err)
+	}
+	defer release()
+
+	tmpDir, err := os.MkdirTemp("", "unlock-*")
+	if err != nil {
+		return fmt.Errorf("create temp dir: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	dst := filepath.Join(tmpDir, "unlocked.pdf")
+	if err := pdf.Decrypt(src, dst, password); err != nil {
+		return err
+	}
+	checksum, _, err := s.storage.StoreAndHash(ctx, s.ArchiveKey(doc), dst)
+	if err != nil {
+		return fmt.Errorf("store archive: %w", err)
+	}
+	if err := s.db.Queries.SetDocumentArchive(ctx, sqlc.SetDocumentArchiveParams{
+		ID:              id,
+		ArchiveChecksum: &checksum,
+	}); err != nil {
+		return fmt.Errorf("set document archive: %w", err)
+	}
+
+	_ = s.LogEvent(ctx, id, EventUnlocked, nil, nil, 0)
+	return s.Reprocess(ctx, id)
+}
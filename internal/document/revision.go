@@ -0,0 +1,208 @@
+package document
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/bketelsen/docko/internal/database/sqlc"
+	"github.com/bketelsen/docko/internal/storage"
+)
+
+// ErrDuplicateRevision is returned when a replacement file matches an existing document
+var ErrDuplicateRevision = errors.New("file already stored as a document")
+
+// Replace stores a new revision of a document's file, keeping its tags,
+// correspondent and history. The prior original (and rendition) are archived
+// under the revision number and the new file is queued for processing.
+// Documents in the trash can't be revised.
+func (s *Service) Replace(ctx context.Context, docID uuid.UUID, sourcePath, originalFilename string) (*sqlc.Document, error) {
+	start := time.Now()
+
+	doc, err := s.getEditable(ctx, docID)
+	if err != nil {
+		return nil, err
+	}
+
+	contentType, err := DetectContentType(sourcePath)
+	if err != nil {
+		return nil, fmt.Errorf("detect content type: %w", err)
+	}
+	if contentType == "" || contentType == ContentTypeMbox {
+		return nil, ErrUnsupportedType
+	}
+
+	contentHash, err := s.storage.HashFile(sourcePath)
+	if err != nil {
+		return nil, fmt.Errorf("hash file: %w", err)
+	}
+	if _, err := s.db.Queries.GetDocumentByHash(ctx, contentHash); err == nil {
+		return nil, ErrDuplicateRevision
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("check duplicate: %w", err)
+	}
+
+	// Archive the current original and rendition under the current revision
+	currentOriginal := s.OriginalKey(doc)
+	currentRendition := s.RenditionKey(doc)
+	archivedOriginal := s.revisionOriginalKey(doc.ID, doc.Revision, doc.OriginalFilename)
+	archivedRendition := s.revisionRenditionKey(doc.ID, doc.Revision)
+
+	if err := s.storage.Move(ctx, currentOriginal, archivedOriginal); err != nil {
+		return nil, fmt.Errorf("archive original: %w", err)
+	}
+	renditionArchived := false
+	if doc.ContentType != ContentTypePDF && s.storage.FileExists(ctx, currentRendition) {
+		if err := s.storage.Move(ctx, currentRendition, archivedRendition); err != nil {
+			_ = s.storage.Move(ctx, archivedOriginal, currentOriginal)
+			return nil, fmt.Errorf("archive rendition: %w", err)
+		}
+		renditionArchived = true
+	}
+
+	newDoc := *doc
+	newDoc.OriginalFilename = originalFilename
+	newDoc.ContentType = contentType
+	destKey := s.OriginalKey(&newDoc)
+
+	// undo puts the archived files back if the replacement fails
+	undo := func() {
+		_ = s.storage.Delete(ctx, destKey)
+		_ = s.storage.Delete(ctx, currentRendition)
+		_ = s.storage.Move(ctx, archivedOriginal, currentOriginal)
+		if renditionArchived {
+			_ = s.storage.Move(ctx, archivedRendition, currentRendition)
+		}
+	}
+
+	_, fileSize, err := s.storage.StoreAndHash(ctx, destKey, sourcePath)
+	if err != nil {
+		undo()
+		return nil, fmt.Errorf("store file: %w", err)
+	}
+
+	if IsImage(contentType) {
+		if err := s.storeImageRendition(ctx, currentRendition, contentType, sourcePath); err != nil {
+			undo()
+			return nil, err
+		}
+	}
+
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		undo()
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	qtx := s.db.Queries.WithTx(tx)
+
+	_, err = qtx.CreateDocumentRevision(ctx, sqlc.CreateDocumentRevisionParams{
+		DocumentID:       doc.ID,
+		Revision:         doc.Revision,
+		OriginalFilename: doc.OriginalFilename,
+		ContentHash:      doc.ContentHash,
+		FileSize:         doc.FileSize,
+		ContentType:      doc.ContentType,
+		EditVersion:      doc.EditVersion,
+	})
+	if err != nil {
+		undo()
+		return nil, fmt.Errorf("create revision: %w", err)
+	}
+
+	updated, err := qtx.ReplaceDocumentFile(ctx, sqlc.ReplaceDocumentFileParams{
+		ID:               doc.ID,
+		OriginalFilename: originalFilename,
+		ContentHash:      contentHash,
+		FileSize:         fileSize,
+		ContentType:      contentType,
+	})
+	if err != nil {
+		undo()
+		return nil, fmt.Errorf("replace document file: %w", err)
+	}
+
+	eventPayload, _ := json.Marshal(map[string]any{
+		"revision":          updated.Revision,
+		"previous_revision": doc.Revision,
+		"previous_filename": doc.OriginalFilename,
+		"previous_hash":     doc.ContentHash,
+		"filename":          originalFilename,
+		"hash":              contentHash,
+		"content_type":      contentType,
+	})
+	_, err = qtx.CreateDocumentEvent(ctx, sqlc.CreateDocumentEventParams{
+		DocumentID: doc.ID,
+		EventType:  EventRevised,
+		Payload:    eventPayload,
+		DurationMs: intPtr(int32(time.Since(start).Milliseconds())),
+	})
+	if err != nil {
+		undo()
+		return nil, fmt.Errorf("create event: %w", err)
+	}
+
+	jobType := JobTypeProcess
+	if NeedsConversion(contentType) {
+		jobType = JobTypeConvert
+	}
+	if _, err := s.queue.EnqueueTx(ctx, qtx, QueueDefault, jobType, IngestPayload{DocumentID: doc.ID}); err != nil {
+		undo()
+		return nil, fmt.Errorf("enqueue job: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		undo()
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	slog.Info("document revised", "id", doc.ID, "revision", updated.Revision, "filename", originalFilename, "type", contentType)
+	return &updated, nil
+}
+
+// GetRevision retrieves an archived revision of a document
+func (s *Service) GetRevision(ctx context.Context, docID uuid.UUID, revision int32) (*sqlc.DocumentRevision, error) {
+	rev, err := s.db.Queries.GetDocumentRevision(ctx, sqlc.GetDocumentRevisionParams{
+		DocumentID: docID,
+		Revision:   revision,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get revision: %w", err)
+	}
+	return &rev, nil
+}
+
+// RevisionOriginalKey returns the storage key of an archived revision's original
+func (s *Service) RevisionOriginalKey(rev *sqlc.DocumentRevision) string {
+	return s.revisionOriginalKey(rev.DocumentID, rev.Revision, rev.OriginalFilename)
+}
+
+// RevisionPDFKey returns the PDF to view for an archived revision: its latest
+// page edit if it was edited, else its original or rendition
+func (s *Service) RevisionPDFKey(rev *sqlc.DocumentRevision) string {
+	if rev.EditVersion > 0 {
+		return s.editedKey(rev.DocumentID, rev.Revision, rev.EditVersion)
+	}
+	if rev.ContentType == ContentTypePDF {
+		return s.RevisionOriginalKey(rev)
+	}
+	return s.revisionRenditionKey(rev.DocumentID, rev.Revision)
+}
+
+// revisionOriginalKey returns {uuid}.v{revision}{ext} in originals
+func (s *Service) revisionOriginalKey(id uuid.UUID, revision int32, filename string) string {
+	return storage.KeyForUUID(storage.CategoryOriginals, id, fmt.Sprintf(".v%d%s", revision, filepath.Ext(filename)))
+}
+
+// revisionRenditionKey returns {uuid}.v{revision}.pdf in renditions
+func (s *Service) revisionRenditionKey(id uuid.UUID, revision int32) string {
+	return storage.KeyForUUID(storage.CategoryRenditions, id, fmt.Sprintf(".v%d.pdf", revision))
+}
@@ -0,0 +1,44 @@
+package document
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/bketelsen/docko/internal/database/sqlc"
+)
+
+func TestRevisionPDFKey(t *testing.T) {
+	s := &Service{}
+	id := uuid.MustParse("0b6f2a3e-8f3c-4a51-9d7e-2f1c0a9b8e7d")
+
+	tests := []struct {
+		name string
+		rev  sqlc.DocumentRevision
+		want string
+	}{
+		{
+			name: "pdf",
+			rev:  sqlc.DocumentRevision{DocumentID: id, Revision: 1, OriginalFilename: "scan.pdf", ContentType: ContentTypePDF},
+			want: "originals/0b/6f/0b6f2a3e-8f3c-4a51-9d7e-2f1c0a9b8e7d.v1.pdf",
+		},
+		{
+			name: "rendition",
+			rev:  sqlc.DocumentRevision{DocumentID: id, Revision: 2, OriginalFilename: "letter.docx", ContentType: "application/vnd.openxmlformats-officedocument.wordprocessingml.document"},
+			want: "renditions/0b/6f/0b6f2a3e-8f3c-4a51-9d7e-2f1c0a9b8e7d.v2.pdf",
+		},
+		{
+			name: "edited",
+			rev:  sqlc.DocumentRevision{DocumentID: id, Revision: 1, OriginalFilename: "scan.pdf", ContentType: ContentTypePDF, EditVersion: 3},
+			want: "renditions/0b/6f/0b6f2a3e-8f3c-4a51-9d7e-2f1c0a9b8e7d.v1.e3.pdf",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.RevisionPDFKey(&tt.rev); got != tt.want {
+				t.Errorf("RevisionPDFKey() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package document
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/bketelsen/docko/internal/database/sqlc"
+)
+
+// ErrNotTrashed is returned when restoring or purging a document that isn't in the trash
+var ErrNotTrashed = errors.New("document is not in the trash")
+
+// ErrTrashed is returned when editing a document that is in the trash
+var ErrTrashed = errors.New("document is in the trash")
+
+// Trash moves a document to the trash, hiding it from search
+// Trashing an already trashed document is a no-op.
+func (s *Service) Trash(ctx context.Context, id uuid.UUID) (*sqlc.Document, error) {
+	doc, err := s.db.Queries.TrashDocument(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Either missing or already trashed
+		return s.GetByID(ctx, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("trash document: %w", err)
+	}
+
+	_ = s.LogEvent(ctx, id, EventTrashed, nil, nil, 0)
+	slog.Info("document trashed", "id", id, "filename", doc.OriginalFilename)
+	return &doc, nil
+}
+
+// Restore takes a document out of the trash
+func (s *Service) Restore(ctx context.Context, id uuid.UUID) (*sqlc.Document, error) {
+	doc, err := s.db.Queries.RestoreDocument(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotTrashed
+	}
+	if err != nil {
+		return nil, fmt.Errorf("restore document: %w", err)
+	}
+
+	_ = s.LogEvent(ctx, id, EventRestored, nil, nil, 0)
+	slog.Info("document restored", "id", id, "filename", doc.OriginalFilename)
+	return &doc, nil
+}
+
+// Purge permanently deletes a trashed document's row and its files in every storage category
+func (s *Service) Purge(ctx context.Context, id uuid.UUID) error {
+	doc, err := s.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !doc.DeletedAt.Valid {
+		return ErrNotTrashed
+	}
+
+	// Delete the row first so a failed file cleanup never leaves a row
+	// pointing at missing files
+	if err := s.db.Queries.DeleteDocument(ctx, id); err != nil {
+		return fmt.Errorf("delete document: %w", err)
+	}
+	if err := s.storage.DeleteAllForUUID(ctx, id); err != nil {
+		slog.Warn("failed to delete purged document files", "id", id, "error", err)
+	}
+
+	slog.Info("document purged", "id", id, "filename", doc.OriginalFilename)
+	return nil
+}
+
+// EmptyTrash purges every trashed document
+// Returns the number of documents purged.
+func (s *Service) EmptyTrash(ctx context.Context) (int, error) {
+	docs, err := s.db.Queries.ListTrashedDocuments(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("list trashed documents: %w", err)
+	}
+	return s.purgeAll(ctx, docs), nil
+}
+
+// PurgeExpiredTrash purges documents trashed longer than retention ago
+// Returns the number of documents purged.
+func (s *Service) PurgeExpiredTrash(ctx context.Context, retention time.Duration) (int, error) {
+	cutoff := pgtype.Timestamptz{Time: time.Now().Add(-retention), Valid: true}
+	docs, err := s.db.Queries.ListTrashedDocumentsBefore(ctx, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("list expired trash: %w", err)
+	}
+	return s.purgeAll(ctx, docs), nil
+}
+
+// purgeAll purges the given documents, logging failures
+func (s *Service) purgeAll(ctx context.Context, docs []sqlc.Document) int {
+	purged := 0
+	for _, doc := range docs {
+		if err := s.Purge(ctx, doc.ID); err != nil {
+			slog.Warn("failed to purge document", "id", doc.ID, "error", err)
+			continue
+		}
+		purged++
+	}
+	return purged
+}
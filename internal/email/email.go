@@ -0,0 +1,258 @@
+package email
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// Message is a parsed email with its decoded bodies and attachments
+type Message struct {
+	From        string    // Display name, or the address when no name is given
+	FromAddress string    // Bare sender address
+	To          string    // Raw To header
+	Subject     string    // Decoded subject
+	Date        time.Time // Zero if the Date header is missing or invalid
+	TextBody    string
+	HTMLBody    string
+	Attachments []Attachment
+}
+
+// Attachment is a decoded attachment part
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// IsPDF reports whether the attachment is a PDF by content type or filename
+func (a Attachment) IsPDF() bool {
+	return a.ContentType == "application/pdf" ||
+		strings.EqualFold(filepath.Ext(a.Filename), ".pdf")
+}
+
+// wordDecoder decodes RFC 2047 encoded words in any charset htmlindex knows
+var wordDecoder = &mime.WordDecoder{CharsetReader: charsetReader}
+
+// ParseFile parses the email stored at path
+func ParseFile(path string) (*Message, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open message: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	return Parse(f)
+}
+
+// Parse reads an RFC 5322 message and decodes its MIME structure
+func Parse(r io.Reader) (*Message, error) {
+	m, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, fmt.Errorf("read message: %w", err)
+	}
+
+	msg := &Message{
+		To:      m.Header.Get("To"),
+		Subject: decodeHeader(m.Header.Get("Subject")),
+	}
+
+	parser := mail.AddressParser{WordDecoder: wordDecoder}
+	if from, err := parser.Parse(m.Header.Get("From")); err == nil {
+		msg.FromAddress = from.Address
+		msg.From = from.Name
+		if msg.From == "" {
+			msg.From = from.Address
+		}
+	} else {
+		msg.From = decodeHeader(m.Header.Get("From"))
+	}
+
+	if date, err := m.Header.Date(); err == nil {
+		msg.Date = date
+	}
+
+	header := partHeader{
+		contentType: m.Header.Get("Content-Type"),
+		encoding:    m.Header.Get("Content-Transfer-Encoding"),
+		disposition: m.Header.Get("Content-Disposition"),
+	}
+	if err := msg.walk(header, m.Body); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// partHeader holds the MIME headers needed to decode a part
+type partHeader struct {
+	contentType string
+	encoding    string
+	disposition string
+}
+
+// walk decodes a MIME part, recursing into multiparts
+func (msg *Message) walk(h partHeader, body io.Reader) error {
+	mediaType, params, err := mime.ParseMediaType(h.contentType)
+	if err != nil {
+		mediaType, params = "text/plain", map[string]string{}
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		mr := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := mr.NextRawPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("read multipart: %w", err)
+			}
+			child := partHeader{
+				contentType: part.Header.Get("Content-Type"),
+				encoding:    part.Header.Get("Content-Transfer-Encoding"),
+				disposition: part.Header.Get("Content-Disposition"),
+			}
+			if err := msg.walk(child, part); err != nil {
+				return err
+			}
+		}
+	}
+
+	data, err := io.ReadAll(decodeTransfer(h.encoding, body))
+	if err != nil {
+		return fmt.Errorf("decode part: %w", err)
+	}
+
+	disposition, dispParams, _ := mime.ParseMediaType(h.disposition)
+	filename := decodeHeader(dispParams["filename"])
+	if filename == "" {
+		filename = decodeHeader(params["name"])
+	}
+
+	isBody := disposition != "attachment" && filename == ""
+	switch {
+	case isBody && mediaType == "text/plain" && msg.TextBody == "":
+		msg.TextBody = toUTF8(data, params["charset"])
+	case isBody && mediaType == "text/html" && msg.HTMLBody == "":
+		msg.HTMLBody = toUTF8(data, params["charset"])
+	case !isBody || !strings.HasPrefix(mediaType, "text/"):
+		msg.Attachments = append(msg.Attachments, Attachment{
+			Filename:    filename,
+			ContentType: mediaType,
+			Data:        data,
+		})
+	}
+	return nil
+}
+
+// decodeTransfer wraps body with a Content-Transfer-Encoding decoder
+func decodeTransfer(encoding string, body io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	default:
+		return body
+	}
+}
+
+// decodeHeader decodes RFC 2047 encoded words, returning the raw value on failure
+func decodeHeader(s string) string {
+	decoded, err := wordDecoder.DecodeHeader(s)
+	if err != nil {
+		return s
+	}
+	return decoded
+}
+
+// charsetReader converts a named charset to UTF-8 for the word decoder
+func charsetReader(charset string, input io.Reader) (io.Reader, error) {
+	enc, err := htmlindex.Get(charset)
+	if err != nil {
+		return nil, err
+	}
+	return enc.NewDecoder().Reader(input), nil
+}
+
+// toUTF8 converts body text in the given charset to UTF-8
+func toUTF8(data []byte, charset string) string {
+	if charset == "" || strings.EqualFold(charset, "utf-8") || strings.EqualFold(charset, "us-ascii") {
+		return string(data)
+	}
+	enc, err := htmlindex.Get(charset)
+	if err != nil {
+		return string(data)
+	}
+	out, err := enc.NewDecoder().Bytes(data)
+	if err != nil {
+		return string(data)
+	}
+	return string(out)
+}
+
+// bodyTagPattern finds the opening body tag of an HTML message
+var bodyTagPattern = regexp.MustCompile(`(?i)<body[^>]*>`)
+
+// RenderHTML renders the message as a standalone HTML page with a header block
+// The result is what gets converted into the message's PDF rendition; HTML
+// bodies are sanitized so converting them fetches nothing remote.
+func (msg *Message) RenderHTML() string {
+	var header strings.Builder
+	header.WriteString(`<table style="font-family: sans-serif; font-size: 10pt; margin-bottom: 12pt">`)
+	row := func(label, value string) {
+		if value == "" {
+			return
+		}
+		fmt.Fprintf(&header, `<tr><td style="font-weight: bold; padding-right: 12pt">%s</td><td>%s</td></tr>`,
+			label, html.EscapeString(value))
+	}
+	from := msg.From
+	if msg.FromAddress != "" && msg.FromAddress != msg.From {
+		from = fmt.Sprintf("%s <%s>", msg.From, msg.FromAddress)
+	}
+	row("From", from)
+	row("To", decodeHeader(msg.To))
+	if !msg.Date.IsZero() {
+		row("Date", msg.Date.Format("Monday, January 2, 2006 3:04 PM MST"))
+	}
+	row("Subject", msg.Subject)
+	var names []string
+	for _, a := range msg.Attachments {
+		if a.Filename != "" {
+			names = append(names, a.Filename)
+		}
+	}
+	row("Attachments", strings.Join(names, ", "))
+	header.WriteString(`</table><hr/>`)
+
+	if msg.HTMLBody != "" {
+		body := SanitizeHTML(msg.HTMLBody)
+		if loc := bodyTagPattern.FindStringIndex(body); loc != nil {
+			return body[:loc[1]] + header.String() + body[loc[1]:]
+		}
+		return header.String() + body
+	}
+
+	var page bytes.Buffer
+	page.WriteString(`<!DOCTYPE html><html><head><meta charset="utf-8"/><title>`)
+	page.WriteString(html.EscapeString(msg.Subject))
+	page.WriteString(`</title></head><body>`)
+	page.WriteString(header.String())
+	page.WriteString(`<pre style="white-space: pre-wrap; font-family: sans-serif">`)
+	page.WriteString(html.EscapeString(msg.TextBody))
+	page.WriteString(`</pre></body></html>`)
+	return page.String()
+}
@@ -0,0 +1,156 @@
+package email
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const multipartMessage = "From: =?utf-8?q?J=C3=B6rg_Utility?= <billing@utility.example>\r\n" +
+	"To: home@example.com\r\n" +
+	"Subject: =?utf-8?b?WW91ciBiaWxsIOKAkyBNYXJjaA==?=\r\n" +
+	"Date: Tue, 05 Mar 2024 09:30:00 +0100\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/mixed; boundary=\"outer\"\r\n" +
+	"\r\n" +
+	"--outer\r\n" +
+	"Content-Type: multipart/alternative; boundary=\"inner\"\r\n" +
+	"\r\n" +
+	"--inner\r\n" +
+	"Content-Type: text/plain; charset=iso-8859-1\r\n" +
+	"Content-Transfer-Encoding: quoted-printable\r\n" +
+	"\r\n" +
+	"Gr=FC=DFe, your bill is attached.\r\n" +
+	"--inner\r\n" +
+	"Content-Type: text/html; charset=utf-8\r\n" +
+	"\r\n" +
+	"<html><body><p>Your bill is attached.</p></body></html>\r\n" +
+	"--inner--\r\n" +
+	"--outer\r\n" +
+	"Content-Type: application/pdf; name=\"bill.pdf\"\r\n" +
+	"Content-Disposition: attachment; filename=\"bill.pdf\"\r\n" +
+	"Content-Transfer-Encoding: base64\r\n" +
+	"\r\n" +
+	"JVBERi0xLjQK\r\n" +
+	"--outer\r\n" +
+	"Content-Type: image/png\r\n" +
+	"Content-Disposition: attachment; filename=\"logo.png\"\r\n" +
+	"Content-Transfer-Encoding: base64\r\n" +
+	"\r\n" +
+	"iVBORw0KGgo=\r\n" +
+	"--outer--\r\n"
+
+func TestParse_Multipart(t *testing.T) {
+	msg, err := Parse(strings.NewReader(multipartMessage))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if msg.From != "Jörg Utility" {
+		t.Errorf("From = %q, want %q", msg.From, "Jörg Utility")
+	}
+	if msg.FromAddress != "billing@utility.example" {
+		t.Errorf("FromAddress = %q", msg.FromAddress)
+	}
+	if msg.Subject != "Your bill – March" {
+		t.Errorf("Subject = %q", msg.Subject)
+	}
+	wantDate := time.Date(2024, 3, 5, 8, 30, 0, 0, time.UTC)
+	if !msg.Date.Equal(wantDate) {
+		t.Errorf("Date = %v, want %v", msg.Date, wantDate)
+	}
+	if !strings.HasPrefix(msg.TextBody, "Grüße, your bill") {
+		t.Errorf("TextBody = %q", msg.TextBody)
+	}
+	if !strings.Contains(msg.HTMLBody, "<p>Your bill is attached.</p>") {
+		t.Errorf("HTMLBody = %q", msg.HTMLBody)
+	}
+
+	if len(msg.Attachments) != 2 {
+		t.Fatalf("got %d attachments, want 2", len(msg.Attachments))
+	}
+	pdf := msg.Attachments[0]
+	if pdf.Filename != "bill.pdf" || !pdf.IsPDF() {
+		t.Errorf("first attachment = %q (%s), want bill.pdf", pdf.Filename, pdf.ContentType)
+	}
+	if string(pdf.Data) != "%PDF-1.4\n" {
+		t.Errorf("pdf data = %q", pdf.Data)
+	}
+	if msg.Attachments[1].IsPDF() {
+		t.Error("png attachment reported as PDF")
+	}
+}
+
+func TestParse_PlainText(t *testing.T) {
+	raw := "From: billing@utility.example\r\nSubject: Hello\r\n\r\nPlain body\r\n"
+	msg, err := Parse(strings.NewReader(raw))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if msg.From != "billing@utility.example" {
+		t.Errorf("From = %q, want address fallback", msg.From)
+	}
+	if !msg.Date.IsZero() {
+		t.Errorf("Date = %v, want zero", msg.Date)
+	}
+	if msg.TextBody != "Plain body\r\n" {
+		t.Errorf("TextBody = %q", msg.TextBody)
+	}
+	if len(msg.Attachments) != 0 {
+		t.Errorf("got %d attachments, want 0", len(msg.Attachments))
+	}
+}
+
+func TestRenderHTML(t *testing.T) {
+	msg := &Message{
+		From:        "Utility <Co>",
+		FromAddress: "billing@utility.example",
+		Subject:     "Bill",
+		TextBody:    "Amount due: 5 < 6",
+	}
+	out := msg.RenderHTML()
+	if !strings.Contains(out, "Utility &lt;Co&gt; &lt;billing@utility.example&gt;") {
+		t.Errorf("sender not escaped in header: %s", out)
+	}
+	if !strings.Contains(out, "Amount due: 5 &lt; 6") {
+		t.Errorf("text body not escaped: %s", out)
+	}
+
+	msg.HTMLBody = "<html><BODY class=\"x\"><p>Hi</p></BODY></html>"
+	out = msg.RenderHTML()
+	if !strings.HasPrefix(out, "<html><BODY class=\"x\"><table") {
+		t.Errorf("header not inserted after body tag: %s", out)
+	}
+}
+
+func TestSplitMbox(t *testing.T) {
+	mbox := "From alice@example.com Mon Jan  1 00:00:00 2024\n" +
+		"Subject: one\n" +
+		"\n" +
+		"First body\n" +
+		">From the archive\n" +
+		"\n" +
+		"From bob@example.com Tue Jan  2 00:00:00 2024\n" +
+		"Subject: two\n" +
+		"\n" +
+		"Second body\n"
+
+	msgs, err := SplitMbox(strings.NewReader(mbox))
+	if err != nil {
+		t.Fatalf("SplitMbox failed: %v", err)
+	}
+	if len(msgs) != 2 {
+		t.Fatalf("got %d messages, want 2", len(msgs))
+	}
+	if want := "Subject: one\n\nFirst body\nFrom the archive\n"; string(msgs[0]) != want {
+		t.Errorf("message 1 = %q, want %q", msgs[0], want)
+	}
+
+	second, err := Parse(strings.NewReader(string(msgs[1])))
+	if err != nil {
+		t.Fatalf("parse second message: %v", err)
+	}
+	if second.Subject != "two" {
+		t.Errorf("second subject = %q", second.Subject)
+	}
+}
@@ -0,0 +1,67 @@
+package email
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// SplitMbox splits an mbox file into raw RFC 5322 messages
+// Each message starts at a "From " separator line. Quoted ">From " lines
+// (mboxrd) are unescaped by one level.
+func SplitMbox(r io.Reader) ([][]byte, error) {
+	br := bufio.NewReader(r)
+
+	var messages [][]byte
+	var current *bytes.Buffer
+	prevBlank := true
+
+	for {
+		line, err := br.ReadBytes('\n')
+		if len(line) > 0 {
+			switch {
+			case prevBlank && bytes.HasPrefix(line, []byte("From ")):
+				if current != nil {
+					messages = append(messages, trimSeparator(current.Bytes()))
+				}
+				current = &bytes.Buffer{}
+			case current != nil:
+				if isQuotedFrom(line) {
+					line = line[1:]
+				}
+				current.Write(line)
+			}
+			trimmed := bytes.TrimRight(line, "\r\n")
+			prevBlank = len(trimmed) == 0
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read mbox: %w", err)
+		}
+	}
+
+	if current != nil {
+		messages = append(messages, trimSeparator(current.Bytes()))
+	}
+	return messages, nil
+}
+
+// isQuotedFrom matches mboxrd-escaped lines like ">From " and ">>From "
+func isQuotedFrom(line []byte) bool {
+	rest := bytes.TrimLeft(line, ">")
+	return len(rest) < len(line) && bytes.HasPrefix(rest, []byte("From "))
+}
+
+// trimSeparator drops the blank line that precedes the next "From " separator
+func trimSeparator(msg []byte) []byte {
+	if bytes.HasSuffix(msg, []byte("\r\n")) {
+		return msg[:len(msg)-2]
+	}
+	if bytes.HasSuffix(msg, []byte("\n")) {
+		return msg[:len(msg)-1]
+	}
+	return msg
+}
@@ -0,0 +1,162 @@
+package email
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// droppedElements are removed from HTML bodies along with their content:
+// they run code, embed other documents or pull in remote resources
+var droppedElements = map[string]bool{
+	"script":   true,
+	"iframe":   true,
+	"frame":    true,
+	"frameset": true,
+	"object":   true,
+	"embed":    true,
+	"applet":   true,
+	"link":     true,
+	"base":     true,
+	"meta":     true,
+	"noscript": true,
+}
+
+// resourceAttributes hold URLs a renderer fetches while laying out the page
+var resourceAttributes = map[string]bool{
+	"src":        true,
+	"srcset":     true,
+	"lowsrc":     true,
+	"dynsrc":     true,
+	"background": true,
+	"poster":     true,
+	"data":       true,
+	"codebase":   true,
+	"xlink:href": true,
+}
+
+// cssURLPattern finds url() references and @import rules in CSS
+var cssURLPattern = regexp.MustCompile(`(?i)url\s*\([^)]*\)|@import[^;]*;?`)
+
+// SanitizeHTML strips everything from an email's HTML body that would make
+// a renderer fetch remote content or run code: scripts, frames, embedded
+// objects, stylesheet links, event handlers and resource URLs other than
+// inline data: and cid: references. Converting a message must not send
+// tracking beacons or requests from the docko host on the sender's behalf.
+func SanitizeHTML(body string) string {
+	var out bytes.Buffer
+	z := html.NewTokenizer(strings.NewReader(body))
+
+	skipping, depth := "", 0
+	inStyle := false
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			// io.EOF, or input the tokenizer can't make sense of, which is left out
+			return out.String()
+		}
+
+		tok := z.Token()
+		if skipping != "" {
+			switch {
+			case tt == html.StartTagToken && tok.Data == skipping:
+				depth++
+			case tt == html.EndTagToken && tok.Data == skipping:
+				depth--
+				if depth == 0 {
+					skipping = ""
+				}
+			}
+			continue
+		}
+
+		switch tt {
+		case html.StartTagToken, html.SelfClosingTagToken:
+			if droppedElements[tok.Data] {
+				if tt == html.StartTagToken && !isVoidElement(tok.Data) {
+					skipping, depth = tok.Data, 1
+				}
+				continue
+			}
+			if tok.Data == "style" && tt == html.StartTagToken {
+				inStyle = true
+			}
+			if cleanAttributes(&tok) {
+				out.WriteString(tok.String())
+			} else {
+				out.Write(z.Raw())
+			}
+		case html.EndTagToken:
+			if droppedElements[tok.Data] {
+				continue
+			}
+			if tok.Data == "style" {
+				inStyle = false
+			}
+			out.Write(z.Raw())
+		case html.TextToken:
+			if inStyle {
+				// Stylesheets may load images, fonts or further stylesheets
+				out.WriteString(cssURLPattern.ReplaceAllString(string(z.Raw()), ""))
+			} else {
+				out.Write(z.Raw())
+			}
+		default:
+			out.Write(z.Raw())
+		}
+	}
+}
+
+// cleanAttributes drops event handlers and remote resource URLs from a tag
+// and reports whether anything was removed
+func cleanAttributes(tok *html.Token) bool {
+	changed := false
+	kept := tok.Attr[:0]
+	for _, a := range tok.Attr {
+		key := strings.ToLower(a.Key)
+		if a.Namespace != "" {
+			key = strings.ToLower(a.Namespace) + ":" + key
+		}
+		switch {
+		case strings.HasPrefix(key, "on"):
+			changed = true
+			continue
+		case resourceAttributes[key] && isRemoteURL(a.Val):
+			changed = true
+			continue
+		case key == "href" && tok.Data != "a" && tok.Data != "area" && isRemoteURL(a.Val):
+			// Links are only followed by a reader; elsewhere href is fetched
+			changed = true
+			continue
+		case key == "style" && cssURLPattern.MatchString(a.Val):
+			a.Val = cssURLPattern.ReplaceAllString(a.Val, "")
+			changed = true
+		}
+		kept = append(kept, a)
+	}
+	tok.Attr = kept
+	return changed
+}
+
+// isRemoteURL reports whether a resource URL points outside the message
+// Inline data: URLs, cid: references to attachments and fragments stay.
+func isRemoteURL(val string) bool {
+	val = strings.ToLower(strings.TrimSpace(val))
+	for _, prefix := range []string{"data:", "cid:", "#"} {
+		if strings.HasPrefix(val, prefix) {
+			return false
+		}
+	}
+	return val != ""
+}
+
+// isVoidElement reports whether an element never has content or an end tag
+func isVoidElement(name string) bool {
+	switch name {
+	case "link", "base", "meta", "embed", "frame":
+		return true
+	}
+	return false
+}
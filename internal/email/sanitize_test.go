@@ -0,0 +1,67 @@
+package email
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSanitizeHTML(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    string
+		missing []string
+	}{
+		{
+			name: "untouched markup keeps its case",
+			in:   `<html><BODY class="x"><p>Hi <a href="https://example.com">there</a></p></BODY></html>`,
+			want: `<html><BODY class="x"><p>Hi <a href="https://example.com">there</a></p></BODY></html>`,
+		},
+		{
+			name:    "remote image",
+			in:      `<p>Bill</p><img src="http://tracker.example/pixel.gif" alt="x">`,
+			want:    `<p>Bill</p><img alt="x">`,
+			missing: []string{"tracker"},
+		},
+		{
+			name: "inline and attached images stay",
+			in:   `<img src="cid:logo@example"><img src="data:image/png;base64,AAAA">`,
+			want: `<img src="cid:logo@example"><img src="data:image/png;base64,AAAA">`,
+		},
+		{
+			name:    "scripts, frames and objects",
+			in:      `<p>a</p><script>fetch("http://x")</script><iframe src="http://x"><p>no</p></iframe><object data="http://x"></object><p>b</p>`,
+			want:    `<p>a</p><p>b</p>`,
+			missing: []string{"http://x"},
+		},
+		{
+			name:    "stylesheets",
+			in:      `<head><link rel="stylesheet" href="http://x/a.css"><style>@import url(http://x/b.css); p { background: url('http://x/c.png') }</style></head>`,
+			missing: []string{"http://x"},
+		},
+		{
+			name:    "event handlers and inline styles",
+			in:      `<body onload="go()" style="background-image: url(http://x/bg.png); color: red" background="http://x/bg2.png">`,
+			missing: []string{"onload", "http://x"},
+		},
+		{
+			name: "prose mentioning url() is kept",
+			in:   `<p>call url(home)</p>`,
+			want: `<p>call url(home)</p>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SanitizeHTML(tt.in)
+			if tt.want != "" && got != tt.want {
+				t.Errorf("SanitizeHTML() = %q, want %q", got, tt.want)
+			}
+			for _, s := range tt.missing {
+				if strings.Contains(got, s) {
+					t.Errorf("SanitizeHTML() = %q, still contains %q", got, s)
+				}
+			}
+		})
+	}
+}
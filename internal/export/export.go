@@ -0,0 +1,290 @@
+// Package export keeps a human-readable mirror of the archive
+// Each processed document is hardlinked (or copied) into a directory tree
+// named by a path template, so the files stay browsable without docko.
+package export
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bketelsen/docko/internal/config"
+	"github.com/bketelsen/docko/internal/database"
+	"github.com/bketelsen/docko/internal/database/sqlc"
+	"github.com/bketelsen/docko/internal/document"
+
+	"github.com/google/uuid"
+)
+
+// Export modes
+const (
+	ModeHardlink = "hardlink" // Falls back to copying across filesystems
+	ModeCopy     = "copy"
+)
+
+// Exporter syncs the export mirror with document metadata
+type Exporter struct {
+	db        *database.DB
+	docSvc    *document.Service
+	root      string
+	tmpl      *Template
+	copyFiles bool
+}
+
+// SyncResult summarizes the changes made by one sync
+type SyncResult struct {
+	Written int
+	Removed int
+}
+
+// New creates an Exporter from the export config
+func New(db *database.DB, docSvc *document.Service, cfg config.ExportConfig) (*Exporter, error) {
+	if cfg.Path == "" {
+		return nil, fmt.Errorf("export path cannot be empty")
+	}
+	if cfg.Mode != ModeHardlink && cfg.Mode != ModeCopy {
+		return nil, fmt.Errorf("invalid export mode %q (want %s or %s)", cfg.Mode, ModeHardlink, ModeCopy)
+	}
+	if cfg.IntervalSeconds <= 0 {
+		return nil, fmt.Errorf("export interval must be positive, got %d seconds", cfg.IntervalSeconds)
+	}
+
+	tmpl, err := ParseTemplate(cfg.Template)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := filepath.Abs(cfg.Path)
+	if err != nil {
+		return nil, fmt.Errorf("resolve export path: %w", err)
+	}
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return nil, fmt.Errorf("create export directory: %w", err)
+	}
+
+	return &Exporter{
+		db:        db,
+		docSvc:    docSvc,
+		root:      root,
+		tmpl:      tmpl,
+		copyFiles: cfg.Mode == ModeCopy,
+	}, nil
+}
+
+// Start syncs the mirror immediately and then on every interval until ctx is cancelled
+func (e *Exporter) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		result, err := e.Sync(ctx)
+		if err != nil {
+			slog.Warn("failed to sync export mirror", "error", err)
+		} else if result.Written > 0 || result.Removed > 0 {
+			slog.Info("synced export mirror", "written", result.Written, "removed", result.Removed)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// target is where a document belongs in the mirror
+type target struct {
+	path     string // Relative, slash-separated
+	source   string // Storage key of the PDF
+	revision int32
+}
+
+// Sync brings the mirror in line with the current documents
+// Files are only touched when a document's rendered path or file changed, or
+// its export went missing; files of deleted documents are removed. Only files
+// recorded in document_exports are ever deleted.
+func (e *Exporter) Sync(ctx context.Context) (SyncResult, error) {
+	var result SyncResult
+
+	docs, err := e.db.Queries.ListExportableDocuments(ctx)
+	if err != nil {
+		return result, fmt.Errorf("list exportable documents: %w", err)
+	}
+	exports, err := e.db.Queries.ListDocumentExports(ctx)
+	if err != nil {
+		return result, fmt.Errorf("list document exports: %w", err)
+	}
+
+	// Render every path first; documents are ordered oldest first so the
+	// older document keeps the plain name when two render the same
+	targets := make(map[uuid.UUID]target, len(docs))
+	wanted := make(map[string]bool, len(docs)) // Lowercased, for case-insensitive filesystems
+	for _, d := range docs {
+		doc := &sqlc.Document{
+			ID:               d.ID,
+			OriginalFilename: d.OriginalFilename,
+			ContentType:      d.ContentType,
+			Revision:         d.Revision,
+			EditVersion:      d.EditVersion,
+		}
+		path := e.tmpl.Render(metadataFor(d))
+		if wanted[strings.ToLower(path)] {
+			path = strings.TrimSuffix(path, ".pdf") + " (" + d.ID.String()[:8] + ").pdf"
+		}
+		wanted[strings.ToLower(path)] = true
+		targets[d.ID] = target{path: path, source: e.docSvc.PDFKey(doc), revision: d.Revision}
+	}
+
+	// Remove files that moved or whose document is gone, unless another
+	// document now renders to the same path
+	current := make(map[uuid.UUID]sqlc.DocumentExport, len(exports))
+	for _, ex := range exports {
+		t, ok := targets[ex.DocumentID]
+		if ok && t.path == ex.ExportPath {
+			current[ex.DocumentID] = ex
+			continue
+		}
+		if !wanted[strings.ToLower(ex.ExportPath)] {
+			if err := e.remove(ex.ExportPath); err != nil {
+				slog.Warn("failed to remove exported file", "path", ex.ExportPath, "error", err)
+				continue
+			}
+			result.Removed++
+		}
+		if !ok {
+			if err := e.db.Queries.DeleteDocumentExport(ctx, ex.DocumentID); err != nil {
+				return result, fmt.Errorf("delete document export: %w", err)
+			}
+		}
+	}
+
+	for id, t := range targets {
+		if ex, ok := current[id]; ok && ex.SourcePath == t.source && ex.SourceRevision == t.revision && e.exists(t.path) {
+			continue
+		}
+		if err := e.export(ctx, t); err != nil {
+			slog.Warn("failed to export document", "document_id", id, "path", t.path, "error", err)
+			continue
+		}
+		err := e.db.Queries.UpsertDocumentExport(ctx, sqlc.UpsertDocumentExportParams{
+			DocumentID:     id,
+			ExportPath:     t.path,
+			SourcePath:     t.source,
+			SourceRevision: t.revision,
+		})
+		if err != nil {
+			return result, fmt.Errorf("record document export: %w", err)
+		}
+		result.Written++
+	}
+
+	return result, nil
+}
+
+// metadataFor builds template metadata from an exportable document
+func metadataFor(d sqlc.ListExportableDocumentsRow) Metadata {
+	m := Metadata{
+		ID:           d.ID,
+		OriginalName: strings.TrimSuffix(d.OriginalFilename, filepath.Ext(d.OriginalFilename)),
+		DocumentDate: d.DocumentDate,
+		Added:        d.CreatedAt,
+	}
+	switch {
+	case d.Title != nil && *d.Title != "":
+		m.Title = *d.Title
+	case d.PdfTitle != nil:
+		m.Title = *d.PdfTitle
+	}
+	if d.CorrespondentName != nil {
+		m.Correspondent = *d.CorrespondentName
+	}
+	if d.DocumentTypeName != nil {
+		m.DocumentType = *d.DocumentTypeName
+	}
+	return m
+}
+
+// abs returns the absolute path of a relative export path
+func (e *Exporter) abs(rel string) string {
+	return filepath.Join(e.root, filepath.FromSlash(rel))
+}
+
+func (e *Exporter) exists(rel string) bool {
+	_, err := os.Stat(e.abs(rel))
+	return err == nil
+}
+
+// export places a document's stored PDF at its export path
+// Files on local storage are hardlinked directly; others are staged first.
+func (e *Exporter) export(ctx context.Context, t target) error {
+	src, release, err := e.docSvc.FetchFile(ctx, t.source)
+	if err != nil {
+		return fmt.Errorf("fetch pdf: %w", err)
+	}
+	defer release()
+	return e.place(src, t.path)
+}
+
+// place links or copies src to the export path, replacing any existing file
+func (e *Exporter) place(src, rel string) error {
+	dst := e.abs(rel)
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+
+	// Write next to the destination and rename so readers never see a partial file
+	tmp := dst + ".docko-tmp"
+	_ = os.Remove(tmp)
+
+	// Renaming over a hardlink to the same file is a no-op that leaves tmp behind
+	defer func() { _ = os.Remove(tmp) }()
+
+	if !e.copyFiles {
+		if err := os.Link(src, tmp); err == nil {
+			return os.Rename(tmp, dst)
+		}
+	}
+
+	if err := copyFile(src, tmp); err != nil {
+		return err
+	}
+	return os.Rename(tmp, dst)
+}
+
+// remove deletes an exported file and any directories left empty
+func (e *Exporter) remove(rel string) error {
+	path := e.abs(rel)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for dir := filepath.Dir(path); dir != e.root && strings.HasPrefix(dir, e.root); dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			break // Not empty
+		}
+	}
+	return nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("open source: %w", err)
+	}
+	defer func() { _ = in.Close() }()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return fmt.Errorf("create destination: %w", err)
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return fmt.Errorf("copy file: %w", err)
+	}
+	return out.Close()
+}
@@ -0,0 +1,66 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bketelsen/docko/internal/config"
+)
+
+func TestPlaceAndRemove(t *testing.T) {
+	for _, copyFiles := range []bool{false, true} {
+		root := t.TempDir()
+		src := filepath.Join(t.TempDir(), "src.pdf")
+		if err := os.WriteFile(src, []byte("%PDF-1.4 test"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		e := &Exporter{root: root, copyFiles: copyFiles}
+
+		rel := "ACME Corp/2024/2024-03-15 Bill.pdf"
+		if err := e.place(src, rel); err != nil {
+			t.Fatalf("place (copy=%v) failed: %v", copyFiles, err)
+		}
+		// Placing again replaces the file without leaving a temp file behind
+		if err := e.place(src, rel); err != nil {
+			t.Fatalf("second place (copy=%v) failed: %v", copyFiles, err)
+		}
+		data, err := os.ReadFile(filepath.Join(root, "ACME Corp", "2024", "2024-03-15 Bill.pdf"))
+		if err != nil || string(data) != "%PDF-1.4 test" {
+			t.Fatalf("exported file = %q, %v", data, err)
+		}
+		if entries, _ := os.ReadDir(filepath.Join(root, "ACME Corp", "2024")); len(entries) != 1 {
+			t.Errorf("export directory has %d entries, want 1", len(entries))
+		}
+
+		if err := e.remove(rel); err != nil {
+			t.Fatalf("remove failed: %v", err)
+		}
+		if entries, _ := os.ReadDir(root); len(entries) != 0 {
+			t.Errorf("empty directories left after remove: %v", entries)
+		}
+		if _, err := os.Stat(root); err != nil {
+			t.Errorf("export root removed: %v", err)
+		}
+	}
+}
+
+func TestNewRejectsInvalidConfig(t *testing.T) {
+	valid := config.ExportConfig{
+		Path:            t.TempDir(),
+		Template:        "{title}.pdf",
+		Mode:            ModeHardlink,
+		IntervalSeconds: 60,
+	}
+	if _, err := New(nil, nil, valid); err != nil {
+		t.Fatalf("New() with valid config error = %v", err)
+	}
+
+	for _, seconds := range []int{0, -5} {
+		cfg := valid
+		cfg.IntervalSeconds = seconds
+		if _, err := New(nil, nil, cfg); err == nil {
+			t.Errorf("New() with interval %d accepted, want error", seconds)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package export
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// maxSegmentBytes keeps each path segment well under the common 255-byte filename limit
+const maxSegmentBytes = 200
+
+// Placeholders lists the names a template can reference as {name}
+var Placeholders = []string{
+	"title", "original_name", "correspondent", "document_type",
+	"document_date", "year", "month", "day", "added", "id",
+}
+
+var placeholderRe = regexp.MustCompile(`\{([a-z_]+)\}`)
+
+// Metadata holds the document values a template is rendered from
+type Metadata struct {
+	ID            uuid.UUID
+	Title         string
+	OriginalName  string // Original filename without extension
+	Correspondent string
+	DocumentType  string
+	DocumentDate  time.Time
+	Added         time.Time
+}
+
+// Template renders relative export paths from document metadata
+type Template struct {
+	raw string
+}
+
+// ParseTemplate validates a path template
+// Every {name} must be a known placeholder and the template may not escape
+// the export directory.
+func ParseTemplate(raw string) (*Template, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, fmt.Errorf("export template cannot be empty")
+	}
+	if strings.HasPrefix(raw, "/") {
+		return nil, fmt.Errorf("export template must be a relative path")
+	}
+	for _, m := range placeholderRe.FindAllStringSubmatch(raw, -1) {
+		if !slices.Contains(Placeholders, m[1]) {
+			return nil, fmt.Errorf("unknown placeholder {%s} in export template", m[1])
+		}
+	}
+	for _, seg := range strings.Split(raw, "/") {
+		if strings.TrimSpace(seg) == ".." {
+			return nil, fmt.Errorf("export template cannot contain ..")
+		}
+	}
+	return &Template{raw: raw}, nil
+}
+
+// Render returns the relative, slash-separated path for a document
+// Values are sanitized so they can't add directories, empty values fall back
+// to a placeholder word, and the path always ends in .pdf.
+func (t *Template) Render(m Metadata) string {
+	rendered := placeholderRe.ReplaceAllStringFunc(t.raw, func(s string) string {
+		return sanitize(m.value(s[1 : len(s)-1]))
+	})
+
+	var segments []string
+	for _, seg := range strings.Split(rendered, "/") {
+		seg = strings.Trim(strings.TrimSpace(seg), ".")
+		if seg == "" {
+			continue
+		}
+		segments = append(segments, seg)
+	}
+	if len(segments) == 0 {
+		segments = []string{m.ID.String()}
+	}
+
+	// Truncate names before adding the extension so it is never cut off
+	last := strings.TrimSuffix(segments[len(segments)-1], ".pdf")
+	last = strings.TrimSuffix(last, ".PDF")
+	if last == "" {
+		last = m.ID.String()
+	}
+	segments[len(segments)-1] = last
+	for i, seg := range segments {
+		segments[i] = truncate(seg, maxSegmentBytes)
+	}
+
+	return strings.Join(segments, "/") + ".pdf"
+}
+
+// value returns the raw value for a placeholder name
+func (m Metadata) value(name string) string {
+	switch name {
+	case "title":
+		return fallback(m.Title, m.OriginalName)
+	case "original_name":
+		return m.OriginalName
+	case "correspondent":
+		return fallback(m.Correspondent, "Unknown")
+	case "document_type":
+		return fallback(m.DocumentType, "Unknown")
+	case "document_date":
+		return m.DocumentDate.Format("2006-01-02")
+	case "year":
+		return m.DocumentDate.Format("2006")
+	case "month":
+		return m.DocumentDate.Format("01")
+	case "day":
+		return m.DocumentDate.Format("02")
+	case "added":
+		return m.Added.Format("2006-01-02")
+	case "id":
+		return m.ID.String()
+	}
+	return ""
+}
+
+func fallback(s, def string) string {
+	if strings.TrimSpace(s) == "" {
+		return def
+	}
+	return s
+}
+
+// sanitize makes a value safe to use inside a single path segment
+func sanitize(s string) string {
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case strings.ContainsRune(`/\:*?"<>|`, r):
+			return '-'
+		case unicode.IsControl(r):
+			return ' '
+		}
+		return r
+	}, s)
+	s = strings.Join(strings.Fields(s), " ")
+	return strings.Trim(s, ".")
+}
+
+// truncate shortens s to at most n bytes without splitting a rune
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return strings.TrimSpace(s[:n])
+}
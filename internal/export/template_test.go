@@ -0,0 +1,85 @@
+package export
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestParseTemplate_Invalid(t *testing.T) {
+	for _, raw := range []string{
+		"",
+		"/abs/{title}.pdf",
+		"{correspondent}/{nope}.pdf",
+		"../{title}.pdf",
+		"{year}/ .. /{title}",
+	} {
+		if _, err := ParseTemplate(raw); err == nil {
+			t.Errorf("ParseTemplate(%q) succeeded, want error", raw)
+		}
+	}
+}
+
+func TestTemplateRender(t *testing.T) {
+	id := uuid.MustParse("0b6f2a3e-8f3c-4a51-9d7e-2f1c0a9b8e7d")
+	base := Metadata{
+		ID:            id,
+		Title:         "March Bill",
+		OriginalName:  "scan_0042",
+		Correspondent: "ACME Corp",
+		DocumentType:  "Invoice",
+		DocumentDate:  time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC),
+		Added:         time.Date(2024, 4, 1, 9, 30, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name string
+		tmpl string
+		edit func(*Metadata)
+		want string
+	}{
+		{"default", "{correspondent}/{year}/{document_date} {title}.pdf", nil, "ACME Corp/2024/2024-03-15 March Bill.pdf"},
+		{"adds extension", "{document_type}/{year}-{month}-{day} {original_name}", nil, "Invoice/2024-03-15 scan_0042.pdf"},
+		{"added date and id", "{added}/{id}", nil, "2024-04-01/" + id.String() + ".pdf"},
+		{"missing correspondent", "{correspondent}/{title}.pdf", func(m *Metadata) { m.Correspondent = "" }, "Unknown/March Bill.pdf"},
+		{"title falls back to filename", "{title}.pdf", func(m *Metadata) { m.Title = " " }, "scan_0042.pdf"},
+		{"separators in values", "{correspondent}/{title}.pdf", func(m *Metadata) {
+			m.Correspondent = "AT&T / Legal"
+			m.Title = `Q1: "final"?`
+		}, "AT&T - Legal/Q1- -final--.pdf"},
+		{"dot values can't climb", "{correspondent}/{title}.pdf", func(m *Metadata) { m.Correspondent = ".." }, "March Bill.pdf"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := ParseTemplate(tt.tmpl)
+			if err != nil {
+				t.Fatalf("ParseTemplate failed: %v", err)
+			}
+			m := base
+			if tt.edit != nil {
+				tt.edit(&m)
+			}
+			if got := tmpl.Render(m); got != tt.want {
+				t.Errorf("Render = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplateRender_LongTitle(t *testing.T) {
+	tmpl, _ := ParseTemplate("{title}.pdf")
+	got := tmpl.Render(Metadata{Title: strings.Repeat("ü", 300)})
+
+	if !strings.HasSuffix(got, ".pdf") {
+		t.Errorf("Render = %q, want .pdf suffix", got)
+	}
+	if len(got) > maxSegmentBytes+len(".pdf") {
+		t.Errorf("len(Render) = %d, want at most %d", len(got), maxSegmentBytes+len(".pdf"))
+	}
+	if !strings.HasPrefix(got, "ü") || strings.ContainsRune(got, '�') {
+		t.Errorf("Render split a rune: %q", got)
+	}
+}
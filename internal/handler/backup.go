@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// DownloadBackup streams a backup bundle of the whole archive
+// GET /backup
+func (h *Handler) DownloadBackup(c echo.Context) error {
+	filename := fmt.Sprintf("docko-backup-%s.tar.gz", time.Now().Format("20060102-150405"))
+	c.Response().Header().Set(echo.HeaderContentType, "application/gzip")
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+
+	result, err := h.backupSvc.Export(c.Request().Context(), c.Response())
+	if err != nil {
+		slog.Error("failed to export backup", "error", err)
+		if !c.Response().Committed {
+			c.Response().Header().Del(echo.HeaderContentDisposition)
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to export backup")
+		}
+		// The download has started; dropping the connection leaves the client
+		// with a truncated file that restore rejects
+		panic(http.ErrAbortHandler)
+	}
+
+	slog.Info("backup downloaded", "documents", result.Manifest.Rows("documents"), "files", result.Files, "bytes", result.Bytes)
+	return nil
+}
@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/bketelsen/docko/internal/database/sqlc"
+	"github.com/bketelsen/docko/internal/document"
+	"github.com/bketelsen/docko/templates/pages/admin"
+	"github.com/bketelsen/docko/templates/partials"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+)
+
+// CustomFieldsPage renders the custom field management page
+func (h *Handler) CustomFieldsPage(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	fields, err := h.db.Queries.ListCustomFieldsWithCounts(ctx)
+	if err != nil {
+		slog.Error("failed to list custom fields", "error", err)
+		return c.String(http.StatusInternalServerError, "Failed to load custom fields")
+	}
+
+	return admin.CustomFields(fields).Render(ctx, c.Response().Writer)
+}
+
+// CreateCustomField creates a new custom field
+// POST /custom-fields (form: name, field_type)
+func (h *Handler) CreateCustomField(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	name := strings.TrimSpace(c.FormValue("name"))
+	if name == "" {
+		return c.String(http.StatusBadRequest, "Name is required")
+	}
+
+	fieldType := sqlc.CustomFieldType(c.FormValue("field_type"))
+	if !slices.Contains(document.FieldTypes, fieldType) {
+		return c.String(http.StatusBadRequest, "Invalid field type")
+	}
+
+	field, err := h.db.Queries.CreateCustomField(ctx, sqlc.CreateCustomFieldParams{
+		Name:      name,
+		FieldType: fieldType,
+	})
+	if err != nil {
+		// ON CONFLICT DO NOTHING returns no rows for a duplicate name
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.String(http.StatusConflict, "A field with this name already exists")
+		}
+		slog.Error("failed to create custom field", "error", err)
+		return c.String(http.StatusInternalServerError, "Failed to create custom field")
+	}
+
+	row := sqlc.ListCustomFieldsWithCountsRow{
+		ID:        field.ID,
+		Name:      field.Name,
+		FieldType: field.FieldType,
+		CreatedAt: field.CreatedAt,
+	}
+
+	c.Response().Header().Set("HX-Trigger", "closeModal")
+	return admin.CustomFieldCard(row).Render(ctx, c.Response().Writer)
+}
+
+// RenameCustomField renames a custom field
+// The type can't change once documents may hold values for the field.
+// POST /custom-fields/:id (form: name)
+func (h *Handler) RenameCustomField(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid field ID")
+	}
+
+	name := strings.TrimSpace(c.FormValue("name"))
+	if name == "" {
+		return c.String(http.StatusBadRequest, "Name is required")
+	}
+
+	if _, err := h.db.Queries.RenameCustomField(ctx, sqlc.RenameCustomFieldParams{ID: id, Name: name}); err != nil {
+		slog.Error("failed to rename custom field", "error", err)
+		return c.String(http.StatusInternalServerError, "Failed to rename custom field")
+	}
+
+	fields, err := h.db.Queries.ListCustomFieldsWithCounts(ctx)
+	if err != nil {
+		slog.Error("failed to list custom fields", "error", err)
+		return c.String(http.StatusInternalServerError, "Failed to load custom fields")
+	}
+
+	var row sqlc.ListCustomFieldsWithCountsRow
+	for _, f := range fields {
+		if f.ID == id {
+			row = f
+			break
+		}
+	}
+
+	c.Response().Header().Set("HX-Trigger", "closeModal")
+	return admin.CustomFieldCard(row).Render(ctx, c.Response().Writer)
+}
+
+// DeleteCustomField removes a custom field and its values on all documents
+func (h *Handler) DeleteCustomField(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid field ID")
+	}
+
+	// Cascade removes document_custom_fields
+	if err := h.db.Queries.DeleteCustomField(ctx, id); err != nil {
+		slog.Error("failed to delete custom field", "error", err)
+		return c.String(http.StatusInternalServerError, "Failed to delete custom field")
+	}
+
+	return c.String(http.StatusOK, "")
+}
+
+// SetDocumentCustomField sets a custom field value on a document
+// POST /documents/:id/fields (form: field_id, value; empty value removes)
+func (h *Handler) SetDocumentCustomField(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	docID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid document ID")
+	}
+
+	fieldID, err := uuid.Parse(c.FormValue("field_id"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid field ID")
+	}
+
+	// Invalid values are shown next to the fields rather than failing the request
+	var errMsg string
+	if err := h.docSvc.SetCustomField(ctx, docID, fieldID, c.FormValue("value")); err != nil {
+		if !errors.Is(err, document.ErrInvalidFieldValue) {
+			slog.Error("failed to set custom field", "error", err, "document_id", docID, "field_id", fieldID)
+			return c.String(http.StatusInternalServerError, "Failed to set custom field")
+		}
+		errMsg = strings.TrimPrefix(err.Error(), document.ErrInvalidFieldValue.Error()+": ")
+	}
+
+	return h.renderDocumentCustomFields(c, docID, errMsg)
+}
+
+// RemoveDocumentCustomField removes a custom field value from a document
+// DELETE /documents/:id/fields/:field_id
+func (h *Handler) RemoveDocumentCustomField(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	docID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid document ID")
+	}
+
+	fieldID, err := uuid.Parse(c.Param("field_id"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid field ID")
+	}
+
+	err = h.db.Queries.RemoveDocumentCustomField(ctx, sqlc.RemoveDocumentCustomFieldParams{
+		DocumentID: docID,
+		FieldID:    fieldID,
+	})
+	if err != nil {
+		slog.Error("failed to remove custom field", "error", err)
+		return c.String(http.StatusInternalServerError, "Failed to remove custom field")
+	}
+
+	return h.renderDocumentCustomFields(c, docID, "")
+}
+
+// renderDocumentCustomFields renders the custom fields partial for a document
+func (h *Handler) renderDocumentCustomFields(c echo.Context, docID uuid.UUID, errMsg string) error {
+	ctx := c.Request().Context()
+
+	values, err := h.db.Queries.ListDocumentCustomFields(ctx, docID)
+	if err != nil {
+		slog.Error("failed to list document custom fields", "error", err)
+		return c.String(http.StatusInternalServerError, "Failed to get custom fields")
+	}
+	fields, err := h.db.Queries.ListCustomFields(ctx)
+	if err != nil {
+		slog.Error("failed to list custom fields", "error", err)
+		return c.String(http.StatusInternalServerError, "Failed to get custom fields")
+	}
+
+	return partials.DocumentCustomFields(docID.String(), values, fields, errMsg).Render(ctx, c.Response().Writer)
+}
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/bketelsen/docko/internal/database/sqlc"
+	"github.com/bketelsen/docko/templates/pages/admin"
+	"github.com/bketelsen/docko/templates/partials"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/labstack/echo/v4"
+)
+
+// DocumentTypesPage renders the document type management page
+func (h *Handler) DocumentTypesPage(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	types, err := h.db.Queries.ListDocumentTypesWithCounts(ctx)
+	if err != nil {
+		slog.Error("failed to list document types", "error", err)
+		return c.String(http.StatusInternalServerError, "Failed to load document types")
+	}
+
+	return admin.DocumentTypes(types).Render(ctx, c.Response().Writer)
+}
+
+// CreateDocumentType creates a new document type
+func (h *Handler) CreateDocumentType(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	name := strings.TrimSpace(c.FormValue("name"))
+	if name == "" {
+		return c.String(http.StatusBadRequest, "Name is required")
+	}
+
+	docType, err := h.db.Queries.CreateDocumentType(ctx, name)
+	if err != nil {
+		// ON CONFLICT DO NOTHING returns no rows for a duplicate name
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.String(http.StatusConflict, "A document type with this name already exists")
+		}
+		slog.Error("failed to create document type", "error", err)
+		return c.String(http.StatusInternalServerError, "Failed to create document type")
+	}
+
+	c.Response().Header().Set("HX-Trigger", "closeModal")
+	return admin.DocumentTypeCard(sqlc.ListDocumentTypesWithCountsRow{
+		ID:            docType.ID,
+		Name:          docType.Name,
+		CreatedAt:     docType.CreatedAt,
+		DocumentCount: 0,
+	}).Render(ctx, c.Response().Writer)
+}
+
+// UpdateDocumentType renames a document type
+func (h *Handler) UpdateDocumentType(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid document type ID")
+	}
+
+	name := strings.TrimSpace(c.FormValue("name"))
+	if name == "" {
+		return c.String(http.StatusBadRequest, "Name is required")
+	}
+
+	if _, err := h.db.Queries.UpdateDocumentType(ctx, sqlc.UpdateDocumentTypeParams{ID: id, Name: name}); err != nil {
+		slog.Error("failed to update document type", "error", err)
+		return c.String(http.StatusInternalServerError, "Failed to update document type")
+	}
+
+	// Get document count for the updated type
+	types, err := h.db.Queries.ListDocumentTypesWithCounts(ctx)
+	if err != nil {
+		slog.Error("failed to get document type counts", "error", err)
+		return c.String(http.StatusInternalServerError, "Failed to get document type data")
+	}
+
+	var row sqlc.ListDocumentTypesWithCountsRow
+	for _, t := range types {
+		if t.ID == id {
+			row = t
+			break
+		}
+	}
+
+	c.Response().Header().Set("HX-Trigger", "closeModal")
+	return admin.DocumentTypeCard(row).Render(ctx, c.Response().Writer)
+}
+
+// DeleteDocumentType removes a document type
+func (h *Handler) DeleteDocumentType(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid document type ID")
+	}
+
+	// Documents of this type are left without one (ON DELETE SET NULL)
+	if err := h.db.Queries.DeleteDocumentType(ctx, id); err != nil {
+		slog.Error("failed to delete document type", "error", err)
+		return c.String(http.StatusInternalServerError, "Failed to delete document type")
+	}
+
+	return c.String(http.StatusOK, "")
+}
+
+// SetDocumentType sets or clears the type of a document
+// POST /documents/:id/type (form: document_type_id; empty clears)
+func (h *Handler) SetDocumentType(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	docID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid document ID")
+	}
+
+	var typeID pgtype.UUID
+	if idStr := c.FormValue("document_type_id"); idStr != "" {
+		id, err := uuid.Parse(idStr)
+		if err != nil {
+			return c.String(http.StatusBadRequest, "Invalid document type ID")
+		}
+		typeID = pgtype.UUID{Bytes: id, Valid: true}
+	}
+
+	if err := h.db.Queries.SetDocumentType(ctx, sqlc.SetDocumentTypeParams{ID: docID, DocumentTypeID: typeID}); err != nil {
+		slog.Error("failed to set document type", "error", err)
+		return c.String(http.StatusInternalServerError, "Failed to set document type")
+	}
+
+	types, err := h.db.Queries.ListDocumentTypes(ctx)
+	if err != nil {
+		slog.Error("failed to list document types", "error", err)
+		return c.String(http.StatusInternalServerError, "Failed to get document types")
+	}
+
+	return partials.DocumentTypePicker(docID.String(), typeID, types).Render(ctx, c.Response().Writer)
+}
@@ -1,21 +1,30 @@
 package handler
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"net/url"
+	"path"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/bketelsen/docko/internal/database/sqlc"
 	"github.com/bketelsen/docko/internal/document"
+	"github.com/bketelsen/docko/internal/pdf"
 	"github.com/bketelsen/docko/internal/processing"
+	"github.com/bketelsen/docko/internal/storage"
 	"github.com/bketelsen/docko/templates/pages/admin"
 	"github.com/bketelsen/docko/templates/partials"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/labstack/echo/v4"
 )
 
@@ -23,10 +32,15 @@ import (
 type searchParams struct {
 	Query           string
 	CorrespondentID *uuid.UUID
+	DocumentTypeID  *uuid.UUID
 	TagIDs          []uuid.UUID
 	DateFrom        *time.Time
 	DateTo          *time.Time
 	DateRange       string // Original value: "today", "7d", "30d", "1y"
+	PagesMin        *int
+	PagesMax        *int
+	PageRange       string   // Original value: "1", "2-5", "6-20", "21+"
+	FieldFilters    []string // Custom field filters: "<field id>:<op>:<value>"
 	Page            int
 	PerPage         int
 }
@@ -36,6 +50,7 @@ func parseSearchParams(c echo.Context) searchParams {
 	params := searchParams{
 		Query:     strings.TrimSpace(c.QueryParam("q")),
 		DateRange: c.QueryParam("date"),
+		PageRange: c.QueryParam("pages"),
 		Page:      1,
 		PerPage:   20,
 	}
@@ -54,6 +69,13 @@ func parseSearchParams(c echo.Context) searchParams {
 		}
 	}
 
+	// Parse document type filter
+	if typeID := c.QueryParam("type"); typeID != "" {
+		if id, err := uuid.Parse(typeID); err == nil {
+			params.DocumentTypeID = &id
+		}
+	}
+
 	// Parse tag filters (multiple allowed)
 	for _, tagID := range c.QueryParams()["tag"] {
 		if id, err := uuid.Parse(tagID); err == nil {
@@ -78,11 +100,69 @@ func parseSearchParams(c echo.Context) searchParams {
 		params.DateFrom = &start
 	}
 
+	// Parse page count range
+	pageRange := func(lo, hi int) {
+		params.PagesMin = &lo
+		if hi > 0 {
+			params.PagesMax = &hi
+		}
+	}
+	switch params.PageRange {
+	case "1":
+		pageRange(1, 1)
+	case "2-5":
+		pageRange(2, 5)
+	case "6-20":
+		pageRange(6, 20)
+	case "21+":
+		pageRange(21, 0)
+	}
+
+	// Parse custom field filters (multiple allowed); the filter row in the
+	// search form adds one when its Add button is clicked
+	params.FieldFilters = c.QueryParams()["cf"]
+	if c.QueryParam("cf_add") != "" && c.QueryParam("cf_field") != "" {
+		filter := c.QueryParam("cf_field") + ":" + c.QueryParam("cf_op") + ":" + strings.TrimSpace(c.QueryParam("cf_value"))
+		if !slices.Contains(params.FieldFilters, filter) {
+			params.FieldFilters = append(params.FieldFilters, filter)
+		}
+	}
+
 	return params
 }
 
+// resolveFieldFilters validates custom field filters against their fields
+// Filters on unknown fields or with values that don't fit the field type are
+// dropped. Returns the filters for SearchDocuments and chip labels by filter.
+func (h *Handler) resolveFieldFilters(ctx context.Context, raw []string) ([]document.FieldFilter, map[string]string) {
+	// Never nil: the query reads the filters as a JSON array
+	filters := []document.FieldFilter{}
+	labels := make(map[string]string)
+	for _, r := range raw {
+		parts := strings.SplitN(r, ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		id, err := uuid.Parse(parts[0])
+		if err != nil {
+			continue
+		}
+		field, err := h.db.Queries.GetCustomField(ctx, id)
+		if err != nil {
+			continue
+		}
+		filter, err := document.ParseFieldFilter(field, parts[1], parts[2])
+		if err != nil {
+			continue
+		}
+		filters = append(filters, filter)
+		labels[r] = document.FieldFilterLabel(field.Name, parts[1], parts[2])
+	}
+	return filters, labels
+}
+
 // buildActiveFilters creates filter chip data from params
-func buildActiveFilters(params searchParams, correspondentName string, tagNames map[uuid.UUID]string) []partials.ActiveFilter {
+func buildActiveFilters(params searchParams, correspondentName, documentTypeName string, tagNames map[uuid.UUID]string, fieldLabels map[string]string) []partials.ActiveFilter {
 	var filters []partials.ActiveFilter
 	baseURL := "/documents?"
 
@@ -95,14 +175,25 @@ func buildActiveFilters(params searchParams, correspondentName string, tagNames
 		if params.CorrespondentID != nil && exclude != "correspondent" {
 			parts = append(parts, "correspondent="+params.CorrespondentID.String())
 		}
+		if params.DocumentTypeID != nil && exclude != "type" {
+			parts = append(parts, "type="+params.DocumentTypeID.String())
+		}
 		if params.DateRange != "" && exclude != "date" {
 			parts = append(parts, "date="+params.DateRange)
 		}
+		if params.PageRange != "" && exclude != "pages" {
+			parts = append(parts, "pages="+url.QueryEscape(params.PageRange))
+		}
 		for _, tagID := range params.TagIDs {
 			if exclude != "tag-"+tagID.String() {
 				parts = append(parts, "tag="+tagID.String())
 			}
 		}
+		for _, cf := range params.FieldFilters {
+			if exclude != "cf-"+cf {
+				parts = append(parts, "cf="+url.QueryEscape(cf))
+			}
+		}
 		if len(parts) == 0 {
 			return "/documents"
 		}
@@ -127,6 +218,15 @@ func buildActiveFilters(params searchParams, correspondentName string, tagNames
 		})
 	}
 
+	if params.DocumentTypeID != nil && documentTypeName != "" {
+		filters = append(filters, partials.ActiveFilter{
+			Type:      "Type",
+			Label:     documentTypeName,
+			Value:     params.DocumentTypeID.String(),
+			RemoveURL: buildURL("type"),
+		})
+	}
+
 	if params.DateRange != "" {
 		labels := map[string]string{
 			"today": "Today",
@@ -142,6 +242,21 @@ func buildActiveFilters(params searchParams, correspondentName string, tagNames
 		})
 	}
 
+	if params.PagesMin != nil {
+		labels := map[string]string{
+			"1":    "1 page",
+			"2-5":  "2-5 pages",
+			"6-20": "6-20 pages",
+			"21+":  "21+ pages",
+		}
+		filters = append(filters, partials.ActiveFilter{
+			Type:      "Pages",
+			Label:     labels[params.PageRange],
+			Value:     params.PageRange,
+			RemoveURL: buildURL("pages"),
+		})
+	}
+
 	for _, tagID := range params.TagIDs {
 		if name, ok := tagNames[tagID]; ok {
 			filters = append(filters, partials.ActiveFilter{
@@ -153,6 +268,15 @@ func buildActiveFilters(params searchParams, correspondentName string, tagNames
 		}
 	}
 
+	for _, cf := range params.FieldFilters {
+		filters = append(filters, partials.ActiveFilter{
+			Type:      "Field",
+			Label:     fieldLabels[cf],
+			Value:     cf,
+			RemoveURL: buildURL("cf-" + cf),
+		})
+	}
+
 	return filters
 }
 
@@ -174,6 +298,12 @@ func (h *Handler) DocumentsPage(c echo.Context) error {
 		correspondentID = *params.CorrespondentID
 	}
 
+	var documentTypeID uuid.UUID
+	hasDocumentType := params.DocumentTypeID != nil
+	if hasDocumentType {
+		documentTypeID = *params.DocumentTypeID
+	}
+
 	var dateFrom, dateTo time.Time
 	hasDateFrom := params.DateFrom != nil
 	hasDateTo := params.DateTo != nil
@@ -184,21 +314,53 @@ func (h *Handler) DocumentsPage(c echo.Context) error {
 		dateTo = *params.DateTo
 	}
 
+	var pagesMin, pagesMax int32
+	hasPagesMin := params.PagesMin != nil
+	hasPagesMax := params.PagesMax != nil
+	if hasPagesMin {
+		pagesMin = int32(*params.PagesMin)
+	}
+	if hasPagesMax {
+		pagesMax = int32(*params.PagesMax)
+	}
+
 	hasTags := len(params.TagIDs) > 0
 	tagCount := int32(len(params.TagIDs))
 
+	// Keep only the custom field filters that are valid for their field
+	fieldFilters, fieldLabels := h.resolveFieldFilters(ctx, params.FieldFilters)
+	params.FieldFilters = slices.DeleteFunc(params.FieldFilters, func(cf string) bool {
+		_, ok := fieldLabels[cf]
+		return !ok
+	})
+	hasFields := len(fieldFilters) > 0
+	fieldCount := int32(len(fieldFilters))
+	fieldFiltersJSON, err := json.Marshal(fieldFilters)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "search failed")
+	}
+
 	// Execute search
 	rows, err := h.db.Queries.SearchDocuments(ctx, sqlc.SearchDocumentsParams{
 		Query:            query,
 		HasCorrespondent: hasCorrespondent,
 		CorrespondentID:  correspondentID,
+		HasDocumentType:  hasDocumentType,
+		DocumentTypeID:   documentTypeID,
 		HasDateFrom:      hasDateFrom,
 		DateFrom:         dateFrom,
 		HasDateTo:        hasDateTo,
 		DateTo:           dateTo,
+		HasPagesMin:      hasPagesMin,
+		PagesMin:         pagesMin,
+		HasPagesMax:      hasPagesMax,
+		PagesMax:         pagesMax,
 		HasTags:          hasTags,
 		TagIds:           params.TagIDs,
 		TagCount:         tagCount,
+		HasFields:        hasFields,
+		FieldFilters:     fieldFiltersJSON,
+		FieldCount:       fieldCount,
 		LimitCount:       int64(params.PerPage),
 		OffsetCount:      int64((params.Page - 1) * params.PerPage),
 	})
@@ -211,13 +373,22 @@ func (h *Handler) DocumentsPage(c echo.Context) error {
 		Query:            query,
 		HasCorrespondent: hasCorrespondent,
 		CorrespondentID:  correspondentID,
+		HasDocumentType:  hasDocumentType,
+		DocumentTypeID:   documentTypeID,
 		HasDateFrom:      hasDateFrom,
 		DateFrom:         dateFrom,
 		HasDateTo:        hasDateTo,
 		DateTo:           dateTo,
+		HasPagesMin:      hasPagesMin,
+		PagesMin:         pagesMin,
+		HasPagesMax:      hasPagesMax,
+		PagesMax:         pagesMax,
 		HasTags:          hasTags,
 		TagIds:           params.TagIDs,
 		TagCount:         tagCount,
+		HasFields:        hasFields,
+		FieldFilters:     fieldFiltersJSON,
+		FieldCount:       fieldCount,
 	})
 	if err != nil {
 		total = 0 // Non-fatal, just show results
@@ -259,6 +430,14 @@ func (h *Handler) DocumentsPage(c echo.Context) error {
 		}
 	}
 
+	// Get document type name for filter chip
+	var documentTypeName string
+	if params.DocumentTypeID != nil {
+		if t, err := h.db.Queries.GetDocumentType(ctx, *params.DocumentTypeID); err == nil {
+			documentTypeName = t.Name
+		}
+	}
+
 	// Get tag names for filter chips
 	tagNames := make(map[uuid.UUID]string)
 	for _, tagID := range params.TagIDs {
@@ -267,18 +446,23 @@ func (h *Handler) DocumentsPage(c echo.Context) error {
 		}
 	}
 
-	activeFilters := buildActiveFilters(params, correspondentName, tagNames)
+	activeFilters := buildActiveFilters(params, correspondentName, documentTypeName, tagNames, fieldLabels)
 
 	// Convert params for template
 	templateParams := partials.SearchParams{
-		Query:     params.Query,
-		DateRange: params.DateRange,
-		Page:      params.Page,
-		PerPage:   params.PerPage,
+		Query:        params.Query,
+		DateRange:    params.DateRange,
+		PageRange:    params.PageRange,
+		FieldFilters: params.FieldFilters,
+		Page:         params.Page,
+		PerPage:      params.PerPage,
 	}
 	if params.CorrespondentID != nil {
 		templateParams.CorrespondentID = params.CorrespondentID.String()
 	}
+	if params.DocumentTypeID != nil {
+		templateParams.DocumentTypeID = params.DocumentTypeID.String()
+	}
 	for _, tagID := range params.TagIDs {
 		templateParams.TagIDs = append(templateParams.TagIDs, tagID.String())
 	}
@@ -323,8 +507,20 @@ func (h *Handler) DocumentsPage(c echo.Context) error {
 		}
 	}
 
+	// Fetch all document types for filter dropdown (full page only)
+	allDocumentTypes, err := h.db.Queries.ListDocumentTypes(ctx)
+	if err != nil {
+		allDocumentTypes = []sqlc.DocumentType{} // Non-fatal
+	}
+
+	// Fetch custom fields for the field filter row (full page only)
+	allFields, err := h.db.Queries.ListCustomFields(ctx)
+	if err != nil {
+		allFields = []sqlc.CustomField{} // Non-fatal
+	}
+
 	// Full page - render Documents template with search results
-	return admin.DocumentsWithSearch(results, docTags, docCorrespondents, templateParams, int(total), activeFilters, allTags, allCorrespondents).
+	return admin.DocumentsWithSearch(results, docTags, docCorrespondents, templateParams, int(total), activeFilters, allTags, allCorrespondents, allDocumentTypes, allFields).
 		Render(ctx, c.Response().Writer)
 }
 
@@ -370,11 +566,89 @@ func (h *Handler) DocumentDetail(c echo.Context) error {
 	// Check if AI is enabled (has available providers)
 	aiEnabled := len(h.aiSvc.AvailableProviders()) > 0
 
-	return admin.DocumentDetail(doc, tags, correspondent, aiSuggestions, aiEnabled).Render(ctx, c.Response().Writer)
+	// Fetch the document this one was extracted from (e.g. an email), if any
+	var parent *sqlc.Document
+	if doc.ParentDocumentID.Valid {
+		p, err := h.db.Queries.GetDocument(ctx, doc.ParentDocumentID.Bytes)
+		if err == nil {
+			parent = &p
+		}
+	}
+
+	// Fetch documents extracted from this one (e.g. email attachments)
+	children, err := h.db.Queries.ListChildDocuments(ctx, pgtype.UUID{Bytes: docID, Valid: true})
+	if err != nil {
+		children = []sqlc.Document{}
+	}
+
+	// Fetch the event history (includes links to earlier revisions)
+	events, err := h.docSvc.GetEvents(ctx, docID)
+	if err != nil {
+		events = []sqlc.DocumentEvent{}
+	}
+
+	// Fetch custom field values and the fields that can be added
+	fieldValues, err := h.db.Queries.ListDocumentCustomFields(ctx, docID)
+	if err != nil {
+		fieldValues = []sqlc.ListDocumentCustomFieldsRow{}
+	}
+	fields, err := h.db.Queries.ListCustomFields(ctx)
+	if err != nil {
+		fields = []sqlc.CustomField{}
+	}
+
+	// Fetch document types for the type picker
+	docTypes, err := h.db.Queries.ListDocumentTypes(ctx)
+	if err != nil {
+		docTypes = []sqlc.DocumentType{}
+	}
+
+	return admin.DocumentDetail(doc, tags, correspondent, aiSuggestions, aiEnabled, parent, children, events, fieldValues, fields, docTypes).Render(ctx, c.Response().Writer)
+}
+
+// UpdateDocumentDetails saves a document's title and notes
+// POST /documents/:id/details (form: title, notes; empty clears)
+func (h *Handler) UpdateDocumentDetails(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	docID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid document ID")
+	}
+
+	title := strings.TrimSpace(c.FormValue("title"))
+	notes := strings.TrimSpace(c.FormValue("notes"))
+
+	// Prepare title and notes (nil if empty)
+	var titlePtr, notesPtr *string
+	if title != "" {
+		titlePtr = &title
+	}
+	if notes != "" {
+		notesPtr = &notes
+	}
+
+	doc, err := h.db.Queries.UpdateDocumentDetails(ctx, sqlc.UpdateDocumentDetailsParams{
+		ID:    docID,
+		Title: titlePtr,
+		Notes: notesPtr,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return echo.NewHTTPError(http.StatusNotFound, "document not found")
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to update document")
+	}
+
+	if wantsJSON(c) {
+		return c.JSON(http.StatusOK, doc)
+	}
+	c.Response().Header().Set("HX-Redirect", "/documents/"+docID.String())
+	return c.NoContent(http.StatusOK)
 }
 
 // ViewPDF serves a PDF file inline for browser viewing
-// GET /documents/:id/view
+// GET /documents/:id/view?version=archive|original
 func (h *Handler) ViewPDF(c echo.Context) error {
 	ctx := c.Request().Context()
 
@@ -388,17 +662,19 @@ func (h *Handler) ViewPDF(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusNotFound, "document not found")
 	}
 
-	pdfPath := h.docSvc.OriginalPath(&doc)
-	if !h.docSvc.FileExists(pdfPath) {
-		return echo.NewHTTPError(http.StatusNotFound, "PDF file not found")
+	if wantsArchive(c) {
+		if doc.ArchiveChecksum == nil {
+			return echo.NewHTTPError(http.StatusNotFound, "no archive version")
+		}
+		return h.serveStored(c, h.docSvc.ArchiveKey(&doc), "inline", document.PDFFilename(&doc), "archive file not found")
 	}
 
 	// Serve with Content-Disposition: inline for browser viewing
-	return c.Inline(pdfPath, doc.OriginalFilename)
+	return h.serveStored(c, h.docSvc.PDFKey(&doc), "inline", document.PDFFilename(&doc), "PDF file not found")
 }
 
-// DownloadPDF serves a PDF file as attachment for download
-// GET /documents/:id/download
+// DownloadPDF serves the original file as attachment for download
+// GET /documents/:id/download?version=archive|original
 func (h *Handler) DownloadPDF(c echo.Context) error {
 	ctx := c.Request().Context()
 
@@ -412,13 +688,42 @@ func (h *Handler) DownloadPDF(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusNotFound, "document not found")
 	}
 
-	pdfPath := h.docSvc.OriginalPath(&doc)
-	if !h.docSvc.FileExists(pdfPath) {
-		return echo.NewHTTPError(http.StatusNotFound, "PDF file not found")
+	if wantsArchive(c) {
+		if doc.ArchiveChecksum == nil {
+			return echo.NewHTTPError(http.StatusNotFound, "no archive version")
+		}
+		return h.serveStored(c, h.docSvc.ArchiveKey(&doc), "attachment", document.PDFFilename(&doc), "archive file not found")
 	}
 
+	// Download defaults to the original as ingested (PDF or image)
 	// Serve with Content-Disposition: attachment for download
-	return c.Attachment(pdfPath, doc.OriginalFilename)
+	return h.serveStored(c, h.docSvc.OriginalKey(&doc), "attachment", doc.OriginalFilename, "original file not found")
+}
+
+// wantsArchive reports whether the request asks for the searchable archive version
+func wantsArchive(c echo.Context) bool {
+	return c.QueryParam("version") == "archive"
+}
+
+// serveStored streams a stored file with range request support
+// disposition is "inline", "attachment" or empty for none; a missing file
+// is a 404 with notFound as the message.
+func (h *Handler) serveStored(c echo.Context, key, disposition, filename, notFound string) error {
+	obj, info, err := h.docSvc.OpenFile(c.Request().Context(), key)
+	if err != nil {
+		if storage.IsNotExist(err) {
+			return echo.NewHTTPError(http.StatusNotFound, notFound)
+		}
+		slog.Error("failed to open stored file", "key", key, "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to read file")
+	}
+	defer func() { _ = obj.Close() }()
+
+	if disposition != "" {
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("%s; filename=%q", disposition, filename))
+	}
+	http.ServeContent(c.Response(), c.Request(), path.Base(key), info.ModTime, obj)
+	return nil
 }
 
 // ServeThumbnail serves a document's thumbnail image
@@ -441,13 +746,8 @@ func (h *Handler) ServeThumbnail(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusNotFound, "thumbnail not generated")
 	}
 
-	thumbnailPath := h.docSvc.ThumbnailPath(&doc)
-	if !h.docSvc.FileExists(thumbnailPath) {
-		return echo.NewHTTPError(http.StatusNotFound, "thumbnail file not found")
-	}
-
 	// Serve thumbnail image
-	return c.File(thumbnailPath)
+	return h.serveStored(c, h.docSvc.ThumbnailKey(&doc), "", "", "thumbnail file not found")
 }
 
 // ViewerModal returns the PDF viewer modal HTML for HTMX
@@ -519,3 +819,53 @@ func (h *Handler) RetryDocument(c echo.Context) error {
 	return partials.DocumentStatus(docID.String(), "pending", "", "").
 		Render(ctx, c.Response().Writer)
 }
+
+// UnlockDocument decrypts a password-protected PDF with a password entered
+// on the document page and queues it for processing again
+// POST /documents/:id/unlock (form: password, save)
+func (h *Handler) UnlockDocument(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	docID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid document ID")
+	}
+
+	doc, err := h.db.Queries.GetDocument(ctx, docID)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Document not found")
+	}
+	if doc.ProcessingStatus != sqlc.ProcessingStatusNeedsPassword {
+		return c.String(http.StatusBadRequest, "Document isn't waiting for a password")
+	}
+
+	password := c.FormValue("password")
+	if password == "" {
+		return admin.UnlockForm(docID.String(), "Enter the PDF's password").Render(ctx, c.Response().Writer)
+	}
+
+	if err := h.docSvc.Unlock(ctx, docID, password); err != nil {
+		if errors.Is(err, pdf.ErrWrongPassword) {
+			return admin.UnlockForm(docID.String(), "That password doesn't open this PDF").Render(ctx, c.Response().Writer)
+		}
+		slog.Error("failed to unlock document", "doc_id", docID, "error", err)
+		return admin.UnlockForm(docID.String(), "Failed to unlock the PDF").Render(ctx, c.Response().Writer)
+	}
+
+	// Other PDFs from the same sender likely use the same password
+	if c.FormValue("save") == "true" {
+		if _, err := h.passwords.Add(ctx, "Entered for "+doc.OriginalFilename, password); err != nil {
+			slog.Warn("failed to save pdf password", "doc_id", docID, "error", err)
+		}
+	}
+
+	if h.broadcaster != nil {
+		h.broadcaster.Broadcast(processing.StatusUpdate{
+			DocumentID: docID,
+			Status:     processing.StatusPending,
+		})
+	}
+
+	c.Response().Header().Set("HX-Redirect", "/documents/"+docID.String())
+	return c.NoContent(http.StatusOK)
+}
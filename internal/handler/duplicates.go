@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/bketelsen/docko/internal/document"
+	"github.com/bketelsen/docko/templates/pages/admin"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// DuplicatesPage renders the near-duplicate review screen
+// GET /duplicates
+func (h *Handler) DuplicatesPage(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	candidates, err := h.db.Queries.ListPendingDuplicateCandidates(ctx)
+	if err != nil {
+		slog.Error("failed to list duplicate candidates", "error", err)
+		return c.String(http.StatusInternalServerError, "Failed to load duplicates")
+	}
+
+	return admin.Duplicates(candidates).Render(ctx, c.Response().Writer)
+}
+
+// MergeDuplicate keeps one document of a candidate pair and trashes the other
+// POST /duplicates/:id/merge (form: keep=<document id>)
+func (h *Handler) MergeDuplicate(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	candidateID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid candidate ID")
+	}
+	keepID, err := uuid.Parse(c.FormValue("keep"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid document ID")
+	}
+
+	if _, err := h.docSvc.MergeDuplicate(ctx, candidateID, keepID); err != nil {
+		switch {
+		case errors.Is(err, document.ErrCandidateResolved):
+			return c.String(http.StatusOK, "") // Already handled, drop the row
+		case errors.Is(err, document.ErrNotInCandidate):
+			return c.String(http.StatusBadRequest, "Document is not part of this pair")
+		}
+		slog.Error("failed to merge duplicate", "candidate_id", candidateID, "error", err)
+		c.Response().Header().Set("HX-Trigger", `{"showToast": {"message": "Failed to merge documents", "type": "error"}}`)
+		return c.NoContent(http.StatusInternalServerError)
+	}
+
+	c.Response().Header().Set("HX-Trigger", `{"showToast": {"message": "Documents merged, duplicate moved to trash", "type": "success"}}`)
+	return c.String(http.StatusOK, "") // Return empty to remove the row
+}
+
+// MarkDistinct records that a candidate pair are different documents
+// POST /duplicates/:id/distinct
+func (h *Handler) MarkDistinct(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	candidateID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid candidate ID")
+	}
+
+	if err := h.docSvc.MarkDistinct(ctx, candidateID); err != nil && !errors.Is(err, document.ErrCandidateResolved) {
+		slog.Error("failed to mark duplicate distinct", "candidate_id", candidateID, "error", err)
+		c.Response().Header().Set("HX-Trigger", `{"showToast": {"message": "Failed to update duplicate", "type": "error"}}`)
+		return c.NoContent(http.StatusInternalServerError)
+	}
+
+	c.Response().Header().Set("HX-Trigger", `{"showToast": {"message": "Marked as distinct documents", "type": "success"}}`)
+	return c.String(http.StatusOK, "") // Return empty to remove the row
+}
@@ -3,6 +3,7 @@ package handler
 import (
 	"github.com/bketelsen/docko/internal/ai"
 	"github.com/bketelsen/docko/internal/auth"
+	"github.com/bketelsen/docko/internal/backup"
 	"github.com/bketelsen/docko/internal/config"
 	"github.com/bketelsen/docko/internal/database"
 	"github.com/bketelsen/docko/internal/document"
@@ -23,11 +24,13 @@ type Handler struct {
 	inboxSvc    *inbox.Service
 	networkSvc  *network.Service
 	aiSvc       *ai.Service
+	backupSvc   *backup.Service
+	passwords   *processing.Passwords
 	queue       *queue.Queue
 	broadcaster *processing.StatusBroadcaster
 }
 
-func New(cfg *config.Config, db *database.DB, authService *auth.Service, docSvc *document.Service, inboxSvc *inbox.Service, networkSvc *network.Service, aiSvc *ai.Service, q *queue.Queue, broadcaster *processing.StatusBroadcaster) *Handler {
+func New(cfg *config.Config, db *database.DB, authService *auth.Service, docSvc *document.Service, inboxSvc *inbox.Service, networkSvc *network.Service, aiSvc *ai.Service, backupSvc *backup.Service, passwords *processing.Passwords, q *queue.Queue, broadcaster *processing.StatusBroadcaster) *Handler {
 	return &Handler{
 		cfg:         cfg,
 		db:          db,
@@ -36,6 +39,8 @@ func New(cfg *config.Config, db *database.DB, authService *auth.Service, docSvc
 		inboxSvc:    inboxSvc,
 		networkSvc:  networkSvc,
 		aiSvc:       aiSvc,
+		backupSvc:   backupSvc,
+		passwords:   passwords,
 		queue:       q,
 		broadcaster: broadcaster,
 	}
@@ -66,6 +71,7 @@ func (h *Handler) RegisterRoutes(e *echo.Echo) {
 	e.GET("/inboxes", h.InboxesPage, middleware.RequireAuth(h.auth))
 	e.POST("/inboxes", h.CreateInbox, middleware.RequireAuth(h.auth))
 	e.PUT("/inboxes/:id", h.UpdateInbox, middleware.RequireAuth(h.auth))
+	e.PUT("/inboxes/:id/defaults", h.UpdateInboxDefaults, middleware.RequireAuth(h.auth))
 	e.DELETE("/inboxes/:id", h.DeleteInbox, middleware.RequireAuth(h.auth))
 	e.POST("/inboxes/:id/toggle", h.ToggleInbox, middleware.RequireAuth(h.auth))
 	e.GET("/inboxes/:id/events", h.InboxEvents, middleware.RequireAuth(h.auth))
@@ -74,6 +80,7 @@ func (h *Handler) RegisterRoutes(e *echo.Echo) {
 	e.GET("/network-sources", h.NetworkSourcesPage, middleware.RequireAuth(h.auth))
 	e.POST("/network-sources", h.CreateNetworkSource, middleware.RequireAuth(h.auth))
 	e.DELETE("/network-sources/:id", h.DeleteNetworkSource, middleware.RequireAuth(h.auth))
+	e.PUT("/network-sources/:id/defaults", h.UpdateNetworkSourceDefaults, middleware.RequireAuth(h.auth))
 	e.POST("/network-sources/:id/toggle", h.ToggleNetworkSource, middleware.RequireAuth(h.auth))
 	e.POST("/network-sources/:id/test", h.TestNetworkSourceConnection, middleware.RequireAuth(h.auth))
 	e.POST("/network-sources/:id/sync", h.SyncNetworkSource, middleware.RequireAuth(h.auth))
@@ -86,6 +93,18 @@ func (h *Handler) RegisterRoutes(e *echo.Echo) {
 	e.POST("/tags/:id", h.UpdateTag, middleware.RequireAuth(h.auth))
 	e.DELETE("/tags/:id", h.DeleteTag, middleware.RequireAuth(h.auth))
 
+	// Document type management routes
+	e.GET("/document-types", h.DocumentTypesPage, middleware.RequireAuth(h.auth))
+	e.POST("/document-types", h.CreateDocumentType, middleware.RequireAuth(h.auth))
+	e.POST("/document-types/:id", h.UpdateDocumentType, middleware.RequireAuth(h.auth))
+	e.DELETE("/document-types/:id", h.DeleteDocumentType, middleware.RequireAuth(h.auth))
+
+	// Custom field management routes
+	e.GET("/custom-fields", h.CustomFieldsPage, middleware.RequireAuth(h.auth))
+	e.POST("/custom-fields", h.CreateCustomField, middleware.RequireAuth(h.auth))
+	e.POST("/custom-fields/:id", h.RenameCustomField, middleware.RequireAuth(h.auth))
+	e.DELETE("/custom-fields/:id", h.DeleteCustomField, middleware.RequireAuth(h.auth))
+
 	// Correspondent management routes (protected)
 	e.GET("/correspondents", h.CorrespondentsPage, middleware.RequireAuth(h.auth))
 	e.GET("/correspondents/search", h.SearchCorrespondentsForDocument, middleware.RequireAuth(h.auth))
@@ -94,15 +113,36 @@ func (h *Handler) RegisterRoutes(e *echo.Echo) {
 	e.POST("/correspondents/:id", h.UpdateCorrespondent, middleware.RequireAuth(h.auth))
 	e.DELETE("/correspondents/:id", h.DeleteCorrespondent, middleware.RequireAuth(h.auth))
 
+	// PDF password management routes (protected)
+	e.GET("/pdf-passwords", h.PDFPasswordsPage, middleware.RequireAuth(h.auth))
+	e.POST("/pdf-passwords", h.CreatePDFPassword, middleware.RequireAuth(h.auth))
+	e.DELETE("/pdf-passwords/:id", h.DeletePDFPassword, middleware.RequireAuth(h.auth))
+
 	// Document routes (protected)
 	e.GET("/documents", h.DocumentsPage, middleware.RequireAuth(h.auth))
 	e.GET("/documents/:id", h.DocumentDetail, middleware.RequireAuth(h.auth))
+	e.DELETE("/documents/:id", h.TrashDocument, middleware.RequireAuth(h.auth))
 	e.GET("/documents/:id/view", h.ViewPDF, middleware.RequireAuth(h.auth))
 	e.GET("/documents/:id/download", h.DownloadPDF, middleware.RequireAuth(h.auth))
 	e.GET("/documents/:id/thumbnail", h.ServeThumbnail, middleware.RequireAuth(h.auth))
 	e.GET("/documents/:id/viewer", h.ViewerModal, middleware.RequireAuth(h.auth))
+	e.POST("/documents/:id/details", h.UpdateDocumentDetails, middleware.RequireAuth(h.auth))
 	e.POST("/documents/:id/analyze", h.ReanalyzeDocument, middleware.RequireAuth(h.auth))
 	e.POST("/api/documents/:id/retry", h.RetryDocument, middleware.RequireAuth(h.auth))
+	e.POST("/documents/:id/unlock", h.UnlockDocument, middleware.RequireAuth(h.auth))
+
+	// Document revision routes (protected)
+	e.POST("/documents/:id/replace", h.ReplaceDocumentFile, middleware.RequireAuth(h.auth))
+	e.POST("/api/documents/:id/revisions", h.ReplaceDocumentFile, middleware.RequireAuth(h.auth))
+	e.GET("/documents/:id/revisions/:rev/view", h.ViewRevision, middleware.RequireAuth(h.auth))
+	e.GET("/documents/:id/revisions/:rev/download", h.DownloadRevision, middleware.RequireAuth(h.auth))
+
+	// Page editing routes (protected)
+	e.POST("/documents/:id/pages/rotate", h.RotatePages, middleware.RequireAuth(h.auth))
+	e.POST("/documents/:id/pages/delete", h.DeletePages, middleware.RequireAuth(h.auth))
+	e.POST("/documents/:id/pages/reorder", h.ReorderPages, middleware.RequireAuth(h.auth))
+	e.POST("/documents/:id/pages/split", h.SplitDocument, middleware.RequireAuth(h.auth))
+	e.POST("/documents/merge", h.MergeDocuments, middleware.RequireAuth(h.auth))
 
 	// Document tag assignment routes (protected)
 	e.GET("/documents/:id/tags/search", h.SearchTagsForDocument, middleware.RequireAuth(h.auth))
@@ -110,11 +150,38 @@ func (h *Handler) RegisterRoutes(e *echo.Echo) {
 	e.POST("/documents/:id/tags", h.AddDocumentTag, middleware.RequireAuth(h.auth))
 	e.DELETE("/documents/:id/tags/:tag_id", h.RemoveDocumentTag, middleware.RequireAuth(h.auth))
 
+	// Document type route
+	e.POST("/documents/:id/type", h.SetDocumentType, middleware.RequireAuth(h.auth))
+
+	// Document custom field routes
+	e.POST("/documents/:id/fields", h.SetDocumentCustomField, middleware.RequireAuth(h.auth))
+	e.DELETE("/documents/:id/fields/:field_id", h.RemoveDocumentCustomField, middleware.RequireAuth(h.auth))
+
 	// Document correspondent assignment routes (protected)
 	e.GET("/documents/:id/correspondent", h.GetDocumentCorrespondent, middleware.RequireAuth(h.auth))
 	e.POST("/documents/:id/correspondent", h.SetDocumentCorrespondent, middleware.RequireAuth(h.auth))
 	e.DELETE("/documents/:id/correspondent", h.RemoveDocumentCorrespondent, middleware.RequireAuth(h.auth))
 
+	// Trash routes (protected)
+	e.GET("/trash", h.TrashPage, middleware.RequireAuth(h.auth))
+	e.POST("/trash/empty", h.EmptyTrash, middleware.RequireAuth(h.auth))
+	e.POST("/trash/:id/restore", h.RestoreDocument, middleware.RequireAuth(h.auth))
+	e.DELETE("/trash/:id", h.PurgeDocument, middleware.RequireAuth(h.auth))
+
+	// Near-duplicate review routes (protected)
+	e.GET("/duplicates", h.DuplicatesPage, middleware.RequireAuth(h.auth))
+	e.POST("/duplicates/:id/merge", h.MergeDuplicate, middleware.RequireAuth(h.auth))
+	e.POST("/duplicates/:id/distinct", h.MarkDistinct, middleware.RequireAuth(h.auth))
+
+	// Storage integrity routes (protected)
+	e.GET("/integrity", h.IntegrityPage, middleware.RequireAuth(h.auth))
+	e.POST("/integrity/run", h.RunIntegrityCheck, middleware.RequireAuth(h.auth))
+	e.POST("/integrity/issues/:id/thumbnail", h.RegenerateIssueThumbnail, middleware.RequireAuth(h.auth))
+	e.POST("/integrity/issues/:id/reprocess", h.ReprocessIssueDocument, middleware.RequireAuth(h.auth))
+
+	// Backup download (protected)
+	e.GET("/backup", h.DownloadBackup, middleware.RequireAuth(h.auth))
+
 	// SSE endpoint for processing status (protected)
 	e.GET("/api/processing/status", h.ProcessingStatus, middleware.RequireAuth(h.auth))
 
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -9,21 +10,23 @@ import (
 	"strings"
 
 	"github.com/bketelsen/docko/internal/database/sqlc"
+	"github.com/bketelsen/docko/internal/document"
 	"github.com/bketelsen/docko/templates/pages/admin"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/labstack/echo/v4"
 )
 
-// countPDFsInDir counts .pdf files in a directory
-func countPDFsInDir(dir string) int {
+// countFilesInDir counts ingestible files in a directory
+func countFilesInDir(dir string) int {
 	entries, err := os.ReadDir(dir)
 	if err != nil {
 		return 0 // Directory doesn't exist or can't be read
 	}
 	count := 0
 	for _, e := range entries {
-		if !e.IsDir() && strings.HasSuffix(strings.ToLower(e.Name()), ".pdf") {
+		if !e.IsDir() && document.IsSupportedFilename(e.Name()) {
 			count++
 		}
 	}
@@ -38,6 +41,28 @@ func resolveErrorPath(inbox sqlc.Inbox) string {
 	return filepath.Join(inbox.Path, "errors")
 }
 
+// parseIngestDefaults reads the default tags, correspondent and document type
+// fields shared by the inbox and network source forms
+// Tags are comma-separated; repeats are dropped.
+func parseIngestDefaults(c echo.Context) (tags []string, correspondent, documentType *string) {
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(c.FormValue("default_tags"), ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[strings.ToLower(name)] {
+			continue
+		}
+		seen[strings.ToLower(name)] = true
+		tags = append(tags, name)
+	}
+	if v := strings.TrimSpace(c.FormValue("default_correspondent")); v != "" {
+		correspondent = &v
+	}
+	if v := strings.TrimSpace(c.FormValue("default_document_type")); v != "" {
+		documentType = &v
+	}
+	return tags, correspondent, documentType
+}
+
 // InboxesPage renders the inbox management page
 func (h *Handler) InboxesPage(c echo.Context) error {
 	ctx := c.Request().Context()
@@ -54,7 +79,7 @@ func (h *Handler) InboxesPage(c echo.Context) error {
 		errorPath := resolveErrorPath(inbox)
 		inboxesWithCounts[i] = admin.InboxWithErrorCount{
 			Inbox:      inbox,
-			ErrorCount: countPDFsInDir(errorPath),
+			ErrorCount: countFilesInDir(errorPath),
 		}
 	}
 
@@ -69,6 +94,9 @@ func (h *Handler) CreateInbox(c echo.Context) error {
 	path := c.FormValue("path")
 	errorPath := c.FormValue("error_path")
 	duplicateAction := c.FormValue("duplicate_action")
+	recursive := c.FormValue("recursive") == "true"
+	folderTags := c.FormValue("folder_tags") == "true"
+	defaultTags, defaultCorrespondent, defaultDocumentType := parseIngestDefaults(c)
 
 	// Validate required fields
 	if name == "" || path == "" {
@@ -108,11 +136,16 @@ func (h *Handler) CreateInbox(c echo.Context) error {
 	}
 
 	inbox, err := h.db.Queries.CreateInbox(ctx, sqlc.CreateInboxParams{
-		Path:            path,
-		Name:            name,
-		ErrorPath:       errorPathPtr,
-		DuplicateAction: action,
-		Enabled:         true,
+		Path:                 path,
+		Name:                 name,
+		ErrorPath:            errorPathPtr,
+		DuplicateAction:      action,
+		Enabled:              true,
+		Recursive:            recursive,
+		FolderTags:           folderTags,
+		DefaultTags:          defaultTags,
+		DefaultCorrespondent: defaultCorrespondent,
+		DefaultDocumentType:  defaultDocumentType,
 	})
 	if err != nil {
 		slog.Error("failed to create inbox", "error", err)
@@ -143,6 +176,8 @@ func (h *Handler) UpdateInbox(c echo.Context) error {
 	errorPath := c.FormValue("error_path")
 	duplicateAction := c.FormValue("duplicate_action")
 	enabled := c.FormValue("enabled") == "true"
+	recursive := c.FormValue("recursive") == "true"
+	folderTags := c.FormValue("folder_tags") == "true"
 
 	// Validate required fields
 	if name == "" || path == "" {
@@ -179,14 +214,16 @@ func (h *Handler) UpdateInbox(c echo.Context) error {
 		ErrorPath:       errorPathPtr,
 		DuplicateAction: action,
 		Enabled:         enabled,
+		Recursive:       recursive,
+		FolderTags:      folderTags,
 	})
 	if err != nil {
 		slog.Error("failed to update inbox", "error", err)
 		return c.String(http.StatusInternalServerError, "Failed to update inbox")
 	}
 
-	// Update watcher if path changed or enabled status changed
-	if oldInbox.Path != path || oldInbox.Enabled != enabled {
+	// Update watcher if path, recursion or enabled status changed
+	if oldInbox.Path != path || oldInbox.Recursive != recursive || oldInbox.Enabled != enabled {
 		// Remove old path from watcher
 		if err := h.inboxSvc.RemoveInbox(id); err != nil {
 			slog.Warn("failed to remove old inbox from watcher", "error", err)
@@ -203,6 +240,36 @@ func (h *Handler) UpdateInbox(c echo.Context) error {
 	return admin.InboxCard(inbox).Render(ctx, c.Response().Writer)
 }
 
+// UpdateInboxDefaults sets the metadata applied to everything an inbox imports
+func (h *Handler) UpdateInboxDefaults(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid inbox ID")
+	}
+
+	tags, correspondent, documentType := parseIngestDefaults(c)
+	inbox, err := h.db.Queries.UpdateInboxDefaults(ctx, sqlc.UpdateInboxDefaultsParams{
+		ID:                   id,
+		DefaultTags:          tags,
+		DefaultCorrespondent: correspondent,
+		DefaultDocumentType:  documentType,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.String(http.StatusNotFound, "Inbox not found")
+		}
+		slog.Error("failed to update inbox defaults", "error", err)
+		return c.String(http.StatusInternalServerError, "Failed to update inbox defaults")
+	}
+
+	return admin.InboxCardWithErrors(admin.InboxWithErrorCount{
+		Inbox:      inbox,
+		ErrorCount: countFilesInDir(resolveErrorPath(inbox)),
+	}).Render(ctx, c.Response().Writer)
+}
+
 // DeleteInbox removes an inbox
 func (h *Handler) DeleteInbox(c echo.Context) error {
 	ctx := c.Request().Context()
@@ -253,6 +320,8 @@ func (h *Handler) ToggleInbox(c echo.Context) error {
 		ErrorPath:       inbox.ErrorPath,
 		DuplicateAction: inbox.DuplicateAction,
 		Enabled:         newEnabled,
+		Recursive:       inbox.Recursive,
+		FolderTags:      inbox.FolderTags,
 	})
 	if err != nil {
 		slog.Error("failed to toggle inbox", "error", err)
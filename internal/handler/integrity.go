@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/bketelsen/docko/internal/database/sqlc"
+	"github.com/bketelsen/docko/internal/document"
+	"github.com/bketelsen/docko/internal/processing"
+	"github.com/bketelsen/docko/templates/pages/admin"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+)
+
+// IntegrityPage renders the latest storage integrity check results
+// GET /integrity
+func (h *Handler) IntegrityPage(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	check, err := h.db.Queries.GetLatestIntegrityCheck(ctx)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return admin.Integrity(nil, nil).Render(ctx, c.Response().Writer)
+	}
+	if err != nil {
+		slog.Error("failed to get integrity check", "error", err)
+		return c.String(http.StatusInternalServerError, "Failed to load integrity check")
+	}
+
+	issues, err := h.db.Queries.ListIntegrityIssues(ctx, check.ID)
+	if err != nil {
+		slog.Error("failed to list integrity issues", "error", err)
+		return c.String(http.StatusInternalServerError, "Failed to load integrity check")
+	}
+
+	return admin.Integrity(&check, issues).Render(ctx, c.Response().Writer)
+}
+
+// RunIntegrityCheck starts an integrity check in the background
+// POST /integrity/run
+func (h *Handler) RunIntegrityCheck(c echo.Context) error {
+	go func() {
+		// Outlives the request; a full check re-hashes every original
+		_, err := h.docSvc.CheckIntegrity(context.Background())
+		switch {
+		case errors.Is(err, document.ErrIntegrityCheckRunning):
+			slog.Info("integrity check already running")
+		case err != nil:
+			slog.Error("integrity check failed", "error", err)
+		}
+	}()
+
+	c.Response().Header().Set("HX-Trigger", `{"showToast": {"message": "Integrity check started, refresh the page for results", "type": "success"}}`)
+	return c.NoContent(http.StatusOK)
+}
+
+// RegenerateIssueThumbnail queues a thumbnail job for an issue's document
+// POST /integrity/issues/:id/thumbnail
+func (h *Handler) RegenerateIssueThumbnail(c echo.Context) error {
+	return h.fixIntegrityIssue(c, "Thumbnail queued for regeneration", func(issue sqlc.IntegrityIssue) error {
+		return h.docSvc.RegenerateThumbnail(c.Request().Context(), issue.DocumentID.Bytes)
+	})
+}
+
+// ReprocessIssueDocument re-queues processing for an issue's document
+// POST /integrity/issues/:id/reprocess
+func (h *Handler) ReprocessIssueDocument(c echo.Context) error {
+	return h.fixIntegrityIssue(c, "Document queued for processing", func(issue sqlc.IntegrityIssue) error {
+		docID := uuid.UUID(issue.DocumentID.Bytes)
+		if err := h.docSvc.Reprocess(c.Request().Context(), docID); err != nil {
+			return err
+		}
+		if h.broadcaster != nil {
+			h.broadcaster.Broadcast(processing.StatusUpdate{
+				DocumentID: docID,
+				Status:     "pending",
+			})
+		}
+		return nil
+	})
+}
+
+// fixIntegrityIssue runs fix for an issue's document and removes the issue
+func (h *Handler) fixIntegrityIssue(c echo.Context, message string, fix func(sqlc.IntegrityIssue) error) error {
+	ctx := c.Request().Context()
+
+	issueID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid issue ID")
+	}
+
+	issue, err := h.db.Queries.GetIntegrityIssue(ctx, issueID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return c.String(http.StatusOK, "") // Already handled, drop the row
+	}
+	if err != nil {
+		slog.Error("failed to get integrity issue", "issue_id", issueID, "error", err)
+		return c.String(http.StatusInternalServerError, "Failed to load issue")
+	}
+	if !issue.DocumentID.Valid {
+		return c.String(http.StatusBadRequest, "Issue has no document")
+	}
+
+	if err := fix(issue); err != nil {
+		slog.Error("failed to fix integrity issue", "issue_id", issueID, "error", err)
+		c.Response().Header().Set("HX-Trigger", `{"showToast": {"message": "Failed to queue the fix", "type": "error"}}`)
+		return c.NoContent(http.StatusInternalServerError)
+	}
+
+	if err := h.db.Queries.DeleteIntegrityIssue(ctx, issueID); err != nil {
+		slog.Warn("failed to delete integrity issue", "issue_id", issueID, "error", err)
+	}
+
+	c.Response().Header().Set("HX-Trigger", `{"showToast": {"message": "`+message+`", "type": "success"}}`)
+	return c.String(http.StatusOK, "") // Return empty to remove the row
+}
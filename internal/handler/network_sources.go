@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -10,6 +11,7 @@ import (
 	"github.com/bketelsen/docko/templates/pages/admin"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/labstack/echo/v4"
 )
 
@@ -42,6 +44,7 @@ func (h *Handler) CreateNetworkSource(c echo.Context) error {
 	moveSubfolder := c.FormValue("move_subfolder")
 	duplicateAction := c.FormValue("duplicate_action")
 	batchSizeStr := c.FormValue("batch_size")
+	defaultTags, defaultCorrespondent, defaultDocumentType := parseIngestDefaults(c)
 
 	// Validate required fields
 	if name == "" || host == "" || sharePath == "" {
@@ -55,6 +58,8 @@ func (h *Handler) CreateNetworkSource(c echo.Context) error {
 		proto = sqlc.NetworkProtocolSmb
 	case "nfs":
 		proto = sqlc.NetworkProtocolNfs
+	case "imap":
+		proto = sqlc.NetworkProtocolImap
 	default:
 		return c.String(http.StatusBadRequest, "Invalid protocol")
 	}
@@ -112,18 +117,21 @@ func (h *Handler) CreateNetworkSource(c echo.Context) error {
 
 	// Create in database (disabled by default until tested)
 	source, err := h.db.Queries.CreateNetworkSource(ctx, sqlc.CreateNetworkSourceParams{
-		Name:              name,
-		Protocol:          proto,
-		Host:              host,
-		SharePath:         sharePath,
-		Username:          usernamePtr,
-		PasswordEncrypted: passwordEncrypted,
-		Enabled:           false, // Require test connection first
-		ContinuousSync:    continuousSync,
-		PostImportAction:  postAction,
-		MoveSubfolder:     moveSubfolderPtr,
-		DuplicateAction:   dupAction,
-		BatchSize:         batchSize,
+		Name:                 name,
+		Protocol:             proto,
+		Host:                 host,
+		SharePath:            sharePath,
+		Username:             usernamePtr,
+		PasswordEncrypted:    passwordEncrypted,
+		Enabled:              false, // Require test connection first
+		ContinuousSync:       continuousSync,
+		PostImportAction:     postAction,
+		MoveSubfolder:        moveSubfolderPtr,
+		DuplicateAction:      dupAction,
+		BatchSize:            batchSize,
+		DefaultTags:          defaultTags,
+		DefaultCorrespondent: defaultCorrespondent,
+		DefaultDocumentType:  defaultDocumentType,
 	})
 	if err != nil {
 		slog.Error("failed to create network source", "error", err)
@@ -197,6 +205,33 @@ func (h *Handler) ToggleNetworkSource(c echo.Context) error {
 	return admin.NetworkSourceCard(updated).Render(ctx, c.Response().Writer)
 }
 
+// UpdateNetworkSourceDefaults sets the metadata applied to everything a source imports
+func (h *Handler) UpdateNetworkSourceDefaults(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid source ID")
+	}
+
+	tags, correspondent, documentType := parseIngestDefaults(c)
+	source, err := h.db.Queries.UpdateNetworkSourceDefaults(ctx, sqlc.UpdateNetworkSourceDefaultsParams{
+		ID:                   id,
+		DefaultTags:          tags,
+		DefaultCorrespondent: correspondent,
+		DefaultDocumentType:  documentType,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c.String(http.StatusNotFound, "Source not found")
+		}
+		slog.Error("failed to update network source defaults", "error", err)
+		return c.String(http.StatusInternalServerError, "Failed to update source defaults")
+	}
+
+	return admin.NetworkSourceCard(source).Render(ctx, c.Response().Writer)
+}
+
 // SyncNetworkSource triggers a manual sync for a source
 func (h *Handler) SyncNetworkSource(c echo.Context) error {
 	ctx := c.Request().Context()
@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/bketelsen/docko/internal/database/sqlc"
+	"github.com/bketelsen/docko/internal/document"
+	"github.com/bketelsen/docko/internal/pdf"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// RotatePages rotates the selected pages of a document
+// POST /documents/:id/pages/rotate (form: pages, degrees)
+func (h *Handler) RotatePages(c echo.Context) error {
+	docID, pages, err := h.parsePageForm(c, "pages")
+	if err != nil {
+		return err
+	}
+	degrees, err := strconv.Atoi(c.FormValue("degrees"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid rotation")
+	}
+
+	doc, err := h.docSvc.RotatePages(c.Request().Context(), docID, pages, degrees)
+	return h.pageEditResponse(c, docID, doc, err)
+}
+
+// DeletePages removes the selected pages from a document
+// POST /documents/:id/pages/delete (form: pages)
+func (h *Handler) DeletePages(c echo.Context) error {
+	docID, pages, err := h.parsePageForm(c, "pages")
+	if err != nil {
+		return err
+	}
+
+	doc, err := h.docSvc.DeletePages(c.Request().Context(), docID, pages)
+	return h.pageEditResponse(c, docID, doc, err)
+}
+
+// ReorderPages puts a document's pages in a new order
+// POST /documents/:id/pages/reorder (form: order, e.g. "3,1,2")
+func (h *Handler) ReorderPages(c echo.Context) error {
+	docID, order, err := h.parsePageForm(c, "order")
+	if err != nil {
+		return err
+	}
+
+	doc, err := h.docSvc.ReorderPages(c.Request().Context(), docID, order)
+	return h.pageEditResponse(c, docID, doc, err)
+}
+
+// SplitDocument splits a document into two after the given page
+// POST /documents/:id/pages/split (form: after)
+func (h *Handler) SplitDocument(c echo.Context) error {
+	docID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid document ID")
+	}
+	after, err := strconv.Atoi(c.FormValue("after"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid page number")
+	}
+
+	newDoc, err := h.docSvc.SplitDocument(c.Request().Context(), docID, after)
+	if err != nil {
+		return h.pageEditResponse(c, docID, nil, err)
+	}
+
+	if wantsJSON(c) {
+		return c.JSON(http.StatusOK, newDoc)
+	}
+	c.Response().Header().Set("HX-Redirect", "/documents/"+docID.String())
+	return c.NoContent(http.StatusOK)
+}
+
+// MergeDocuments merges the selected documents, in order, into the first
+// POST /documents/merge (form: doc_id, repeated)
+func (h *Handler) MergeDocuments(c echo.Context) error {
+	params, err := c.FormParams()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid form")
+	}
+
+	ids := make([]uuid.UUID, 0, len(params["doc_id"]))
+	for _, raw := range params["doc_id"] {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid document ID")
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) < 2 {
+		return echo.NewHTTPError(http.StatusBadRequest, "select at least two documents to merge")
+	}
+
+	doc, err := h.docSvc.MergeDocuments(c.Request().Context(), ids)
+	return h.pageEditResponse(c, ids[0], doc, err)
+}
+
+// parsePageForm parses the document ID and a page selection form field
+func (h *Handler) parsePageForm(c echo.Context, field string) (uuid.UUID, []int, error) {
+	ctx := c.Request().Context()
+
+	docID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return uuid.Nil, nil, echo.NewHTTPError(http.StatusBadRequest, "invalid document ID")
+	}
+
+	doc, err := h.docSvc.GetByID(ctx, docID)
+	if err != nil {
+		return uuid.Nil, nil, echo.NewHTTPError(http.StatusNotFound, "document not found")
+	}
+	pageCount, err := h.docSvc.PageCount(ctx, doc)
+	if err != nil {
+		slog.Error("failed to count pages", "doc_id", docID, "error", err)
+		return uuid.Nil, nil, echo.NewHTTPError(http.StatusInternalServerError, "failed to read document")
+	}
+
+	pages, err := pdf.ParsePages(c.FormValue(field), pageCount)
+	if err != nil {
+		return uuid.Nil, nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	return docID, pages, nil
+}
+
+// pageEditResponse maps a page operation result to a response: the updated
+// document as JSON, or a redirect back to the detail page for HTMX
+func (h *Handler) pageEditResponse(c echo.Context, docID uuid.UUID, doc *sqlc.Document, err error) error {
+	if err != nil {
+		if errors.Is(err, pdf.ErrInvalidPages) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		if errors.Is(err, document.ErrSplitDuplicate) || errors.Is(err, document.ErrTrashed) || errors.Is(err, document.ErrEditConflict) {
+			return echo.NewHTTPError(http.StatusConflict, err.Error())
+		}
+		slog.Error("failed to edit document pages", "doc_id", docID, "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to edit document pages")
+	}
+
+	if wantsJSON(c) {
+		return c.JSON(http.StatusOK, doc)
+	}
+	c.Response().Header().Set("HX-Redirect", "/documents/"+docID.String())
+	return c.NoContent(http.StatusOK)
+}
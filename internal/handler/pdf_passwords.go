@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/bketelsen/docko/templates/pages/admin"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// PDFPasswordsPage renders the list of passwords tried on encrypted PDFs
+func (h *Handler) PDFPasswordsPage(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	passwords, err := h.passwords.List(ctx)
+	if err != nil {
+		slog.Error("failed to list pdf passwords", "error", err)
+		return c.String(http.StatusInternalServerError, "Failed to load passwords")
+	}
+
+	return admin.PDFPasswords(passwords).Render(ctx, c.Response().Writer)
+}
+
+// CreatePDFPassword adds a password to the list
+func (h *Handler) CreatePDFPassword(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	label := strings.TrimSpace(c.FormValue("label"))
+	password := c.FormValue("password")
+	if label == "" || password == "" {
+		return c.String(http.StatusBadRequest, "Label and password are required")
+	}
+
+	stored, err := h.passwords.Add(ctx, label, password)
+	if err != nil {
+		slog.Error("failed to create pdf password", "error", err)
+		return c.String(http.StatusInternalServerError, "Failed to save password")
+	}
+
+	return admin.PDFPasswordCard(stored).Render(ctx, c.Response().Writer)
+}
+
+// DeletePDFPassword removes a password from the list
+func (h *Handler) DeletePDFPassword(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid password ID")
+	}
+
+	if err := h.passwords.Delete(ctx, id); err != nil {
+		slog.Error("failed to delete pdf password", "error", err)
+		return c.String(http.StatusInternalServerError, "Failed to delete password")
+	}
+
+	return c.String(http.StatusOK, "")
+}
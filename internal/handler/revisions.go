@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/bketelsen/docko/internal/document"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+)
+
+// ReplaceDocumentFile uploads a new revision of a document's file
+// POST /documents/:id/replace (HTMX, redirects to the detail page)
+// POST /api/documents/:id/revisions (JSON, returns the updated document)
+func (h *Handler) ReplaceDocumentFile(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	docID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid document ID")
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "no file provided")
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "failed to read uploaded file")
+	}
+	defer func() { _ = src.Close() }()
+
+	tmpFile, err := os.CreateTemp("", "revision-*"+filepath.Ext(file.Filename))
+	if err != nil {
+		slog.Error("failed to create temp file", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to process upload")
+	}
+	tmpPath := tmpFile.Name()
+	defer func() { _ = os.Remove(tmpPath) }()
+
+	if _, err := io.Copy(tmpFile, src); err != nil {
+		_ = tmpFile.Close()
+		slog.Error("failed to write temp file", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to process upload")
+	}
+	_ = tmpFile.Close()
+
+	doc, err := h.docSvc.Replace(ctx, docID, tmpPath, file.Filename)
+	if err != nil {
+		switch {
+		case errors.Is(err, document.ErrUnsupportedType):
+			return echo.NewHTTPError(http.StatusBadRequest, "only PDF, image, office and email files are allowed")
+		case errors.Is(err, document.ErrDuplicateRevision):
+			return echo.NewHTTPError(http.StatusConflict, "this file is already stored as a document")
+		case errors.Is(err, document.ErrTrashed):
+			return echo.NewHTTPError(http.StatusConflict, "restore the document from the trash before replacing its file")
+		case errors.Is(err, pgx.ErrNoRows):
+			return echo.NewHTTPError(http.StatusNotFound, "document not found")
+		}
+		slog.Error("failed to replace document file", "doc_id", docID, "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to replace document file")
+	}
+
+	if wantsJSON(c) {
+		return c.JSON(http.StatusOK, doc)
+	}
+	c.Response().Header().Set("HX-Redirect", "/documents/"+docID.String())
+	return c.NoContent(http.StatusOK)
+}
+
+// ViewRevision serves an earlier revision's PDF inline
+// GET /documents/:id/revisions/:rev/view
+func (h *Handler) ViewRevision(c echo.Context) error {
+	return h.serveRevision(c, true)
+}
+
+// DownloadRevision serves an earlier revision's original as attachment
+// GET /documents/:id/revisions/:rev/download
+func (h *Handler) DownloadRevision(c echo.Context) error {
+	return h.serveRevision(c, false)
+}
+
+// serveRevision looks up an archived revision and serves its PDF or original
+func (h *Handler) serveRevision(c echo.Context, inline bool) error {
+	ctx := c.Request().Context()
+
+	docID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid document ID")
+	}
+	revNum, err := strconv.Atoi(c.Param("rev"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid revision")
+	}
+
+	rev, err := h.docSvc.GetRevision(ctx, docID, int32(revNum))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "revision not found")
+	}
+
+	if inline {
+		filename := strings.TrimSuffix(rev.OriginalFilename, filepath.Ext(rev.OriginalFilename)) + ".pdf"
+		return h.serveStored(c, h.docSvc.RevisionPDFKey(rev), "inline", filename, "PDF file not found")
+	}
+
+	return h.serveStored(c, h.docSvc.RevisionOriginalKey(rev), "attachment", rev.OriginalFilename, "original file not found")
+}
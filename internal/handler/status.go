@@ -92,7 +92,7 @@ func (h *Handler) ProcessingStatus(c echo.Context) error {
 			)
 
 			// Send queue activity event if queue name is set and job completed/failed
-			if update.QueueName != "" && (update.Status == "completed" || update.Status == "failed" || update.Status == "ai_complete" || update.Status == "ai_failed") {
+			if update.QueueName != "" && (update.Status == "completed" || update.Status == "failed" || update.Status == "needs_password" || update.Status == "ai_complete" || update.Status == "ai_failed") {
 				// Fetch document name for activity row
 				docName := "Unknown"
 				if doc, err := h.db.Queries.GetDocument(ctx, update.DocumentID); err == nil {
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/bketelsen/docko/internal/document"
+	"github.com/bketelsen/docko/templates/pages/admin"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// TrashPage renders the trash listing
+// GET /trash
+func (h *Handler) TrashPage(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	docs, err := h.db.Queries.ListTrashedDocuments(ctx)
+	if err != nil {
+		slog.Error("failed to list trashed documents", "error", err)
+		return c.String(http.StatusInternalServerError, "Failed to load trash")
+	}
+
+	return admin.Trash(docs, h.cfg.Storage.TrashRetentionDays).Render(ctx, c.Response().Writer)
+}
+
+// TrashDocument moves a document to the trash and returns to the document list
+// DELETE /documents/:id
+func (h *Handler) TrashDocument(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	docID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid document ID")
+	}
+
+	if _, err := h.docSvc.Trash(ctx, docID); err != nil {
+		slog.Error("failed to trash document", "doc_id", docID, "error", err)
+		return c.String(http.StatusInternalServerError, "Failed to move document to trash")
+	}
+
+	c.Response().Header().Set("HX-Redirect", "/documents")
+	return c.NoContent(http.StatusOK)
+}
+
+// RestoreDocument takes a document out of the trash
+// POST /trash/:id/restore
+func (h *Handler) RestoreDocument(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	docID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid document ID")
+	}
+
+	if _, err := h.docSvc.Restore(ctx, docID); err != nil && !errors.Is(err, document.ErrNotTrashed) {
+		slog.Error("failed to restore document", "doc_id", docID, "error", err)
+		return c.String(http.StatusInternalServerError, "Failed to restore document")
+	}
+
+	// From the detail page, reload it; from the trash page, drop the row
+	if c.QueryParam("redirect") == "detail" {
+		c.Response().Header().Set("HX-Redirect", "/documents/"+docID.String())
+		return c.NoContent(http.StatusOK)
+	}
+	return c.String(http.StatusOK, "")
+}
+
+// PurgeDocument permanently deletes a trashed document and its files
+// DELETE /trash/:id
+func (h *Handler) PurgeDocument(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	docID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid document ID")
+	}
+
+	if err := h.docSvc.Purge(ctx, docID); err != nil {
+		if errors.Is(err, document.ErrNotTrashed) {
+			return c.String(http.StatusBadRequest, "Document is not in the trash")
+		}
+		slog.Error("failed to purge document", "doc_id", docID, "error", err)
+		return c.String(http.StatusInternalServerError, "Failed to delete document")
+	}
+
+	// Return empty response for HTMX to remove the row
+	return c.String(http.StatusOK, "")
+}
+
+// EmptyTrash permanently deletes every trashed document
+// POST /trash/empty
+func (h *Handler) EmptyTrash(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	purged, err := h.docSvc.EmptyTrash(ctx)
+	if err != nil {
+		slog.Error("failed to empty trash", "error", err)
+		return c.String(http.StatusInternalServerError, "Failed to empty trash")
+	}
+
+	slog.Info("trash emptied", "documents", purged)
+	c.Response().Header().Set("HX-Redirect", "/trash")
+	return c.String(http.StatusOK, fmt.Sprintf("Deleted %d documents", purged))
+}
@@ -2,18 +2,21 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"mime/multipart"
 	"net/http"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 
+	"github.com/bketelsen/docko/internal/document"
 	"github.com/bketelsen/docko/templates/pages/admin"
 
 	"github.com/google/uuid"
-	"github.com/h2non/filetype"
 	"github.com/labstack/echo/v4"
 )
 
@@ -76,6 +79,18 @@ func (h *Handler) UploadMultiple(c echo.Context) error {
 		}}, http.StatusBadRequest)
 	}
 
+	// Combining makes one document out of images of its pages
+	if combine, _ := strconv.ParseBool(c.FormValue("combine")); combine && len(files) > 1 {
+		result := h.processCombinedUpload(c, files)
+		status := http.StatusCreated
+		if !result.Success {
+			status = http.StatusBadRequest
+		} else if result.IsDuplicate {
+			status = http.StatusOK
+		}
+		return h.respondUploads(c, []UploadResult{result}, status)
+	}
+
 	results := make([]UploadResult, 0, len(files))
 	for _, file := range files {
 		result := h.processUpload(c, file)
@@ -107,59 +122,73 @@ func (h *Handler) UploadMultiple(c echo.Context) error {
 func (h *Handler) processUpload(c echo.Context, file *multipart.FileHeader) UploadResult {
 	ctx := c.Request().Context()
 
-	// Open the uploaded file
-	src, err := file.Open()
-	if err != nil {
-		slog.Error("failed to open uploaded file", "error", err, "filename", file.Filename)
+	tmpPath, result := saveUpload(file)
+	if tmpPath == "" {
+		return result
+	}
+	defer func() { _ = os.Remove(tmpPath) }()
+
+	// Validate file type using magic bytes
+	if !isSupportedFile(tmpPath) {
 		return UploadResult{
 			Success:  false,
 			Filename: file.Filename,
-			Error:    "Failed to read uploaded file",
+			Error:    "Only PDF, image, office and email files are allowed",
 		}
 	}
-	defer func() { _ = src.Close() }()
 
-	// Create temp file
-	tmpFile, err := os.CreateTemp("", "upload-*.pdf")
+	// Ingest the document
+	doc, isDuplicate, err := h.docSvc.Ingest(ctx, tmpPath, file.Filename)
 	if err != nil {
-		slog.Error("failed to create temp file", "error", err)
+		slog.Error("failed to ingest document", "error", err, "filename", file.Filename)
 		return UploadResult{
 			Success:  false,
 			Filename: file.Filename,
-			Error:    "Failed to process upload",
+			Error:    fmt.Sprintf("Failed to ingest document: %v", err),
 		}
 	}
-	tmpPath := tmpFile.Name()
-	defer func() { _ = os.Remove(tmpPath) }()
 
-	// Copy to temp file
-	if _, err := io.Copy(tmpFile, src); err != nil {
-		_ = tmpFile.Close()
-		slog.Error("failed to write temp file", "error", err)
-		return UploadResult{
-			Success:  false,
-			Filename: file.Filename,
-			Error:    "Failed to process upload",
+	return UploadResult{
+		Success:     true,
+		DocumentID:  doc.ID,
+		Filename:    file.Filename,
+		IsDuplicate: isDuplicate,
+	}
+}
+
+// processCombinedUpload ingests uploaded images as the pages of one
+// document, named after the first image
+func (h *Handler) processCombinedUpload(c echo.Context, files []*multipart.FileHeader) UploadResult {
+	ctx := c.Request().Context()
+	filename := strings.TrimSuffix(files[0].Filename, filepath.Ext(files[0].Filename)) + ".pdf"
+
+	paths := make([]string, 0, len(files))
+	defer func() {
+		for _, path := range paths {
+			_ = os.Remove(path)
+		}
+	}()
+	for _, file := range files {
+		tmpPath, result := saveUpload(file)
+		if tmpPath == "" {
+			return result
 		}
+		paths = append(paths, tmpPath)
 	}
-	_ = tmpFile.Close()
 
-	// Validate PDF using magic bytes
-	if !isPDF(tmpPath) {
+	doc, isDuplicate, err := h.docSvc.IngestImages(ctx, paths, filename)
+	if errors.Is(err, document.ErrUnsupportedType) {
 		return UploadResult{
 			Success:  false,
-			Filename: file.Filename,
-			Error:    "Only PDF files are allowed",
+			Filename: filename,
+			Error:    "Only images can be combined into one document",
 		}
 	}
-
-	// Ingest the document
-	doc, isDuplicate, err := h.docSvc.Ingest(ctx, tmpPath, file.Filename)
 	if err != nil {
-		slog.Error("failed to ingest document", "error", err, "filename", file.Filename)
+		slog.Error("failed to ingest combined images", "error", err, "filename", filename, "pages", len(files))
 		return UploadResult{
 			Success:  false,
-			Filename: file.Filename,
+			Filename: filename,
 			Error:    fmt.Sprintf("Failed to ingest document: %v", err),
 		}
 	}
@@ -167,32 +196,53 @@ func (h *Handler) processUpload(c echo.Context, file *multipart.FileHeader) Uplo
 	return UploadResult{
 		Success:     true,
 		DocumentID:  doc.ID,
-		Filename:    file.Filename,
+		Filename:    filename,
 		IsDuplicate: isDuplicate,
 	}
 }
 
-// isPDF checks if the file at the given path is a valid PDF using magic bytes
-func isPDF(path string) bool {
-	f, err := os.Open(path)
+// saveUpload copies an uploaded file to a temp file and returns its path
+// On failure the path is empty and the result describes the error.
+func saveUpload(file *multipart.FileHeader) (string, UploadResult) {
+	src, err := file.Open()
 	if err != nil {
-		return false
+		slog.Error("failed to open uploaded file", "error", err, "filename", file.Filename)
+		return "", UploadResult{
+			Success:  false,
+			Filename: file.Filename,
+			Error:    "Failed to read uploaded file",
+		}
 	}
-	defer func() { _ = f.Close() }()
+	defer func() { _ = src.Close() }()
 
-	// Read first 262 bytes for magic number detection
-	head := make([]byte, 262)
-	n, err := f.Read(head)
-	if err != nil && err != io.EOF {
-		return false
+	tmpFile, err := os.CreateTemp("", "upload-*"+filepath.Ext(file.Filename))
+	if err != nil {
+		slog.Error("failed to create temp file", "error", err)
+		return "", UploadResult{
+			Success:  false,
+			Filename: file.Filename,
+			Error:    "Failed to process upload",
+		}
 	}
 
-	kind, err := filetype.Match(head[:n])
-	if err != nil {
-		return false
+	if _, err := io.Copy(tmpFile, src); err != nil {
+		_ = tmpFile.Close()
+		_ = os.Remove(tmpFile.Name())
+		slog.Error("failed to write temp file", "error", err)
+		return "", UploadResult{
+			Success:  false,
+			Filename: file.Filename,
+			Error:    "Failed to process upload",
+		}
 	}
+	_ = tmpFile.Close()
+	return tmpFile.Name(), UploadResult{}
+}
 
-	return kind.MIME.Value == "application/pdf"
+// isSupportedFile checks if the file at the given path is an ingestible type using magic bytes
+func isSupportedFile(path string) bool {
+	contentType, err := document.DetectContentType(path)
+	return err == nil && contentType != ""
 }
 
 // respondUpload sends a single upload result
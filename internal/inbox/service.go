@@ -3,7 +3,7 @@ package inbox
 import (
 	"context"
 	"fmt"
-	"io"
+	"io/fs"
 	"log/slog"
 	"os"
 	"path/filepath"
@@ -12,7 +12,6 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/h2non/filetype"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 
@@ -44,20 +43,26 @@ type Service struct {
 	cfg       *config.Config
 	watcher   *Watcher
 	mu        sync.RWMutex
-	watching  map[uuid.UUID]string // inbox ID -> path
-	semaphore chan struct{}        // Limit concurrent ingestions
+	watching  map[uuid.UUID]watched // inbox ID -> watched directory
+	semaphore chan struct{}         // Limit concurrent ingestions
 	ctx       context.Context
 	cancel    context.CancelFunc
 	wg        sync.WaitGroup
 }
 
+// watched is the directory watched for an inbox
+type watched struct {
+	path      string
+	recursive bool
+}
+
 // New creates a new inbox Service.
 func New(db *database.DB, docSvc *document.Service, cfg *config.Config) *Service {
 	return &Service{
 		db:        db,
 		docSvc:    docSvc,
 		cfg:       cfg,
-		watching:  make(map[uuid.UUID]string),
+		watching:  make(map[uuid.UUID]watched),
 		semaphore: make(chan struct{}, DefaultMaxConcurrent),
 	}
 }
@@ -136,11 +141,25 @@ func (s *Service) AddInbox(inbox *sqlc.Inbox) error {
 		return fmt.Errorf("create error directory: %w", err)
 	}
 
-	if err := s.watcher.Add(inbox.Path); err != nil {
+	if err := s.watch(inbox); err != nil {
 		return fmt.Errorf("watch directory: %w", err)
 	}
+	return nil
+}
 
-	s.watching[inbox.ID] = inbox.Path
+// watch registers an inbox's directory with the watcher
+// Called with s.mu held.
+func (s *Service) watch(inbox *sqlc.Inbox) error {
+	var err error
+	if inbox.Recursive {
+		err = s.watcher.AddRecursive(inbox.Path, s.getErrorPath(inbox))
+	} else {
+		err = s.watcher.Add(inbox.Path)
+	}
+	if err != nil {
+		return err
+	}
+	s.watching[inbox.ID] = watched{path: filepath.Clean(inbox.Path), recursive: inbox.Recursive}
 	return nil
 }
 
@@ -149,12 +168,12 @@ func (s *Service) RemoveInbox(inboxID uuid.UUID) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	path, exists := s.watching[inboxID]
+	w, exists := s.watching[inboxID]
 	if !exists {
 		return nil // Not watching
 	}
 
-	if err := s.watcher.Remove(path); err != nil {
+	if err := s.watcher.Remove(w.path); err != nil {
 		return fmt.Errorf("stop watching: %w", err)
 	}
 
@@ -179,9 +198,9 @@ func (s *Service) RefreshInboxes(ctx context.Context) error {
 	defer s.mu.Unlock()
 
 	// Remove inboxes that are no longer enabled
-	for id, path := range s.watching {
+	for id, w := range s.watching {
 		if _, ok := enabled[id]; !ok {
-			if err := s.watcher.Remove(path); err != nil {
+			if err := s.watcher.Remove(w.path); err != nil {
 				slog.Warn("failed to remove inbox", "id", id, "error", err)
 			}
 			delete(s.watching, id)
@@ -204,11 +223,9 @@ func (s *Service) RefreshInboxes(ctx context.Context) error {
 			slog.Warn("failed to create error directory", "path", errorPath, "error", err)
 		}
 
-		if err := s.watcher.Add(inbox.Path); err != nil {
+		if err := s.watch(&inbox); err != nil {
 			slog.Warn("failed to watch inbox", "path", inbox.Path, "error", err)
-			continue
 		}
-		s.watching[inbox.ID] = inbox.Path
 	}
 
 	return nil
@@ -260,23 +277,46 @@ func (s *Service) scanAllInboxes(ctx context.Context) error {
 	return nil
 }
 
-// scanDirectory processes all PDF files in an inbox directory.
+// scanDirectory processes all supported files in an inbox directory.
+// Recursive inboxes are walked, skipping hidden directories, the error
+// directory and directories watched as inboxes of their own.
 func (s *Service) scanDirectory(ctx context.Context, inbox *sqlc.Inbox) error {
-	entries, err := os.ReadDir(inbox.Path)
-	if err != nil {
-		return fmt.Errorf("read directory: %w", err)
+	root := filepath.Clean(inbox.Path)
+	errorPath := filepath.Clean(s.getErrorPath(inbox))
+
+	s.mu.RLock()
+	others := make(map[string]bool)
+	for id, w := range s.watching {
+		if id != inbox.ID {
+			others[w.path] = true
+		}
 	}
+	s.mu.RUnlock()
 
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+			slog.Warn("failed to read inbox subdirectory", "path", path, "error", err)
+			return nil
 		}
-		if !isPDFFilename(entry.Name()) {
-			continue
+		if d.IsDir() {
+			if path == root {
+				return nil
+			}
+			if !inbox.Recursive || path == errorPath || others[path] || strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
 		}
-
-		path := filepath.Join(inbox.Path, entry.Name())
-		s.processFile(ctx, inbox, path)
+		if isSupportedFilename(d.Name()) {
+			s.processFile(ctx, inbox, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("read directory: %w", err)
 	}
 
 	// Update last scan time
@@ -307,21 +347,34 @@ func (s *Service) processFile(ctx context.Context, inbox *sqlc.Inbox, path strin
 
 	slog.Debug("processing file", "path", path, "inbox", inbox.Name)
 
-	// Validate PDF using magic bytes
-	isPDF, err := s.validatePDF(path)
+	// Validate file type using magic bytes
+	contentType, err := document.DetectContentType(path)
 	if err != nil {
 		slog.Warn("failed to validate file", "path", path, "error", err)
 		s.handleError(ctx, inbox, path, filename, fmt.Sprintf("validation failed: %v", err))
 		return
 	}
-	if !isPDF {
-		slog.Info("file is not a valid PDF", "path", path)
-		s.handleError(ctx, inbox, path, filename, "not a valid PDF file")
+	if contentType == "" {
+		slog.Info("file is not a supported document", "path", path)
+		s.handleError(ctx, inbox, path, filename, "not a supported document file")
 		return
 	}
 
-	// Ingest document
-	doc, isDupe, err := s.docSvc.Ingest(ctx, path, filename)
+	// Ingest document with the inbox's default metadata
+	opts := document.IngestOptions{
+		Source: &document.IngestSource{
+			Type:              document.SourceInbox,
+			ID:                inbox.ID,
+			Name:              inbox.Name,
+			TagNames:          inbox.DefaultTags,
+			CorrespondentName: deref(inbox.DefaultCorrespondent),
+			DocumentTypeName:  deref(inbox.DefaultDocumentType),
+		},
+	}
+	if inbox.FolderTags {
+		opts.TagNames = document.FolderTags(inbox.Path, path)
+	}
+	doc, isDupe, err := s.docSvc.IngestWithOptions(ctx, path, filename, opts)
 	if err != nil {
 		slog.Error("failed to ingest document", "path", path, "error", err)
 		s.handleError(ctx, inbox, path, filename, fmt.Sprintf("ingestion failed: %v", err))
@@ -336,24 +389,6 @@ func (s *Service) processFile(ctx context.Context, inbox *sqlc.Inbox, path strin
 	}
 }
 
-// validatePDF checks if a file is a valid PDF using magic bytes.
-func (s *Service) validatePDF(path string) (bool, error) {
-	f, err := os.Open(path)
-	if err != nil {
-		return false, err
-	}
-	defer func() { _ = f.Close() }()
-
-	// Read first 262 bytes for magic byte detection
-	buf := make([]byte, 262)
-	n, err := f.Read(buf)
-	if err != nil && err != io.EOF {
-		return false, err
-	}
-
-	return filetype.Is(buf[:n], "pdf"), nil
-}
-
 // handleSuccess processes a successfully ingested file.
 func (s *Service) handleSuccess(ctx context.Context, inbox *sqlc.Inbox, path, filename string, docID uuid.UUID, duration time.Duration) {
 	// Delete the source file
@@ -449,17 +484,20 @@ func (s *Service) generateUniqueFilename(path string) string {
 }
 
 // findInboxForPath looks up the inbox that contains the given file path.
+// When inboxes are nested, the innermost one watching the file's directory wins.
 func (s *Service) findInboxForPath(path string) (*sqlc.Inbox, error) {
 	dir := filepath.Dir(path)
 
 	s.mu.RLock()
 	var inboxID uuid.UUID
 	found := false
-	for id, inboxPath := range s.watching {
-		if inboxPath == dir {
-			inboxID = id
-			found = true
-			break
+	best := ""
+	for id, w := range s.watching {
+		if w.path != dir && !(w.recursive && isWithin(w.path, dir)) {
+			continue
+		}
+		if len(w.path) > len(best) {
+			inboxID, best, found = id, w.path, true
 		}
 	}
 	s.mu.RUnlock()
@@ -506,3 +544,11 @@ func (s *Service) updateInboxStatus(ctx context.Context, inboxID uuid.UUID, errM
 		slog.Warn("failed to update inbox status", "error", err)
 	}
 }
+
+// deref returns the string s points to, or "" if s is nil
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -2,13 +2,17 @@ package inbox
 
 import (
 	"context"
+	"io/fs"
 	"log/slog"
+	"os"
 	"path/filepath"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
+
+	"github.com/bketelsen/docko/internal/document"
 )
 
 // debouncer handles debouncing file events to avoid processing the same file
@@ -67,14 +71,39 @@ func (d *debouncer) CancelAll() {
 	}
 }
 
-// Watcher watches directories for new PDF files using fsnotify.
+// watchRoot is a directory added to the Watcher, with the directories
+// watched on its behalf
+type watchRoot struct {
+	recursive bool
+	exclude   []string            // Directories not watched, with their contents
+	dirs      map[string]struct{} // The root and, if recursive, its subdirectories
+}
+
+// covers reports whether a directory belongs to the root's watch
+func (r *watchRoot) covers(root, dir string) bool {
+	if dir == root {
+		return true
+	}
+	if !r.recursive || !isWithin(root, dir) || strings.HasPrefix(filepath.Base(dir), ".") {
+		return false
+	}
+	for _, ex := range r.exclude {
+		if dir == ex || isWithin(ex, dir) {
+			return false
+		}
+	}
+	return true
+}
+
+// Watcher watches directories for new document files using fsnotify.
 type Watcher struct {
 	watcher   *fsnotify.Watcher
 	debouncer *debouncer
 	handler   func(path string) // Called for each stable file
 	delay     time.Duration     // Debounce delay (default 500ms)
 	mu        sync.RWMutex
-	watching  map[string]struct{} // Tracked directories
+	roots     map[string]*watchRoot // Added directories
+	watching  map[string]int        // Watched directories, by number of roots covering them
 }
 
 // NewWatcher creates a new file watcher with the given debounce delay and handler.
@@ -94,46 +123,134 @@ func NewWatcher(delay time.Duration, handler func(string)) (*Watcher, error) {
 		debouncer: newDebouncer(),
 		handler:   handler,
 		delay:     delay,
-		watching:  make(map[string]struct{}),
+		roots:     make(map[string]*watchRoot),
+		watching:  make(map[string]int),
 	}, nil
 }
 
 // Add starts watching the given directory for file changes.
 func (w *Watcher) Add(path string) error {
+	return w.add(path, &watchRoot{})
+}
+
+// AddRecursive starts watching the given directory and every directory below
+// it, including ones created later. Hidden directories and the excluded
+// directories are skipped with their contents.
+func (w *Watcher) AddRecursive(path string, exclude ...string) error {
+	return w.add(path, &watchRoot{recursive: true, exclude: exclude})
+}
+
+func (w *Watcher) add(path string, root *watchRoot) error {
+	path = filepath.Clean(path)
+	for i, ex := range root.exclude {
+		root.exclude[i] = filepath.Clean(ex)
+	}
+
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
-	if _, exists := w.watching[path]; exists {
+	if _, exists := w.roots[path]; exists {
 		return nil // Already watching
 	}
 
-	if err := w.watcher.Add(path); err != nil {
+	root.dirs = make(map[string]struct{})
+	if err := w.watchDir(root, path); err != nil {
 		return err
 	}
+	w.roots[path] = root
+	if root.recursive {
+		w.watchTree(path, root, path)
+	}
 
-	w.watching[path] = struct{}{}
-	slog.Info("watching directory", "path", path)
+	slog.Info("watching directory", "path", path, "recursive", root.recursive)
 	return nil
 }
 
-// Remove stops watching the given directory.
+// Remove stops watching the given directory, and its subdirectories if it
+// was added recursively.
 func (w *Watcher) Remove(path string) error {
+	path = filepath.Clean(path)
+
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
-	if _, exists := w.watching[path]; !exists {
+	root, exists := w.roots[path]
+	if !exists {
 		return nil // Not watching
 	}
 
-	if err := w.watcher.Remove(path); err != nil {
-		return err
+	var firstErr error
+	for dir := range root.dirs {
+		if err := w.unwatchDir(root, dir); err != nil && dir == path {
+			firstErr = err
+		}
 	}
-
-	delete(w.watching, path)
+	delete(w.roots, path)
 	slog.Info("stopped watching directory", "path", path)
+	return firstErr
+}
+
+// watchDir watches dir on behalf of root
+func (w *Watcher) watchDir(root *watchRoot, dir string) error {
+	if _, ok := root.dirs[dir]; ok {
+		return nil
+	}
+	if w.watching[dir] == 0 {
+		if err := w.watcher.Add(dir); err != nil {
+			return err
+		}
+	}
+	w.watching[dir]++
+	root.dirs[dir] = struct{}{}
 	return nil
 }
 
+// unwatchDir stops watching dir on behalf of root, releasing the fsnotify
+// watch once no other root covers it
+func (w *Watcher) unwatchDir(root *watchRoot, dir string) error {
+	if _, ok := root.dirs[dir]; !ok {
+		return nil
+	}
+	delete(root.dirs, dir)
+	if w.watching[dir]--; w.watching[dir] > 0 {
+		return nil
+	}
+	delete(w.watching, dir)
+	err := w.watcher.Remove(dir)
+	if _, statErr := os.Stat(dir); statErr != nil {
+		return nil // Gone; fsnotify has already dropped the watch
+	}
+	return err
+}
+
+// watchTree watches the directories below dir that root covers and returns
+// the supported files found in them
+// Called with w.mu held.
+func (w *Watcher) watchTree(rootPath string, root *watchRoot, dir string) []string {
+	var files []string
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			slog.Warn("failed to read directory", "path", path, "error", err)
+			return nil
+		}
+		if !d.IsDir() {
+			if d.Type().IsRegular() && isSupportedFilename(path) {
+				files = append(files, path)
+			}
+			return nil
+		}
+		if !root.covers(rootPath, path) {
+			return filepath.SkipDir
+		}
+		if err := w.watchDir(root, path); err != nil {
+			slog.Warn("failed to watch directory", "path", path, "error", err)
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	return files
+}
+
 // Run starts the event loop and blocks until context is cancelled.
 // It handles Create and Write events, debouncing them before calling the handler.
 func (w *Watcher) Run(ctx context.Context) error {
@@ -159,33 +276,102 @@ func (w *Watcher) Run(ctx context.Context) error {
 
 // handleEvent processes a single fsnotify event.
 func (w *Watcher) handleEvent(event fsnotify.Event) {
+	// Keep recursive watches in step with directories coming and going
+	if event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename) {
+		w.dirGone(event.Name)
+		return
+	}
+
 	// Only process Create and Write events
 	if !event.Has(fsnotify.Create) && !event.Has(fsnotify.Write) {
 		return
 	}
 
-	// Only process PDF files
-	if !isPDFFilename(event.Name) {
+	if event.Has(fsnotify.Create) {
+		if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+			// Files moved in with the directory raise no events of their own
+			for _, path := range w.dirCreated(event.Name) {
+				w.debounce(path)
+			}
+			return
+		}
+	}
+
+	// Only process files we can ingest
+	if !isSupportedFilename(event.Name) {
 		return
 	}
 
-	path := event.Name
-	slog.Debug("file event", "op", event.Op.String(), "path", path)
+	slog.Debug("file event", "op", event.Op.String(), "path", event.Name)
+	w.debounce(event.Name)
+}
 
-	// Debounce the event - handler will be called after no events for delay duration
+// debounce calls the handler for path once no events have arrived for the delay
+func (w *Watcher) debounce(path string) {
 	w.debouncer.Debounce(path, w.delay, func() {
 		slog.Debug("file stable, processing", "path", path)
 		w.handler(path)
 	})
 }
 
+// dirCreated watches a new directory under the recursive roots covering it
+// and returns the supported files already in it
+func (w *Watcher) dirCreated(dir string) []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	seen := make(map[string]bool)
+	var files []string
+	for rootPath, root := range w.roots {
+		if _, parentWatched := root.dirs[filepath.Dir(dir)]; !parentWatched || !root.covers(rootPath, dir) {
+			continue
+		}
+		slog.Debug("watching new directory", "path", dir, "root", rootPath)
+		for _, path := range w.watchTree(rootPath, root, dir) {
+			if !seen[path] {
+				seen[path] = true
+				files = append(files, path)
+			}
+		}
+	}
+	return files
+}
+
+// dirGone stops watching a removed or renamed subdirectory and those below it
+// A renamed directory is watched again under its new name when its Create
+// event arrives.
+func (w *Watcher) dirGone(dir string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.watching[dir] == 0 {
+		return
+	}
+	for rootPath, root := range w.roots {
+		if dir == rootPath {
+			continue // Roots are removed explicitly
+		}
+		for sub := range root.dirs {
+			if sub == dir || isWithin(dir, sub) {
+				_ = w.unwatchDir(root, sub)
+			}
+		}
+	}
+}
+
 // Close stops watching all directories and releases resources.
 func (w *Watcher) Close() error {
 	w.debouncer.CancelAll()
 	return w.watcher.Close()
 }
 
-// isPDFFilename checks if a filename has a .pdf extension (case-insensitive).
-func isPDFFilename(path string) bool {
-	return strings.EqualFold(filepath.Ext(path), ".pdf")
+// isWithin reports whether path is below dir
+func isWithin(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// isSupportedFilename checks if a filename has an ingestible extension (case-insensitive).
+func isSupportedFilename(path string) bool {
+	return document.IsSupportedFilename(path)
 }
@@ -0,0 +1,164 @@
+package inbox
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// collect runs a watcher on root and returns a function that waits for the
+// files handled so far to settle and returns them
+func collect(t *testing.T, add func(w *Watcher) error) func() []string {
+	t.Helper()
+
+	var mu sync.Mutex
+	var handled []string
+	w, err := NewWatcher(20*time.Millisecond, func(path string) {
+		mu.Lock()
+		handled = append(handled, path)
+		mu.Unlock()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := add(w); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		_ = w.Run(ctx)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+		_ = w.Close()
+	})
+
+	return func() []string {
+		time.Sleep(300 * time.Millisecond)
+		mu.Lock()
+		defer mu.Unlock()
+		got := slices.Clone(handled)
+		slices.Sort(got)
+		return got
+	}
+}
+
+func writeFile(t *testing.T, path string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("%PDF-1.4"), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatcherRecursive(t *testing.T) {
+	root := t.TempDir()
+	errorDir := filepath.Join(root, "errors")
+	for _, dir := range []string{errorDir, filepath.Join(root, "medical")} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	handled := collect(t, func(w *Watcher) error { return w.AddRecursive(root, errorDir) })
+
+	// Existing subdirectory, a new one, and a tree moved in whole
+	writeFile(t, filepath.Join(root, "top.pdf"))
+	writeFile(t, filepath.Join(root, "medical", "a.pdf"))
+	if err := os.Mkdir(filepath.Join(root, "taxes"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond) // Let the new directory be watched
+	writeFile(t, filepath.Join(root, "taxes", "b.pdf"))
+	staged := filepath.Join(t.TempDir(), "2024")
+	writeFile(t, filepath.Join(staged, "q1", "c.pdf"))
+	if err := os.Rename(staged, filepath.Join(root, "2024")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Ignored: the error directory, hidden directories, unsupported files
+	writeFile(t, filepath.Join(errorDir, "failed.pdf"))
+	writeFile(t, filepath.Join(root, ".sync", "d.pdf"))
+	writeFile(t, filepath.Join(root, "medical", "notes.txt"))
+
+	want := []string{
+		filepath.Join(root, "2024", "q1", "c.pdf"),
+		filepath.Join(root, "medical", "a.pdf"),
+		filepath.Join(root, "taxes", "b.pdf"),
+		filepath.Join(root, "top.pdf"),
+	}
+	if got := handled(); !slices.Equal(got, want) {
+		t.Errorf("handled %q, want %q", got, want)
+	}
+}
+
+func TestWatcherNotRecursive(t *testing.T) {
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, "medical"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	handled := collect(t, func(w *Watcher) error { return w.Add(root) })
+
+	writeFile(t, filepath.Join(root, "top.pdf"))
+	writeFile(t, filepath.Join(root, "medical", "a.pdf"))
+
+	want := []string{filepath.Join(root, "top.pdf")}
+	if got := handled(); !slices.Equal(got, want) {
+		t.Errorf("handled %q, want %q", got, want)
+	}
+}
+
+func TestWatcherRemoveRecursive(t *testing.T) {
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, "medical"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	var w *Watcher
+	handled := collect(t, func(watcher *Watcher) error {
+		w = watcher
+		return w.AddRecursive(root)
+	})
+	if err := w.Remove(root); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	if len(w.watching) != 0 {
+		t.Errorf("still watching %v after Remove", w.watching)
+	}
+
+	writeFile(t, filepath.Join(root, "medical", "a.pdf"))
+	if got := handled(); len(got) != 0 {
+		t.Errorf("handled %q after Remove", got)
+	}
+}
+
+func TestIsWithin(t *testing.T) {
+	tests := []struct {
+		dir, path string
+		want      bool
+	}{
+		{"/inbox", "/inbox/medical", true},
+		{"/inbox", "/inbox/medical/2024", true},
+		{"/inbox", "/inbox", false},
+		{"/inbox", "/inbox-old/medical", false},
+		{"/inbox", "/other", false},
+		{"/inbox", "/inbox/..data", true},
+	}
+
+	for _, tt := range tests {
+		if got := isWithin(filepath.FromSlash(tt.dir), filepath.FromSlash(tt.path)); got != tt.want {
+			t.Errorf("isWithin(%q, %q) = %v, want %v", tt.dir, tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,399 @@
+package network
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-imap"
+	"github.com/emersion/go-imap/client"
+
+	"github.com/bketelsen/docko/internal/email"
+)
+
+const (
+	imapsPort          = "993"
+	imapConnectTimeout = 30 * time.Second
+	imapCommandTimeout = 2 * time.Minute
+)
+
+// IMAPSource implements NetworkSource for an IMAP mailbox folder.
+// Each PDF attachment of an unseen message is listed as a file with the path
+// "{uid}/{index}/{filename}". Post-import actions apply to the whole message
+// once every listed attachment of it has been handled.
+type IMAPSource struct {
+	host     string
+	mailbox  string
+	username string
+	password string
+
+	// tlsConfig is the base TLS configuration; nil verifies against the
+	// system roots
+	tlsConfig *tls.Config
+
+	// Connection state (opened on first use, closed by Close)
+	client *client.Client
+
+	// pending counts listed attachments per message not yet handled
+	pending map[uint32]int
+
+	// cached holds the last fetched message, since attachments of one
+	// message are read one after another
+	cachedUID uint32
+	cached    *email.Message
+}
+
+// NewIMAPSource creates a new IMAP source.
+// Host may include a port; port 993 (the default) uses implicit TLS,
+// other ports must upgrade with STARTTLS. Servers offering neither are
+// refused, so the password is never sent in cleartext.
+// Password should already be decrypted before passing here.
+func NewIMAPSource(host, mailbox, username, password string) *IMAPSource {
+	if mailbox == "" {
+		mailbox = "INBOX"
+	}
+	return &IMAPSource{
+		host:     host,
+		mailbox:  mailbox,
+		username: username,
+		password: password,
+		pending:  make(map[uint32]int),
+	}
+}
+
+// connect logs in and selects the mailbox, reusing an open connection.
+func (s *IMAPSource) connect(ctx context.Context) error {
+	if s.client != nil {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	addr := s.host
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, imapsPort)
+	}
+	serverName, port, _ := net.SplitHostPort(addr)
+
+	tlsConfig := &tls.Config{}
+	if s.tlsConfig != nil {
+		tlsConfig = s.tlsConfig.Clone()
+	}
+	tlsConfig.ServerName = serverName
+
+	dialer := &net.Dialer{Timeout: imapConnectTimeout}
+	var c *client.Client
+	var err error
+	if port == imapsPort {
+		c, err = client.DialWithDialerTLS(dialer, addr, tlsConfig)
+	} else {
+		c, err = client.DialWithDialer(dialer, addr)
+	}
+	if err != nil {
+		return fmt.Errorf("dial: %w", err)
+	}
+	c.Timeout = imapCommandTimeout
+
+	if !c.IsTLS() {
+		if ok, _ := c.SupportStartTLS(); ok {
+			if err := c.StartTLS(tlsConfig); err != nil {
+				_ = c.Logout()
+				return fmt.Errorf("starttls: %w", err)
+			}
+		}
+	}
+	if !c.IsTLS() {
+		_ = c.Logout()
+		return errors.New("server offers neither TLS nor STARTTLS, refusing to send the password in cleartext")
+	}
+
+	if err := c.Login(s.username, s.password); err != nil {
+		_ = c.Logout()
+		return fmt.Errorf("login: %w", err)
+	}
+
+	if _, err := c.Select(s.mailbox, false); err != nil {
+		_ = c.Logout()
+		return fmt.Errorf("select %s: %w", s.mailbox, err)
+	}
+
+	s.client = c
+	return nil
+}
+
+// disconnect logs out and drops the connection.
+func (s *IMAPSource) disconnect() {
+	if s.client != nil {
+		_ = s.client.Logout()
+		s.client = nil
+	}
+	s.cached = nil
+	s.cachedUID = 0
+}
+
+// Test validates the IMAP login and mailbox.
+func (s *IMAPSource) Test(ctx context.Context) error {
+	if err := s.connect(ctx); err != nil {
+		return err
+	}
+	defer s.disconnect()
+	return nil
+}
+
+// ListFiles returns the PDF attachments of all unseen messages in the mailbox.
+func (s *IMAPSource) ListFiles(ctx context.Context) ([]RemoteFile, error) {
+	if err := s.connect(ctx); err != nil {
+		return nil, err
+	}
+
+	criteria := imap.NewSearchCriteria()
+	criteria.WithoutFlags = []string{imap.SeenFlag, imap.DeletedFlag}
+	uids, err := s.client.UidSearch(criteria)
+	if err != nil {
+		return nil, fmt.Errorf("search: %w", err)
+	}
+	if len(uids) == 0 {
+		return nil, nil
+	}
+
+	seqset := new(imap.SeqSet)
+	seqset.AddNum(uids...)
+
+	items := []imap.FetchItem{imap.FetchUid, imap.FetchInternalDate, imap.FetchBodyStructure}
+	messages := make(chan *imap.Message, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- s.client.UidFetch(seqset, items, messages)
+	}()
+
+	var files []RemoteFile
+	s.pending = make(map[uint32]int)
+	for msg := range messages {
+		if msg.BodyStructure == nil {
+			continue
+		}
+		for i, att := range pdfParts(msg.BodyStructure) {
+			name := attachmentName(att, i)
+			files = append(files, RemoteFile{
+				Path:    fmt.Sprintf("%d/%d/%s", msg.Uid, i, name),
+				Name:    name,
+				Size:    int64(att.Size),
+				ModTime: msg.InternalDate,
+			})
+			s.pending[msg.Uid]++
+		}
+	}
+	if err := <-done; err != nil {
+		return nil, fmt.Errorf("fetch: %w", err)
+	}
+	return files, nil
+}
+
+// ReadFile copies an attachment's decoded content to the provided writer.
+// The message is fetched with BODY.PEEK so reading doesn't mark it seen.
+func (s *IMAPSource) ReadFile(ctx context.Context, remotePath string, w io.Writer) error {
+	uid, index, err := parseIMAPPath(remotePath)
+	if err != nil {
+		return err
+	}
+	if err := s.connect(ctx); err != nil {
+		return err
+	}
+
+	msg, err := s.fetchMessage(uid)
+	if err != nil {
+		return err
+	}
+
+	var pdfs []email.Attachment
+	for _, att := range msg.Attachments {
+		if att.IsPDF() {
+			pdfs = append(pdfs, att)
+		}
+	}
+	if index >= len(pdfs) {
+		return fmt.Errorf("attachment %d not found in message %d", index, uid)
+	}
+
+	if _, err := io.Copy(w, bytes.NewReader(pdfs[index].Data)); err != nil {
+		return fmt.Errorf("copy: %w", err)
+	}
+	return nil
+}
+
+// DeleteFile deletes the attachment's message once all its attachments are handled.
+func (s *IMAPSource) DeleteFile(ctx context.Context, remotePath string) error {
+	uid, ready, err := s.handled(remotePath)
+	if err != nil || !ready {
+		return err
+	}
+	if err := s.connect(ctx); err != nil {
+		return err
+	}
+
+	seqset := new(imap.SeqSet)
+	seqset.AddNum(uid)
+	item := imap.FormatFlagsOp(imap.AddFlags, true)
+	if err := s.client.UidStore(seqset, item, []interface{}{imap.DeletedFlag}, nil); err != nil {
+		return fmt.Errorf("flag message %d deleted: %w", uid, err)
+	}
+	if err := s.client.Expunge(nil); err != nil {
+		return fmt.Errorf("expunge: %w", err)
+	}
+	return nil
+}
+
+// MoveFile moves the attachment's message to a folder once all its attachments are handled.
+// The folder is destPath's directory relative to the attachment's directory,
+// matching the subfolder the service appends, and is created if missing.
+func (s *IMAPSource) MoveFile(ctx context.Context, remotePath, destPath string) error {
+	uid, ready, err := s.handled(remotePath)
+	if err != nil || !ready {
+		return err
+	}
+	if err := s.connect(ctx); err != nil {
+		return err
+	}
+
+	folder, err := filepath.Rel(filepath.Dir(remotePath), filepath.Dir(destPath))
+	if err != nil || folder == "." || strings.HasPrefix(folder, "..") {
+		return fmt.Errorf("invalid destination %s", destPath)
+	}
+	folder = filepath.ToSlash(folder)
+
+	// CREATE fails if the folder exists, which is the common case
+	_ = s.client.Create(folder)
+
+	seqset := new(imap.SeqSet)
+	seqset.AddNum(uid)
+	if err := s.client.UidMove(seqset, folder); err != nil {
+		return fmt.Errorf("move message %d to %s: %w", uid, folder, err)
+	}
+	return nil
+}
+
+// MarkSeen flags the attachment's message as seen once all its attachments are handled,
+// so later syncs skip it.
+func (s *IMAPSource) MarkSeen(ctx context.Context, remotePath string) error {
+	uid, ready, err := s.handled(remotePath)
+	if err != nil || !ready {
+		return err
+	}
+	if err := s.connect(ctx); err != nil {
+		return err
+	}
+
+	seqset := new(imap.SeqSet)
+	seqset.AddNum(uid)
+	item := imap.FormatFlagsOp(imap.AddFlags, true)
+	if err := s.client.UidStore(seqset, item, []interface{}{imap.SeenFlag}, nil); err != nil {
+		return fmt.Errorf("flag message %d seen: %w", uid, err)
+	}
+	return nil
+}
+
+// Close logs out of the server.
+func (s *IMAPSource) Close() error {
+	s.disconnect()
+	return nil
+}
+
+// handled records that an attachment was handled and reports whether it was
+// the last pending one of its message.
+func (s *IMAPSource) handled(remotePath string) (uint32, bool, error) {
+	uid, _, err := parseIMAPPath(remotePath)
+	if err != nil {
+		return 0, false, err
+	}
+	if s.pending[uid] > 1 {
+		s.pending[uid]--
+		return uid, false, nil
+	}
+	delete(s.pending, uid)
+	return uid, true, nil
+}
+
+// fetchMessage downloads and parses a message, caching the last one.
+func (s *IMAPSource) fetchMessage(uid uint32) (*email.Message, error) {
+	if s.cached != nil && s.cachedUID == uid {
+		return s.cached, nil
+	}
+
+	seqset := new(imap.SeqSet)
+	seqset.AddNum(uid)
+	section := &imap.BodySectionName{Peek: true}
+
+	messages := make(chan *imap.Message, 1)
+	if err := s.client.UidFetch(seqset, []imap.FetchItem{section.FetchItem()}, messages); err != nil {
+		return nil, fmt.Errorf("fetch message %d: %w", uid, err)
+	}
+	fetched := <-messages
+	if fetched == nil {
+		return nil, fmt.Errorf("message %d not found", uid)
+	}
+	body := fetched.GetBody(section)
+	if body == nil {
+		return nil, fmt.Errorf("message %d has no body", uid)
+	}
+
+	msg, err := email.Parse(body)
+	if err != nil {
+		return nil, fmt.Errorf("parse message %d: %w", uid, err)
+	}
+	s.cached, s.cachedUID = msg, uid
+	return msg, nil
+}
+
+// pdfParts returns a message's PDF attachment parts in the order email.Parse finds them.
+func pdfParts(bs *imap.BodyStructure) []*imap.BodyStructure {
+	var parts []*imap.BodyStructure
+	bs.Walk(func(path []int, part *imap.BodyStructure) bool {
+		if strings.EqualFold(part.MIMEType, "multipart") {
+			return true
+		}
+		filename, _ := part.Filename()
+		isPDF := strings.EqualFold(part.MIMEType+"/"+part.MIMESubType, "application/pdf") ||
+			strings.EqualFold(filepath.Ext(filename), ".pdf")
+		if isPDF {
+			parts = append(parts, part)
+		}
+		return false
+	})
+	return parts
+}
+
+// attachmentName returns a safe filename for an attachment part.
+func attachmentName(part *imap.BodyStructure, index int) string {
+	filename, _ := part.Filename()
+	filename = filepath.Base(strings.ReplaceAll(filename, "\\", "/"))
+	if filename == "" || filename == "." || filename == "/" {
+		filename = fmt.Sprintf("attachment-%d.pdf", index+1)
+	}
+	return filename
+}
+
+// parseIMAPPath splits a "{uid}/{index}/{filename}" path.
+func parseIMAPPath(remotePath string) (uint32, int, error) {
+	parts := strings.SplitN(remotePath, "/", 3)
+	if len(parts) < 2 {
+		return 0, 0, fmt.Errorf("invalid imap path %q", remotePath)
+	}
+	uid, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid imap path %q: %w", remotePath, err)
+	}
+	index, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid imap path %q: %w", remotePath, err)
+	}
+	return uint32(uid), index, nil
+}
@@ -0,0 +1,286 @@
+package network
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/emersion/go-imap"
+	"github.com/emersion/go-imap/backend"
+	"github.com/emersion/go-imap/backend/memory"
+	"github.com/emersion/go-imap/server"
+)
+
+// pdfAttachmentMessage is a message with a text body and two PDF attachments
+const pdfAttachmentMessage = "From: Billing <billing@example.com>\r\n" +
+	"To: me@example.com\r\n" +
+	"Subject: Your invoices\r\n" +
+	"Date: Mon, 02 Mar 2026 10:00:00 +0000\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/mixed; boundary=\"b1\"\r\n" +
+	"\r\n" +
+	"--b1\r\n" +
+	"Content-Type: text/plain\r\n" +
+	"\r\n" +
+	"Invoices attached.\r\n" +
+	"--b1\r\n" +
+	"Content-Type: application/pdf; name=\"march.pdf\"\r\n" +
+	"Content-Disposition: attachment; filename=\"march.pdf\"\r\n" +
+	"Content-Transfer-Encoding: base64\r\n" +
+	"\r\n" +
+	"JVBERi0xLjQKbWFyY2g=\r\n" +
+	"--b1\r\n" +
+	"Content-Type: application/octet-stream; name=\"april.pdf\"\r\n" +
+	"Content-Disposition: attachment; filename=\"april.pdf\"\r\n" +
+	"Content-Transfer-Encoding: base64\r\n" +
+	"\r\n" +
+	"JVBERi0xLjQKYXByaWw=\r\n" +
+	"--b1--\r\n"
+
+// startIMAPServer runs an in-process IMAP server offering STARTTLS with the
+// messages appended unseen to INBOX and returns a source logged in to it.
+func startIMAPServer(t *testing.T, messages ...string) *IMAPSource {
+	t.Helper()
+
+	cert, roots := selfSignedCert(t)
+	addr := serveIMAP(t, &tls.Config{Certificates: []tls.Certificate{cert}}, messages...)
+
+	src := NewIMAPSource(addr, "INBOX", "username", "password")
+	src.tlsConfig = &tls.Config{RootCAs: roots}
+	return src
+}
+
+// selfSignedCert returns a certificate for 127.0.0.1 and a pool trusting it
+func selfSignedCert(t *testing.T) (tls.Certificate, *x509.CertPool) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("parse certificate: %v", err)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(leaf)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}, roots
+}
+
+// serveIMAP runs an in-process IMAP server with the messages appended
+// unseen to INBOX and returns its address. A nil tlsConfig serves plaintext
+// only, without STARTTLS.
+func serveIMAP(t *testing.T, tlsConfig *tls.Config, messages ...string) string {
+	t.Helper()
+
+	be := memory.New()
+	user, err := be.Login(nil, "username", "password")
+	if err != nil {
+		t.Fatalf("login to memory backend: %v", err)
+	}
+	inbox, err := user.GetMailbox("INBOX")
+	if err != nil {
+		t.Fatalf("get INBOX: %v", err)
+	}
+	for _, msg := range messages {
+		if err := inbox.CreateMessage(nil, time.Now(), bytes.NewBufferString(msg)); err != nil {
+			t.Fatalf("append message: %v", err)
+		}
+	}
+
+	srv := server.New(moveBackend{be})
+	srv.TLSConfig = tlsConfig
+	srv.AllowInsecureAuth = tlsConfig == nil
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	go func() { _ = srv.Serve(l) }()
+	t.Cleanup(func() { _ = srv.Close() })
+
+	return l.Addr().String()
+}
+
+// moveBackend adds MOVE support to the memory backend, which the server
+// advertises but the memory mailboxes don't implement
+type moveBackend struct{ *memory.Backend }
+
+type moveUser struct{ backend.User }
+
+type moveMailbox struct{ backend.Mailbox }
+
+func (b moveBackend) Login(info *imap.ConnInfo, username, password string) (backend.User, error) {
+	user, err := b.Backend.Login(info, username, password)
+	if err != nil {
+		return nil, err
+	}
+	return moveUser{user}, nil
+}
+
+func (u moveUser) GetMailbox(name string) (backend.Mailbox, error) {
+	mbox, err := u.User.GetMailbox(name)
+	if err != nil {
+		return nil, err
+	}
+	return moveMailbox{mbox}, nil
+}
+
+func (m moveMailbox) MoveMessages(uid bool, seqset *imap.SeqSet, dest string) error {
+	if err := m.CopyMessages(uid, seqset, dest); err != nil {
+		return err
+	}
+	if err := m.UpdateMessagesFlags(uid, seqset, imap.AddFlags, []string{imap.DeletedFlag}); err != nil {
+		return err
+	}
+	return m.Expunge()
+}
+
+func TestIMAPSourceListAndRead(t *testing.T) {
+	src := startIMAPServer(t, pdfAttachmentMessage)
+	ctx := context.Background()
+	defer func() { _ = src.Close() }()
+
+	if err := src.Test(ctx); err != nil {
+		t.Fatalf("Test() error = %v", err)
+	}
+
+	files, err := src.ListFiles(ctx)
+	if err != nil {
+		t.Fatalf("ListFiles() error = %v", err)
+	}
+	// The memory backend's seeded message is seen and has no attachments
+	if len(files) != 2 {
+		t.Fatalf("ListFiles() returned %d files, want 2: %+v", len(files), files)
+	}
+	if files[0].Name != "march.pdf" || files[1].Name != "april.pdf" {
+		t.Errorf("file names = %q, %q, want march.pdf, april.pdf", files[0].Name, files[1].Name)
+	}
+
+	var buf bytes.Buffer
+	if err := src.ReadFile(ctx, files[1].Path, &buf); err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if buf.String() != "%PDF-1.4\napril" {
+		t.Errorf("ReadFile() = %q, want april attachment", buf.String())
+	}
+}
+
+func TestIMAPSourceRefusesCleartext(t *testing.T) {
+	addr := serveIMAP(t, nil, pdfAttachmentMessage)
+
+	src := NewIMAPSource(addr, "INBOX", "username", "password")
+	defer func() { _ = src.Close() }()
+
+	err := src.Test(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "cleartext") {
+		t.Errorf("Test() error = %v, want cleartext refusal", err)
+	}
+}
+
+func TestIMAPSourcePostImportActions(t *testing.T) {
+	tests := []struct {
+		name  string
+		apply func(ctx context.Context, src *IMAPSource, file RemoteFile) error
+		check func(t *testing.T, src *IMAPSource)
+	}{
+		{
+			name: "mark seen",
+			apply: func(ctx context.Context, src *IMAPSource, file RemoteFile) error {
+				return src.MarkSeen(ctx, file.Path)
+			},
+		},
+		{
+			name: "delete",
+			apply: func(ctx context.Context, src *IMAPSource, file RemoteFile) error {
+				return src.DeleteFile(ctx, file.Path)
+			},
+		},
+		{
+			name: "move",
+			apply: func(ctx context.Context, src *IMAPSource, file RemoteFile) error {
+				return src.MoveFile(ctx, file.Path, strings.TrimSuffix(file.Path, file.Name)+"Imported/"+file.Name)
+			},
+			check: func(t *testing.T, src *IMAPSource) {
+				if _, err := src.client.Select("Imported", true); err != nil {
+					t.Fatalf("select Imported: %v", err)
+				}
+				criteria := imap.NewSearchCriteria()
+				criteria.Header.Add("Subject", "Your invoices")
+				uids, err := src.client.UidSearch(criteria)
+				if err != nil {
+					t.Fatalf("search Imported: %v", err)
+				}
+				if len(uids) != 1 {
+					t.Errorf("Imported has %d matching messages, want 1", len(uids))
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := startIMAPServer(t, pdfAttachmentMessage)
+			ctx := context.Background()
+			defer func() { _ = src.Close() }()
+
+			files, err := src.ListFiles(ctx)
+			if err != nil {
+				t.Fatalf("ListFiles() error = %v", err)
+			}
+			if len(files) != 2 {
+				t.Fatalf("ListFiles() returned %d files, want 2", len(files))
+			}
+
+			// The action waits for the message's last attachment
+			if err := tt.apply(ctx, src, files[0]); err != nil {
+				t.Fatalf("first action error = %v", err)
+			}
+			if again, _ := src.ListFiles(ctx); len(again) != 2 {
+				t.Fatalf("message handled after first attachment: %d files listed", len(again))
+			}
+
+			for _, file := range files {
+				if err := tt.apply(ctx, src, file); err != nil {
+					t.Fatalf("action error = %v", err)
+				}
+			}
+
+			again, err := src.ListFiles(ctx)
+			if err != nil {
+				t.Fatalf("ListFiles() after action error = %v", err)
+			}
+			if len(again) != 0 {
+				t.Errorf("ListFiles() after action returned %d files, want 0", len(again))
+			}
+
+			if tt.check != nil {
+				tt.check(t, src)
+			}
+		})
+	}
+}
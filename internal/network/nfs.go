@@ -10,6 +10,8 @@ import (
 
 	"github.com/vmware/go-nfs-client/nfs"
 	"github.com/vmware/go-nfs-client/nfs/rpc"
+
+	"github.com/bketelsen/docko/internal/document"
 )
 
 // NFSSource implements NetworkSource for NFSv3 shares.
@@ -79,8 +81,8 @@ func (s *NFSSource) Test(ctx context.Context) error {
 	return nil
 }
 
-// ListPDFs returns all PDF files in the export (recursive).
-func (s *NFSSource) ListPDFs(ctx context.Context) ([]RemoteFile, error) {
+// ListFiles returns all ingestible files (PDFs and images) in the export (recursive).
+func (s *NFSSource) ListFiles(ctx context.Context) ([]RemoteFile, error) {
 	if err := s.connect(ctx); err != nil {
 		return nil, err
 	}
@@ -125,8 +127,8 @@ func (s *NFSSource) walkDir(ctx context.Context, dir string, files *[]RemoteFile
 			continue
 		}
 
-		// Only include PDF files
-		if !strings.EqualFold(filepath.Ext(entry.Name()), ".pdf") {
+		// Only include files we can ingest
+		if !document.IsSupportedFilename(entry.Name()) {
 			continue
 		}
 
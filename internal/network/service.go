@@ -126,14 +126,14 @@ func (s *Service) SyncSource(ctx context.Context, sourceID uuid.UUID) (int, erro
 
 	slog.Info("starting sync", "source", cfg.Name, "host", cfg.Host)
 
-	// List PDF files
-	files, err := source.ListPDFs(ctx)
+	// List ingestible files
+	files, err := source.ListFiles(ctx)
 	if err != nil {
 		s.recordSyncFailure(ctx, &cfg, err)
 		return 0, fmt.Errorf("list files: %w", err)
 	}
 
-	slog.Info("found PDF files", "source", cfg.Name, "count", len(files))
+	slog.Info("found files", "source", cfg.Name, "count", len(files))
 
 	// Apply batch size limit
 	if len(files) > int(cfg.BatchSize) {
@@ -195,7 +195,7 @@ func (s *Service) SyncAll(ctx context.Context) error {
 // importFile downloads and ingests a single file.
 func (s *Service) importFile(ctx context.Context, source NetworkSource, cfg *sqlc.NetworkSource, file RemoteFile) error {
 	// Create temp file for download
-	tmpFile, err := os.CreateTemp("", TempFilePrefix+"*.pdf")
+	tmpFile, err := os.CreateTemp("", TempFilePrefix+"*"+filepath.Ext(file.Name))
 	if err != nil {
 		return fmt.Errorf("create temp file: %w", err)
 	}
@@ -209,8 +209,17 @@ func (s *Service) importFile(ctx context.Context, source NetworkSource, cfg *sql
 	}
 	_ = tmpFile.Close()
 
-	// Ingest through document service
-	doc, isDupe, err := s.docSvc.Ingest(ctx, tmpPath, file.Name)
+	// Ingest through document service with the source's default metadata
+	doc, isDupe, err := s.docSvc.IngestWithOptions(ctx, tmpPath, file.Name, document.IngestOptions{
+		Source: &document.IngestSource{
+			Type:              document.SourceNetwork,
+			ID:                cfg.ID,
+			Name:              cfg.Name,
+			TagNames:          cfg.DefaultTags,
+			CorrespondentName: deref(cfg.DefaultCorrespondent),
+			DocumentTypeName:  deref(cfg.DefaultDocumentType),
+		},
+	})
 	if err != nil {
 		return fmt.Errorf("ingest: %w", err)
 	}
@@ -240,7 +249,10 @@ func (s *Service) importFile(ctx context.Context, source NetworkSource, cfg *sql
 func (s *Service) handlePostImportAction(ctx context.Context, source NetworkSource, cfg *sqlc.NetworkSource, file RemoteFile) error {
 	switch cfg.PostImportAction {
 	case sqlc.PostImportActionLeave:
-		// Do nothing
+		// Leave in place, flagging it handled where the source supports it
+		if marker, ok := source.(SeenMarker); ok {
+			return marker.MarkSeen(ctx, file.Path)
+		}
 		return nil
 
 	case sqlc.PostImportActionDelete:
@@ -339,3 +351,11 @@ func (s *Service) logEvent(ctx context.Context, sourceID uuid.UUID, filename, re
 func (s *Service) GetCrypto() *CredentialCrypto {
 	return s.crypto
 }
+
+// deref returns the string s points to, or "" if s is nil
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -7,10 +7,11 @@ import (
 	"io/fs"
 	"net"
 	"path/filepath"
-	"strings"
 	"time"
 
 	"github.com/hirochachacha/go-smb2"
+
+	"github.com/bketelsen/docko/internal/document"
 )
 
 const (
@@ -110,8 +111,8 @@ func (s *SMBSource) Test(ctx context.Context) error {
 	return nil
 }
 
-// ListPDFs returns all PDF files in the share (recursive).
-func (s *SMBSource) ListPDFs(ctx context.Context) ([]RemoteFile, error) {
+// ListFiles returns all ingestible files (PDFs and images) in the share (recursive).
+func (s *SMBSource) ListFiles(ctx context.Context) ([]RemoteFile, error) {
 	if err := s.connect(ctx); err != nil {
 		return nil, err
 	}
@@ -137,8 +138,8 @@ func (s *SMBSource) ListPDFs(ctx context.Context) ([]RemoteFile, error) {
 			return nil
 		}
 
-		// Only include PDF files
-		if !strings.EqualFold(filepath.Ext(path), ".pdf") {
+		// Only include files we can ingest
+		if !document.IsSupportedFilename(path) {
 			return nil
 		}
 
@@ -17,15 +17,15 @@ type RemoteFile struct {
 	ModTime time.Time // Last modification time
 }
 
-// NetworkSource defines the interface for network file sources (SMB, NFS).
+// NetworkSource defines the interface for network file sources (SMB, NFS, IMAP).
 type NetworkSource interface {
 	// Test validates that connection can be established.
 	// Returns nil on success, error describing the failure otherwise.
 	Test(ctx context.Context) error
 
-	// ListPDFs returns all PDF files in the source (recursive).
+	// ListFiles returns all ingestible files (PDFs and images) in the source (recursive).
 	// Files are returned in no particular order.
-	ListPDFs(ctx context.Context) ([]RemoteFile, error)
+	ListFiles(ctx context.Context) ([]RemoteFile, error)
 
 	// ReadFile copies file content from remote path to the provided writer.
 	ReadFile(ctx context.Context, remotePath string, w io.Writer) error
@@ -41,32 +41,55 @@ type NetworkSource interface {
 	Close() error
 }
 
+// SeenMarker is implemented by sources that can flag a file as handled in
+// place, so the "leave" post-import action keeps it from being listed again.
+type SeenMarker interface {
+	// MarkSeen flags the file as handled without removing it.
+	MarkSeen(ctx context.Context, remotePath string) error
+}
+
 // NewSourceFromConfig creates a NetworkSource from database configuration.
-// The crypto parameter is used to decrypt passwords for SMB sources.
+// The crypto parameter is used to decrypt passwords for SMB and IMAP sources.
 func NewSourceFromConfig(cfg *sqlc.NetworkSource, crypto *CredentialCrypto) (NetworkSource, error) {
 	switch cfg.Protocol {
 	case sqlc.NetworkProtocolSmb:
-		// Decrypt password if present
-		password := ""
-		if cfg.PasswordEncrypted != nil && *cfg.PasswordEncrypted != "" {
-			var err error
-			password, err = crypto.Decrypt(*cfg.PasswordEncrypted)
-			if err != nil {
-				return nil, fmt.Errorf("decrypt password: %w", err)
-			}
-		}
-
-		username := ""
-		if cfg.Username != nil {
-			username = *cfg.Username
+		username, password, err := credentials(cfg, crypto)
+		if err != nil {
+			return nil, err
 		}
-
 		return NewSMBSource(cfg.Host, cfg.SharePath, username, password), nil
 
 	case sqlc.NetworkProtocolNfs:
 		return NewNFSSource(cfg.Host, cfg.SharePath), nil
 
+	case sqlc.NetworkProtocolImap:
+		username, password, err := credentials(cfg, crypto)
+		if err != nil {
+			return nil, err
+		}
+		// share_path holds the mailbox folder for IMAP
+		return NewIMAPSource(cfg.Host, cfg.SharePath, username, password), nil
+
 	default:
 		return nil, fmt.Errorf("unsupported protocol: %s", cfg.Protocol)
 	}
 }
+
+// credentials returns the source's username and decrypted password.
+func credentials(cfg *sqlc.NetworkSource, crypto *CredentialCrypto) (string, string, error) {
+	// Decrypt password if present
+	password := ""
+	if cfg.PasswordEncrypted != nil && *cfg.PasswordEncrypted != "" {
+		var err error
+		password, err = crypto.Decrypt(*cfg.PasswordEncrypted)
+		if err != nil {
+			return "", "", fmt.Errorf("decrypt password: %w", err)
+		}
+	}
+
+	username := ""
+	if cfg.Username != nil {
+		username = *cfg.Username
+	}
+	return username, password, nil
+}
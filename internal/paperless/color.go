@@ -0,0 +1,68 @@
+package paperless
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// hueColors divides the color wheel among docko's tag colors
+// Each entry covers hues below its limit, in degrees.
+var hueColors = []struct {
+	limit float64
+	color string
+}{
+	{15, "red"},
+	{33, "orange"},
+	{42, "amber"},
+	{65, "yellow"},
+	{150, "green"},
+	{167, "emerald"},
+	{190, "teal"},
+	{230, "blue"},
+	{255, "indigo"},
+	{295, "purple"},
+	{345, "pink"},
+	{360, "red"},
+}
+
+// TagColor returns the docko tag color closest to a Paperless #rrggbb color
+// Paperless lets tags have any color; docko has a fixed palette, so the
+// color is matched by hue, with washed-out colors mapped to gray.
+func TagColor(hex string) string {
+	hex = strings.TrimPrefix(strings.TrimSpace(hex), "#")
+	rgb, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil || len(hex) != 6 {
+		return "blue"
+	}
+	r := float64(rgb>>16&0xff) / 255
+	g := float64(rgb>>8&0xff) / 255
+	b := float64(rgb&0xff) / 255
+
+	hi, lo := max(r, g, b), min(r, g, b)
+	light := (hi + lo) / 2
+	if hi-lo < 0.1 || light < 0.1 || light > 0.95 {
+		return "gray"
+	}
+
+	var hue float64
+	switch hi {
+	case r:
+		hue = math.Mod((g-b)/(hi-lo), 6)
+	case g:
+		hue = (b-r)/(hi-lo) + 2
+	default:
+		hue = (r-g)/(hi-lo) + 4
+	}
+	hue *= 60
+	if hue < 0 {
+		hue += 360
+	}
+
+	for _, hc := range hueColors {
+		if hue < hc.limit {
+			return hc.color
+		}
+	}
+	return "red"
+}
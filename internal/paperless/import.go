@@ -0,0 +1,370 @@
+package paperless
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/bketelsen/docko/internal/database"
+	"github.com/bketelsen/docko/internal/database/sqlc"
+	"github.com/bketelsen/docko/internal/document"
+)
+
+// fieldTypes maps Paperless custom field types to docko's
+// Select fields keep the chosen option's label as text.
+var fieldTypes = map[string]sqlc.CustomFieldType{
+	"string":       sqlc.CustomFieldTypeString,
+	"longtext":     sqlc.CustomFieldTypeString,
+	"select":       sqlc.CustomFieldTypeString,
+	"url":          sqlc.CustomFieldTypeUrl,
+	"date":         sqlc.CustomFieldTypeDate,
+	"boolean":      sqlc.CustomFieldTypeBoolean,
+	"integer":      sqlc.CustomFieldTypeNumber,
+	"float":        sqlc.CustomFieldTypeNumber,
+	"monetary":     sqlc.CustomFieldTypeMonetary,
+	"documentlink": sqlc.CustomFieldTypeDocumentLink,
+}
+
+// otherModels names the unmapped Paperless models people will recognize
+var otherModels = map[string]string{
+	"documents.storagepath":         "storage paths",
+	"documents.savedview":           "saved views",
+	"documents.savedviewfilterrule": "saved view filter rules",
+	"documents.sharelink":           "share links",
+	"documents.workflow":            "workflows",
+	"paperless_mail.mailaccount":    "mail accounts",
+	"paperless_mail.mailrule":       "mail rules",
+	"auth.user":                     "users",
+	"auth.group":                    "groups",
+}
+
+// Options configure an import
+type Options struct {
+	DryRun bool // Check what would be imported without importing
+	// Progress, if set, is called after each document
+	Progress func(doc *Document, duplicate bool, err error)
+}
+
+// Report summarizes an import
+type Report struct {
+	Imported   int
+	Duplicates int
+	Failed     int
+	// Unmapped counts what docko couldn't represent, by description
+	Unmapped map[string]int
+}
+
+func (r *Report) unmapped(what string, n int) {
+	if n > 0 {
+		r.Unmapped[what] += n
+	}
+}
+
+// importer holds the state of one import
+type importer struct {
+	db     *database.DB
+	docSvc *document.Service
+	exp    *Export
+	opts   Options
+	report *Report
+	fields map[int64]sqlc.CustomField // docko field for each mapped Paperless field
+	docs   map[int64]uuid.UUID        // docko document for each imported Paperless document
+}
+
+// Import ingests the documents of a loaded export with their metadata
+// Tags, correspondents, document types and custom fields are matched to
+// existing ones by name or created. Documents already in docko are counted
+// as duplicates and left unchanged. Paperless's extracted text and archive
+// PDF are imported with each document, so it isn't OCR'd again.
+func Import(ctx context.Context, db *database.DB, docSvc *document.Service, exp *Export, opts Options) (*Report, error) {
+	im := &importer{
+		db:     db,
+		docSvc: docSvc,
+		exp:    exp,
+		opts:   opts,
+		report: &Report{Unmapped: make(map[string]int)},
+		fields: make(map[int64]sqlc.CustomField),
+		docs:   make(map[int64]uuid.UUID),
+	}
+
+	for model, n := range exp.Other {
+		name, ok := otherModels[model]
+		if !ok {
+			name = model + " records"
+		}
+		im.report.unmapped(name, n)
+	}
+
+	if err := im.createTags(ctx); err != nil {
+		return im.report, err
+	}
+	if err := im.createFields(ctx); err != nil {
+		return im.report, err
+	}
+	for i := range exp.Documents {
+		if err := ctx.Err(); err != nil {
+			return im.report, err
+		}
+		im.importDocument(ctx, &exp.Documents[i])
+	}
+	if !opts.DryRun {
+		im.setFieldValues(ctx)
+	}
+	return im.report, nil
+}
+
+// createTags creates the tags that don't exist yet, keeping their color
+func (im *importer) createTags(ctx context.Context) error {
+	if im.opts.DryRun {
+		return nil
+	}
+	for _, t := range im.exp.Tags {
+		_, err := im.db.Queries.GetTagByName(ctx, t.Name)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("get tag: %w", err)
+		}
+		color := TagColor(t.Color)
+		if _, err := im.db.Queries.CreateTag(ctx, sqlc.CreateTagParams{Name: t.Name, Color: &color}); err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("create tag %q: %w", t.Name, err)
+		}
+	}
+	return nil
+}
+
+// createFields finds or creates a docko custom field for each Paperless one
+func (im *importer) createFields(ctx context.Context) error {
+	existing, err := im.db.Queries.ListCustomFields(ctx)
+	if err != nil {
+		return fmt.Errorf("list custom fields: %w", err)
+	}
+	byName := make(map[string]sqlc.CustomField, len(existing))
+	for _, f := range existing {
+		byName[strings.ToLower(f.Name)] = f
+	}
+
+	for pk, f := range im.exp.CustomFields {
+		fieldType, ok := fieldTypes[f.DataType]
+		if !ok {
+			im.report.unmapped(fmt.Sprintf("custom field %q (%s fields aren't supported)", f.Name, f.DataType), 1)
+			continue
+		}
+		if field, ok := byName[strings.ToLower(f.Name)]; ok {
+			if field.FieldType != fieldType {
+				im.report.unmapped(fmt.Sprintf("custom field %q (docko's field of that name is a %s field)", f.Name, field.FieldType), 1)
+				continue
+			}
+			im.fields[pk] = field
+			continue
+		}
+		if im.opts.DryRun {
+			im.fields[pk] = sqlc.CustomField{Name: f.Name, FieldType: fieldType}
+			continue
+		}
+		field, err := im.db.Queries.CreateCustomField(ctx, sqlc.CreateCustomFieldParams{Name: f.Name, FieldType: fieldType})
+		if err != nil {
+			return fmt.Errorf("create custom field %q: %w", f.Name, err)
+		}
+		im.fields[pk] = field
+	}
+	return nil
+}
+
+// importDocument ingests one document, recording the outcome in the report
+func (im *importer) importDocument(ctx context.Context, d *Document) {
+	duplicate, err := im.ingest(ctx, d)
+	switch {
+	case err != nil:
+		im.report.Failed++
+	case duplicate:
+		im.report.Duplicates++
+	default:
+		im.report.Imported++
+		if d.ASN != nil {
+			im.report.unmapped("archive serial numbers", 1)
+		}
+		if d.StoragePath != nil {
+			im.report.unmapped("storage path assignments", 1)
+		}
+	}
+	if im.opts.Progress != nil {
+		im.opts.Progress(d, duplicate, err)
+	}
+}
+
+func (im *importer) ingest(ctx context.Context, d *Document) (bool, error) {
+	if d.StorageType == "gpg" {
+		return false, fmt.Errorf("file is GPG-encrypted; decrypt it in Paperless (decrypt_documents) and export again")
+	}
+	if d.File == "" {
+		return false, fmt.Errorf("export has no file for this document")
+	}
+	path := filepath.Join(im.exp.Dir, d.File)
+
+	if im.opts.DryRun {
+		hash, err := hashFile(path)
+		if err != nil {
+			return false, err
+		}
+		_, err = im.docSvc.GetByHash(ctx, hash)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return false, err
+		}
+		return err == nil, nil
+	}
+
+	opts := document.IngestOptions{
+		Title: strings.TrimSpace(d.Title),
+		Notes: strings.Join(im.exp.Notes[d.PK], "\n\n"),
+		Text:  strings.TrimSpace(d.Content),
+	}
+	if created, ok := d.CreatedDate(); ok {
+		opts.DocumentDate = &created
+	}
+	if d.Correspondent != nil {
+		opts.CorrespondentName = im.exp.Correspondents[*d.Correspondent]
+	}
+	if d.DocumentType != nil {
+		opts.DocumentTypeName = im.exp.DocumentTypes[*d.DocumentType]
+	}
+	for _, pk := range d.Tags {
+		if t, ok := im.exp.Tags[pk]; ok {
+			opts.TagNames = append(opts.TagNames, t.Name)
+		}
+	}
+	// Exports made with --no-archive have no archive files
+	if archive := filepath.Join(im.exp.Dir, d.Archive); d.Archive != "" && fileExists(archive) {
+		opts.ArchivePath = archive
+	}
+
+	filename := d.OriginalFilename
+	if filename == "" {
+		filename = filepath.Base(d.File)
+	}
+	doc, duplicate, err := im.docSvc.IngestWithOptions(ctx, path, filename, opts)
+	if err != nil {
+		return false, err
+	}
+	if !duplicate {
+		im.docs[d.PK] = doc.ID
+	}
+	return duplicate, nil
+}
+
+// setFieldValues sets custom field values on the imported documents
+// It runs after every document is in, so document links can be resolved.
+func (im *importer) setFieldValues(ctx context.Context) {
+	for _, v := range im.exp.FieldValues {
+		docID, ok := im.docs[v.Document]
+		if !ok {
+			continue // Not imported, or already in docko
+		}
+		field, ok := im.fields[v.Field]
+		if !ok {
+			im.report.unmapped("values of unmapped custom fields", 1)
+			continue
+		}
+
+		pf := im.exp.CustomFields[v.Field]
+		raw, ok := im.fieldValue(&pf, &v)
+		if !ok {
+			continue // Empty
+		}
+		if err := im.docSvc.SetCustomField(ctx, docID, field.ID, raw); err != nil {
+			im.report.unmapped(fmt.Sprintf("custom field %q values that didn't fit its type", field.Name), 1)
+		}
+	}
+}
+
+// fieldValue returns a Paperless field value as docko field input
+func (im *importer) fieldValue(f *CustomField, v *FieldValue) (string, bool) {
+	switch f.DataType {
+	case "string":
+		return deref(v.ValueText)
+	case "longtext":
+		return deref(v.ValueLongText)
+	case "url":
+		return deref(v.ValueURL)
+	case "date":
+		return deref(v.ValueDate)
+	case "monetary":
+		return deref(v.ValueMonetary) // Currency codes are stripped on input
+	case "boolean":
+		if v.ValueBool == nil {
+			return "", false
+		}
+		return strconv.FormatBool(*v.ValueBool), true
+	case "integer":
+		if v.ValueInt == nil {
+			return "", false
+		}
+		return strconv.FormatInt(*v.ValueInt, 10), true
+	case "float":
+		if v.ValueFloat == nil || math.IsNaN(*v.ValueFloat) {
+			return "", false
+		}
+		return strconv.FormatFloat(*v.ValueFloat, 'f', -1, 64), true
+	case "select":
+		if len(v.ValueSelect) == 0 || string(v.ValueSelect) == "null" {
+			return "", false
+		}
+		label, ok := f.selectLabel(v.ValueSelect)
+		if !ok {
+			im.report.unmapped(fmt.Sprintf("custom field %q options that aren't defined", f.Name), 1)
+		}
+		return label, ok
+	case "documentlink":
+		// docko links a field to one document
+		if len(v.ValueDocumentIDs) > 1 {
+			im.report.unmapped("document links beyond the first in a field", len(v.ValueDocumentIDs)-1)
+		}
+		for _, pk := range v.ValueDocumentIDs {
+			if id, ok := im.docs[pk]; ok {
+				return id.String(), true
+			}
+		}
+		if len(v.ValueDocumentIDs) > 0 {
+			im.report.unmapped("document links to documents that weren't imported", 1)
+		}
+	}
+	return "", false
+}
+
+func deref(s *string) (string, bool) {
+	if s == nil || strings.TrimSpace(*s) == "" {
+		return "", false
+	}
+	return *s, true
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// hashFile returns the SHA-256 of a file, as docko records it
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
@@ -0,0 +1,276 @@
+// Package paperless imports a Paperless-ngx document exporter dump
+// The exporter writes every database record to manifest.json (or one
+// manifest per document with --split-manifest) next to the original, archive
+// and thumbnail files. Records docko has an equivalent for are mapped; the
+// rest are counted in the import report.
+package paperless
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// record is one entry of a Paperless manifest
+type record struct {
+	Model  string          `json:"model"`
+	PK     json.RawMessage `json:"pk"`
+	Fields json.RawMessage `json:"fields"`
+
+	// Set on documents: file paths relative to the export directory
+	ExportedFile    string `json:"__exported_file_name__"`
+	ExportedArchive string `json:"__exported_archive_name__"`
+}
+
+// Tag is a Paperless tag
+type Tag struct {
+	Name  string `json:"name"`
+	Color string `json:"color"` // #rrggbb
+}
+
+// named is a Paperless correspondent or document type
+type named struct {
+	Name string `json:"name"`
+}
+
+// CustomField is a Paperless custom field definition
+type CustomField struct {
+	Name      string          `json:"name"`
+	DataType  string          `json:"data_type"`
+	ExtraData json.RawMessage `json:"extra_data"` // Holds select_options for select fields
+}
+
+// Document is a Paperless document
+type Document struct {
+	PK               int64   `json:"-"`
+	Title            string  `json:"title"`
+	Content          string  `json:"content"` // Text extracted or OCR'd by Paperless
+	Correspondent    *int64  `json:"correspondent"`
+	DocumentType     *int64  `json:"document_type"`
+	StoragePath      *int64  `json:"storage_path"`
+	Tags             []int64 `json:"tags"`
+	Created          string  `json:"created"` // A date, or a datetime before Paperless 2.16
+	OriginalFilename string  `json:"original_filename"`
+	ASN              *int64  `json:"archive_serial_number"`
+	StorageType      string  `json:"storage_type"` // "gpg" for documents encrypted by old versions
+
+	File    string `json:"-"` // Original, relative to the export directory
+	Archive string `json:"-"` // Searchable PDF/A, relative to the export directory; may be empty
+}
+
+// CreatedDate parses the document's created date
+func (d *Document) CreatedDate() (time.Time, bool) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02"} {
+		if t, err := time.Parse(layout, d.Created); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// FieldValue is a custom field value on a Paperless document
+// Each field type keeps its value in its own column.
+type FieldValue struct {
+	Document         int64           `json:"document"`
+	Field            int64           `json:"field"`
+	ValueText        *string         `json:"value_text"`
+	ValueLongText    *string         `json:"value_long_text"`
+	ValueBool        *bool           `json:"value_bool"`
+	ValueURL         *string         `json:"value_url"`
+	ValueDate        *string         `json:"value_date"`
+	ValueInt         *int64          `json:"value_int"`
+	ValueFloat       *float64        `json:"value_float"`
+	ValueMonetary    *string         `json:"value_monetary"` // e.g. EUR12.50
+	ValueDocumentIDs []int64         `json:"value_document_ids"`
+	ValueSelect      json.RawMessage `json:"value_select"` // Option index, or option ID since Paperless 2.14
+}
+
+// note is a Paperless document note
+type note struct {
+	Document int64  `json:"document"`
+	Note     string `json:"note"`
+}
+
+// Export is a loaded Paperless export
+type Export struct {
+	Dir            string
+	Tags           map[int64]Tag
+	Correspondents map[int64]string
+	DocumentTypes  map[int64]string
+	CustomFields   map[int64]CustomField
+	Documents      []Document // Ordered by Paperless ID
+	FieldValues    []FieldValue
+	Notes          map[int64][]string // By document
+	Other          map[string]int     // Records of models docko has no equivalent for
+}
+
+// Load reads the manifests of the export in dir
+func Load(dir string) (*Export, error) {
+	paths := []string{filepath.Join(dir, "manifest.json")}
+	if _, err := os.Stat(paths[0]); err != nil {
+		return nil, fmt.Errorf("%s is not a Paperless export: %w", dir, err)
+	}
+	// --split-manifest writes each document's records next to its file
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && strings.HasSuffix(d.Name(), "-manifest.json") {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("find manifests: %w", err)
+	}
+
+	exp := &Export{
+		Dir:            dir,
+		Tags:           make(map[int64]Tag),
+		Correspondents: make(map[int64]string),
+		DocumentTypes:  make(map[int64]string),
+		CustomFields:   make(map[int64]CustomField),
+		Notes:          make(map[int64][]string),
+		Other:          make(map[string]int),
+	}
+	for _, path := range paths {
+		if err := exp.read(path); err != nil {
+			return nil, fmt.Errorf("read %s: %w", filepath.Base(path), err)
+		}
+	}
+	slices.SortFunc(exp.Documents, func(a, b Document) int { return cmp.Compare(a.PK, b.PK) })
+	return exp, nil
+}
+
+// read adds the records of one manifest file
+func (exp *Export) read(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var records []record
+	if err := json.Unmarshal(data, &records); err != nil {
+		return err
+	}
+	for _, r := range records {
+		if err := exp.add(r); err != nil {
+			return fmt.Errorf("%s %s: %w", r.Model, r.PK, err)
+		}
+	}
+	return nil
+}
+
+// mappedModels are the Paperless models docko has an equivalent for
+var mappedModels = map[string]bool{
+	"documents.tag":                 true,
+	"documents.correspondent":       true,
+	"documents.documenttype":        true,
+	"documents.customfield":         true,
+	"documents.document":            true,
+	"documents.customfieldinstance": true,
+	"documents.note":                true,
+}
+
+// add maps one record
+func (exp *Export) add(r record) error {
+	if !mappedModels[r.Model] {
+		exp.Other[r.Model]++
+		return nil
+	}
+	var pk int64
+	if err := json.Unmarshal(r.PK, &pk); err != nil {
+		return fmt.Errorf("invalid id: %w", err)
+	}
+
+	switch r.Model {
+	case "documents.tag":
+		var t Tag
+		if err := json.Unmarshal(r.Fields, &t); err != nil {
+			return err
+		}
+		exp.Tags[pk] = t
+	case "documents.correspondent":
+		var c named
+		if err := json.Unmarshal(r.Fields, &c); err != nil {
+			return err
+		}
+		exp.Correspondents[pk] = c.Name
+	case "documents.documenttype":
+		var t named
+		if err := json.Unmarshal(r.Fields, &t); err != nil {
+			return err
+		}
+		exp.DocumentTypes[pk] = t.Name
+	case "documents.customfield":
+		var f CustomField
+		if err := json.Unmarshal(r.Fields, &f); err != nil {
+			return err
+		}
+		exp.CustomFields[pk] = f
+	case "documents.document":
+		var d Document
+		if err := json.Unmarshal(r.Fields, &d); err != nil {
+			return err
+		}
+		d.PK = pk
+		d.File = filepath.FromSlash(r.ExportedFile)
+		d.Archive = filepath.FromSlash(r.ExportedArchive)
+		exp.Documents = append(exp.Documents, d)
+	case "documents.customfieldinstance":
+		var v FieldValue
+		if err := json.Unmarshal(r.Fields, &v); err != nil {
+			return err
+		}
+		exp.FieldValues = append(exp.FieldValues, v)
+	case "documents.note":
+		var n note
+		if err := json.Unmarshal(r.Fields, &n); err != nil {
+			return err
+		}
+		if n.Note = strings.TrimSpace(n.Note); n.Note != "" {
+			exp.Notes[n.Document] = append(exp.Notes[n.Document], n.Note)
+		}
+	}
+	return nil
+}
+
+// selectLabel returns the label of a select field's chosen option
+func (f *CustomField) selectLabel(value json.RawMessage) (string, bool) {
+	var extra struct {
+		Options json.RawMessage `json:"select_options"`
+	}
+	if err := json.Unmarshal(f.ExtraData, &extra); err != nil {
+		return "", false
+	}
+
+	// Options were plain labels chosen by index before Paperless 2.14
+	var labels []string
+	var index int
+	if json.Unmarshal(extra.Options, &labels) == nil && json.Unmarshal(value, &index) == nil {
+		if index >= 0 && index < len(labels) {
+			return labels[index], true
+		}
+		return "", false
+	}
+
+	var options []struct {
+		ID    string `json:"id"`
+		Label string `json:"label"`
+	}
+	var id string
+	if json.Unmarshal(extra.Options, &options) != nil || json.Unmarshal(value, &id) != nil {
+		return "", false
+	}
+	for _, o := range options {
+		if o.ID == id {
+			return o.Label, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,151 @@
+package paperless
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const testManifest = `[
+  {"model": "documents.correspondent", "pk": 1, "fields": {"name": "City Bank", "match": "", "matching_algorithm": 1}},
+  {"model": "documents.tag", "pk": 2, "fields": {"name": "Taxes", "color": "#e31a1c", "is_inbox_tag": false}},
+  {"model": "documents.documenttype", "pk": 3, "fields": {"name": "Statement"}},
+  {"model": "documents.customfield", "pk": 4, "fields": {"name": "Category", "data_type": "select",
+    "extra_data": {"select_options": [{"id": "k1", "label": "Home"}, {"id": "k2", "label": "Work"}]}}},
+  {"model": "documents.storagepath", "pk": 1, "fields": {"name": "By year", "path": "{created_year}/{title}"}},
+  {"model": "auth.user", "pk": 1, "fields": {"username": "admin"}},
+  {"model": "documents.document", "pk": 7, "fields": {"correspondent": 1, "document_type": 3, "tags": [2],
+    "title": "March statement", "content": "Balance 100.00", "created": "2024-03-15", "original_filename": "scan.pdf",
+    "archive_serial_number": 12, "storage_type": "unencrypted"},
+    "__exported_file_name__": "originals/0000007.pdf", "__exported_archive_name__": "archive/0000007.pdf"},
+  {"model": "documents.note", "pk": 1, "fields": {"document": 7, "note": " Paid in full ", "user": 1}}
+]`
+
+const testSplitManifest = `[
+  {"model": "documents.document", "pk": 5, "fields": {"title": "Lease", "content": "", "created": "2021-06-01T00:00:00+02:00",
+    "original_filename": "lease.pdf", "tags": []},
+    "__exported_file_name__": "0000005.pdf"}
+]`
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "manifest.json"), []byte(testManifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "0000005-manifest.json"), []byte(testSplitManifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	exp, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if exp.Correspondents[1] != "City Bank" || exp.DocumentTypes[3] != "Statement" || exp.Tags[2].Color != "#e31a1c" {
+		t.Errorf("metadata not loaded: %+v %+v %+v", exp.Correspondents, exp.DocumentTypes, exp.Tags)
+	}
+	if exp.Other["documents.storagepath"] != 1 || exp.Other["auth.user"] != 1 {
+		t.Errorf("Other = %v, want storage path and user counted", exp.Other)
+	}
+	if got := exp.Notes[7]; len(got) != 1 || got[0] != "Paid in full" {
+		t.Errorf("Notes[7] = %q", got)
+	}
+
+	if len(exp.Documents) != 2 || exp.Documents[0].PK != 5 || exp.Documents[1].PK != 7 {
+		t.Fatalf("Documents = %+v, want 5 and 7 in order", exp.Documents)
+	}
+	d := exp.Documents[1]
+	if d.File != filepath.Join("originals", "0000007.pdf") || d.Archive != filepath.Join("archive", "0000007.pdf") {
+		t.Errorf("files = %q, %q", d.File, d.Archive)
+	}
+	if d.ASN == nil || *d.ASN != 12 || d.Content != "Balance 100.00" {
+		t.Errorf("document fields not loaded: %+v", d)
+	}
+
+	created, ok := d.CreatedDate()
+	if !ok || !created.Equal(time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("CreatedDate() = %v, %v", created, ok)
+	}
+	created, ok = exp.Documents[0].CreatedDate()
+	if !ok || created.UTC() != time.Date(2021, 5, 31, 22, 0, 0, 0, time.UTC) {
+		t.Errorf("CreatedDate() of a datetime = %v, %v", created, ok)
+	}
+}
+
+func TestLoadNotAnExport(t *testing.T) {
+	if _, err := Load(t.TempDir()); err == nil {
+		t.Error("Load of a directory without manifest.json succeeded")
+	}
+}
+
+func TestTagColor(t *testing.T) {
+	tests := []struct {
+		hex  string
+		want string
+	}{
+		{"#e31a1c", "red"},
+		{"#ff7f00", "orange"},
+		{"#33a02c", "green"},
+		{"#a6cee3", "blue"},
+		{"#1f78b4", "blue"},
+		{"#6a3d9a", "purple"},
+		{"#fb9a99", "red"},
+		{"#ec4899", "pink"},
+		{"#808080", "gray"},
+		{"#000000", "gray"},
+		{"not a color", "blue"},
+	}
+
+	for _, tt := range tests {
+		if got := TagColor(tt.hex); got != tt.want {
+			t.Errorf("TagColor(%q) = %q, want %q", tt.hex, got, tt.want)
+		}
+	}
+}
+
+func TestFieldValue(t *testing.T) {
+	linked := uuid.New()
+	im := &importer{
+		report: &Report{Unmapped: make(map[string]int)},
+		docs:   map[int64]uuid.UUID{9: linked},
+	}
+	str := func(s string) *string { return &s }
+	yes := true
+	n := int64(42)
+	f := 2.5
+
+	tests := []struct {
+		field CustomField
+		value FieldValue
+		want  string
+		ok    bool
+	}{
+		{CustomField{DataType: "string"}, FieldValue{ValueText: str("hello")}, "hello", true},
+		{CustomField{DataType: "string"}, FieldValue{}, "", false},
+		{CustomField{DataType: "monetary"}, FieldValue{ValueMonetary: str("EUR12.50")}, "EUR12.50", true},
+		{CustomField{DataType: "boolean"}, FieldValue{ValueBool: &yes}, "true", true},
+		{CustomField{DataType: "integer"}, FieldValue{ValueInt: &n}, "42", true},
+		{CustomField{DataType: "float"}, FieldValue{ValueFloat: &f}, "2.5", true},
+		{CustomField{DataType: "select", ExtraData: json.RawMessage(`{"select_options": ["Home", "Work"]}`)},
+			FieldValue{ValueSelect: json.RawMessage(`1`)}, "Work", true},
+		{CustomField{DataType: "select", ExtraData: json.RawMessage(`{"select_options": [{"id": "k1", "label": "Home"}]}`)},
+			FieldValue{ValueSelect: json.RawMessage(`"k1"`)}, "Home", true},
+		{CustomField{DataType: "documentlink"}, FieldValue{ValueDocumentIDs: []int64{3, 9}}, linked.String(), true},
+		{CustomField{DataType: "documentlink"}, FieldValue{ValueDocumentIDs: []int64{3}}, "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := im.fieldValue(&tt.field, &tt.value)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("fieldValue(%s, %+v) = %q, %v; want %q, %v", tt.field.DataType, tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+	if im.report.Unmapped["document links beyond the first in a field"] != 1 ||
+		im.report.Unmapped["document links to documents that weren't imported"] != 1 {
+		t.Errorf("Unmapped = %v", im.report.Unmapped)
+	}
+}
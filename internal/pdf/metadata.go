@@ -0,0 +1,47 @@
+package pdf
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// Metadata holds the document information of a PDF
+type Metadata struct {
+	PageCount int
+	Title     string
+	Author    string
+	CreatedAt time.Time // Zero if the PDF doesn't record it
+}
+
+// ReadMetadata reads a PDF's page count, title, author and creation date
+// The info dictionary is merged with XMP metadata, which wins where both are set.
+func ReadMetadata(path string) (*Metadata, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open pdf: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	// Validation populates the context from the info dictionary and XMP
+	ctx, err := api.ReadAndValidate(f, nil)
+	if err != nil {
+		return nil, fmt.Errorf("read pdf metadata: %w", err)
+	}
+
+	meta := &Metadata{
+		PageCount: ctx.PageCount,
+		Title:     strings.TrimSpace(ctx.Title),
+		Author:    strings.TrimSpace(ctx.Author),
+	}
+	if ctx.XRefTable.CreationDate != "" {
+		if t, ok := types.DateTime(ctx.XRefTable.CreationDate, true); ok {
+			meta.CreatedAt = t
+		}
+	}
+	return meta, nil
+}
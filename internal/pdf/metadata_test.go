@@ -0,0 +1,77 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeInfoPDF writes a one-page PDF whose info dictionary holds info
+func writeInfoPDF(t *testing.T, path, info string) {
+	t.Helper()
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] >>",
+		info,
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info 4 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatalf("write pdf: %v", err)
+	}
+}
+
+func TestReadMetadata(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "info.pdf")
+	writeInfoPDF(t, path, "<< /Title (Electric Bill) /Author ( City Utilities ) /CreationDate (D:20240315093000Z) >>")
+
+	meta, err := ReadMetadata(path)
+	if err != nil {
+		t.Fatalf("ReadMetadata failed: %v", err)
+	}
+	if meta.PageCount != 1 {
+		t.Errorf("PageCount = %d, want 1", meta.PageCount)
+	}
+	if meta.Title != "Electric Bill" {
+		t.Errorf("Title = %q, want %q", meta.Title, "Electric Bill")
+	}
+	if meta.Author != "City Utilities" {
+		t.Errorf("Author = %q, want %q", meta.Author, "City Utilities")
+	}
+	want := time.Date(2024, 3, 15, 9, 30, 0, 0, time.UTC)
+	if !meta.CreatedAt.Equal(want) {
+		t.Errorf("CreatedAt = %v, want %v", meta.CreatedAt, want)
+	}
+}
+
+func TestReadMetadata_NoInfo(t *testing.T) {
+	path := writeTestPDF(t, t.TempDir(), "scan", 2)
+
+	meta, err := ReadMetadata(path)
+	if err != nil {
+		t.Fatalf("ReadMetadata failed: %v", err)
+	}
+	if meta.PageCount != 2 {
+		t.Errorf("PageCount = %d, want 2", meta.PageCount)
+	}
+	if meta.Title != "" || meta.Author != "" {
+		t.Errorf("Title, Author = %q, %q, want empty", meta.Title, meta.Author)
+	}
+}
@@ -0,0 +1,121 @@
+package pdf
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+)
+
+// ErrInvalidPages is returned for page selections that don't fit the document
+var ErrInvalidPages = errors.New("invalid page selection")
+
+// ParsePages parses a page selection such as "1,3-5" into page numbers
+// Pages must lie within 1..pageCount; order is kept and duplicates are dropped.
+func ParsePages(selection string, pageCount int) ([]int, error) {
+	var pages []int
+	seen := make(map[int]bool)
+	for _, part := range strings.Split(selection, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		first, last := part, part
+		if i := strings.Index(part, "-"); i >= 0 {
+			first, last = strings.TrimSpace(part[:i]), strings.TrimSpace(part[i+1:])
+		}
+		from, err := strconv.Atoi(first)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidPages, part)
+		}
+		to, err := strconv.Atoi(last)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidPages, part)
+		}
+		if from < 1 || to > pageCount || from > to {
+			return nil, fmt.Errorf("%w: %q is outside pages 1-%d", ErrInvalidPages, part, pageCount)
+		}
+
+		for p := from; p <= to; p++ {
+			if !seen[p] {
+				seen[p] = true
+				pages = append(pages, p)
+			}
+		}
+	}
+	if len(pages) == 0 {
+		return nil, fmt.Errorf("%w: no pages selected", ErrInvalidPages)
+	}
+	return pages, nil
+}
+
+// Rotate writes srcPath to dstPath with the given pages rotated clockwise
+// degrees must be a multiple of 90.
+func Rotate(srcPath, dstPath string, degrees int, pages []int) error {
+	if degrees%90 != 0 {
+		return fmt.Errorf("rotation must be a multiple of 90 degrees, got %d", degrees)
+	}
+	return writeVia(dstPath, func(tmp string) error {
+		return api.RotateFile(srcPath, tmp, degrees, selection(pages), nil)
+	})
+}
+
+// RemovePages writes srcPath to dstPath without the given pages
+func RemovePages(srcPath, dstPath string, pages []int) error {
+	return writeVia(dstPath, func(tmp string) error {
+		return api.RemovePagesFile(srcPath, tmp, selection(pages), nil)
+	})
+}
+
+// Reorder writes srcPath to dstPath with its pages in the given order
+// order must list every page exactly once.
+func Reorder(srcPath, dstPath string, order []int) error {
+	pageCount, err := PageCount(srcPath)
+	if err != nil {
+		return err
+	}
+	if len(order) != pageCount {
+		return fmt.Errorf("%w: order lists %d of %d pages", ErrInvalidPages, len(order), pageCount)
+	}
+	return writeVia(dstPath, func(tmp string) error {
+		return api.CollectFile(srcPath, tmp, selection(order), nil)
+	})
+}
+
+// Merge concatenates srcPaths, in order, into dstPath
+func Merge(dstPath string, srcPaths ...string) error {
+	if len(srcPaths) < 2 {
+		return fmt.Errorf("merge needs at least two documents")
+	}
+	return writeVia(dstPath, func(tmp string) error {
+		return api.MergeCreateFile(srcPaths, tmp, false, nil)
+	})
+}
+
+// selection converts page numbers to a pdfcpu page selection
+func selection(pages []int) []string {
+	sel := make([]string, len(pages))
+	for i, p := range pages {
+		sel[i] = strconv.Itoa(p)
+	}
+	return sel
+}
+
+// writeVia runs write against a temp file next to dstPath and renames it
+// into place, so a failed operation never leaves a partial PDF
+func writeVia(dstPath string, write func(tmp string) error) error {
+	if err := os.MkdirAll(filepath.Dir(dstPath), 0755); err != nil {
+		return fmt.Errorf("create output dir: %w", err)
+	}
+	tmp := dstPath + ".tmp"
+	if err := write(tmp); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, dstPath)
+}
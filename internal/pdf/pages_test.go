@@ -0,0 +1,112 @@
+package pdf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeTestPDF creates a PDF with the given number of pages
+func writeTestPDF(t *testing.T, dir, name string, pages int) string {
+	t.Helper()
+	var srcs []string
+	for i := 0; i < pages; i++ {
+		p := filepath.Join(dir, fmt.Sprintf("%s-%d.png", name, i))
+		writePNG(t, p)
+		srcs = append(srcs, p)
+	}
+	path := filepath.Join(dir, name+".pdf")
+	if err := FromImages(context.Background(), path, ".png", srcs...); err != nil {
+		t.Fatalf("FromImages failed: %v", err)
+	}
+	return path
+}
+
+// assertPageCount fails the test if path doesn't have want pages
+func assertPageCount(t *testing.T, path string, want int) {
+	t.Helper()
+	n, err := PageCount(path)
+	if err != nil {
+		t.Fatalf("PageCount failed: %v", err)
+	}
+	if n != want {
+		t.Errorf("page count = %d, want %d", n, want)
+	}
+}
+
+func TestParsePages(t *testing.T) {
+	tests := []struct {
+		selection string
+		want      []int
+		wantErr   bool
+	}{
+		{selection: "1", want: []int{1}},
+		{selection: "1,3-5", want: []int{1, 3, 4, 5}},
+		{selection: " 4 , 2 - 3 ", want: []int{4, 2, 3}},
+		{selection: "2,2,1-2", want: []int{2, 1}},
+		{selection: "", wantErr: true},
+		{selection: "0", wantErr: true},
+		{selection: "6", wantErr: true},
+		{selection: "3-2", wantErr: true},
+		{selection: "a", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.selection, func(t *testing.T) {
+			got, err := ParsePages(tt.selection, 5)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidPages) {
+					t.Errorf("ParsePages() error = %v, want ErrInvalidPages", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParsePages() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParsePages() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPageOperations(t *testing.T) {
+	tmpDir := t.TempDir()
+	src := writeTestPDF(t, tmpDir, "scan", 4)
+
+	rotated := filepath.Join(tmpDir, "out", "rotated.pdf")
+	if err := Rotate(src, rotated, 90, []int{1, 3}); err != nil {
+		t.Fatalf("Rotate failed: %v", err)
+	}
+	assertPageCount(t, rotated, 4)
+
+	if err := Rotate(src, rotated, 45, []int{1}); err == nil {
+		t.Error("Rotate should reject 45 degrees")
+	}
+
+	removed := filepath.Join(tmpDir, "removed.pdf")
+	if err := RemovePages(src, removed, []int{2, 4}); err != nil {
+		t.Fatalf("RemovePages failed: %v", err)
+	}
+	assertPageCount(t, removed, 2)
+
+	reordered := filepath.Join(tmpDir, "reordered.pdf")
+	if err := Reorder(src, reordered, []int{4, 3, 2, 1}); err != nil {
+		t.Fatalf("Reorder failed: %v", err)
+	}
+	assertPageCount(t, reordered, 4)
+
+	if err := Reorder(src, reordered, []int{2, 1}); !errors.Is(err, ErrInvalidPages) {
+		t.Errorf("Reorder with missing pages error = %v, want ErrInvalidPages", err)
+	}
+
+	other := writeTestPDF(t, tmpDir, "other", 2)
+	merged := filepath.Join(tmpDir, "merged.pdf")
+	if err := Merge(merged, src, other); err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	assertPageCount(t, merged, 6)
+}
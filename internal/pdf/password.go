@@ -0,0 +1,44 @@
+package pdf
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+// ErrWrongPassword is returned when a password doesn't open an encrypted PDF
+var ErrWrongPassword = errors.New("wrong password")
+
+// NeedsPassword reports whether a PDF can't be opened without a password
+// PDFs encrypted only to restrict permissions open without one.
+func NeedsPassword(path string) (bool, error) {
+	_, err := api.ReadContextFile(path)
+	if errors.Is(err, pdfcpu.ErrWrongPassword) {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("read pdf: %w", err)
+	}
+	return false, nil
+}
+
+// Decrypt writes an unencrypted copy of srcPath to dstPath
+// password may be the user (open) or owner password.
+func Decrypt(srcPath, dstPath, password string) error {
+	conf := model.NewDefaultConfiguration()
+	conf.UserPW = password
+	conf.OwnerPW = password
+	err := writeVia(dstPath, func(tmp string) error {
+		return api.DecryptFile(srcPath, tmp, conf)
+	})
+	if errors.Is(err, pdfcpu.ErrWrongPassword) {
+		return ErrWrongPassword
+	}
+	if err != nil {
+		return fmt.Errorf("decrypt pdf: %w", err)
+	}
+	return nil
+}
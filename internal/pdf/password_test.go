@@ -0,0 +1,65 @@
+package pdf
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+// writeEncryptedPDF encrypts a test PDF with the given user and owner passwords
+func writeEncryptedPDF(t *testing.T, dir, name, userPW, ownerPW string) string {
+	t.Helper()
+	src := writeTestPDF(t, dir, name+"-plain", 2)
+	path := filepath.Join(dir, name+".pdf")
+	if err := api.EncryptFile(src, path, model.NewAESConfiguration(userPW, ownerPW, 256)); err != nil {
+		t.Fatalf("EncryptFile failed: %v", err)
+	}
+	return path
+}
+
+func TestNeedsPassword(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name string
+		path string
+		want bool
+	}{
+		{"plain", writeTestPDF(t, dir, "plain", 1), false},
+		{"user password", writeEncryptedPDF(t, dir, "locked", "secret", "owner"), true},
+		{"permissions only", writeEncryptedPDF(t, dir, "restricted", "", "owner"), false},
+	}
+
+	for _, tt := range tests {
+		got, err := NeedsPassword(tt.path)
+		if err != nil {
+			t.Fatalf("%s: NeedsPassword failed: %v", tt.name, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: NeedsPassword = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDecrypt(t *testing.T) {
+	dir := t.TempDir()
+	locked := writeEncryptedPDF(t, dir, "locked", "secret", "owner")
+	dst := filepath.Join(dir, "out", "unlocked.pdf")
+
+	if err := Decrypt(locked, dst, "wrong"); !errors.Is(err, ErrWrongPassword) {
+		t.Fatalf("Decrypt with a wrong password = %v, want ErrWrongPassword", err)
+	}
+
+	for _, password := range []string{"secret", "owner"} {
+		if err := Decrypt(locked, dst, password); err != nil {
+			t.Fatalf("Decrypt(%q) failed: %v", password, err)
+		}
+		needs, err := NeedsPassword(dst)
+		if err != nil || needs {
+			t.Errorf("decrypted copy: NeedsPassword = %v, %v", needs, err)
+		}
+		assertPageCount(t, dst, 2)
+	}
+}
@@ -0,0 +1,120 @@
+package pdf
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+)
+
+func init() {
+	// pdfcpu otherwise creates a config directory under the user's home
+	api.DisableConfigDir()
+}
+
+// Image is an image file to convert, with its format as a file extension
+// (.jpg, .png, .tif or .heic)
+type Image struct {
+	Path string
+	Ext  string
+}
+
+// FromImages combines one or more images of the same format into a single PDF at dstPath
+// ext is the image format as a file extension (.jpg, .png, .tif or .heic).
+// Each image, and each frame of a multi-page TIFF, becomes a page sized to the image.
+// HEIC images are first converted to JPEG with heif-convert.
+func FromImages(ctx context.Context, dstPath, ext string, imagePaths ...string) error {
+	images := make([]Image, len(imagePaths))
+	for i, path := range imagePaths {
+		images[i] = Image{Path: path, Ext: ext}
+	}
+	return CombineImages(ctx, dstPath, images)
+}
+
+// CombineImages converts images, which may differ in format, into a single
+// PDF at dstPath with their pages in the order given, like FromImages
+func CombineImages(ctx context.Context, dstPath string, images []Image) error {
+	if len(images) == 0 {
+		return fmt.Errorf("no images to convert")
+	}
+
+	tmpDir, err := os.MkdirTemp("", "img2pdf-*")
+	if err != nil {
+		return fmt.Errorf("create temp dir: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	// pdfcpu picks the decoder from the file extension, so stage every
+	// image under a name matching its format
+	inputs := make([]string, 0, len(images))
+	for i, img := range images {
+		ext := strings.ToLower(img.Ext)
+		staged := filepath.Join(tmpDir, fmt.Sprintf("page-%03d", i))
+		if ext == ".heic" || ext == ".heif" {
+			staged += ".jpg"
+			if err := heicToJPEG(ctx, img.Path, staged); err != nil {
+				return err
+			}
+		} else {
+			staged += ext
+			if err := os.Symlink(img.Path, staged); err != nil {
+				return fmt.Errorf("stage image: %w", err)
+			}
+		}
+		inputs = append(inputs, staged)
+	}
+
+	// Build in the temp dir first so a failed conversion never leaves a partial PDF
+	tmpPDF := filepath.Join(tmpDir, "out.pdf")
+	if err := api.ImportImagesFile(inputs, tmpPDF, pdfcpu.DefaultImportConfig(), nil); err != nil {
+		return fmt.Errorf("import images: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dstPath), 0755); err != nil {
+		return fmt.Errorf("create output dir: %w", err)
+	}
+	if err := copyFile(tmpPDF, dstPath); err != nil {
+		return fmt.Errorf("write pdf: %w", err)
+	}
+	return nil
+}
+
+// heicToJPEG converts a HEIC image to JPEG using heif-convert
+func heicToJPEG(ctx context.Context, src, dst string) error {
+	convCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	cmd := exec.CommandContext(convCtx, "heif-convert", "-q", "90", src, dst)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("heif-convert failed: %w\noutput: %s", err, output)
+	}
+	return nil
+}
+
+// copyFile copies src to dst, replacing dst if it exists
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
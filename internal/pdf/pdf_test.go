@@ -0,0 +1,131 @@
+package pdf
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+)
+
+// writePNG creates a small solid-color PNG for conversion tests
+func writePNG(t *testing.T, path string) {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 120, 80))
+	for y := 0; y < 80; y++ {
+		for x := 0; x < 120; x++ {
+			img.Set(x, y, color.RGBA{R: 200, G: 100, B: 50, A: 255})
+		}
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("create png: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+	if err := png.Encode(f, img); err != nil {
+		t.Fatalf("encode png: %v", err)
+	}
+}
+
+func TestFromImages_SinglePage(t *testing.T) {
+	tmpDir := t.TempDir()
+	// Source names deliberately lack an image extension, like ingest temp files
+	src := filepath.Join(tmpDir, "upload-123")
+	writePNG(t, src)
+
+	dst := filepath.Join(tmpDir, "out", "doc.pdf")
+	if err := FromImages(context.Background(), dst, ".PNG", src); err != nil {
+		t.Fatalf("FromImages failed: %v", err)
+	}
+
+	n, err := api.PageCountFile(dst)
+	if err != nil {
+		t.Fatalf("PageCountFile failed: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("page count = %d, want 1", n)
+	}
+}
+
+func TestFromImages_MultipleImages(t *testing.T) {
+	tmpDir := t.TempDir()
+	var srcs []string
+	for _, name := range []string{"a.png", "b.png", "c.png"} {
+		p := filepath.Join(tmpDir, name)
+		writePNG(t, p)
+		srcs = append(srcs, p)
+	}
+
+	dst := filepath.Join(tmpDir, "combined.pdf")
+	if err := FromImages(context.Background(), dst, ".png", srcs...); err != nil {
+		t.Fatalf("FromImages failed: %v", err)
+	}
+
+	n, err := api.PageCountFile(dst)
+	if err != nil {
+		t.Fatalf("PageCountFile failed: %v", err)
+	}
+	if n != 3 {
+		t.Errorf("page count = %d, want 3", n)
+	}
+}
+
+func TestCombineImages_MixedFormats(t *testing.T) {
+	tmpDir := t.TempDir()
+	var images []Image
+	for i, ext := range []string{".png", ".jpg", ".png", ".jpg"} {
+		p := filepath.Join(tmpDir, fmt.Sprintf("upload-%d", i))
+		if ext == ".png" {
+			writePNG(t, p)
+		} else {
+			writeJPEG(t, p)
+		}
+		images = append(images, Image{Path: p, Ext: ext})
+	}
+
+	dst := filepath.Join(tmpDir, "combined.pdf")
+	if err := CombineImages(context.Background(), dst, images); err != nil {
+		t.Fatalf("CombineImages failed: %v", err)
+	}
+
+	n, err := api.PageCountFile(dst)
+	if err != nil {
+		t.Fatalf("PageCountFile failed: %v", err)
+	}
+	if n != 4 {
+		t.Errorf("page count = %d, want 4", n)
+	}
+}
+
+// writeJPEG creates a small grey JPEG for conversion tests
+func writeJPEG(t *testing.T, path string) {
+	t.Helper()
+	img := image.NewGray(image.Rect(0, 0, 80, 120))
+	for i := range img.Pix {
+		img.Pix[i] = 180
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("create jpeg: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+	if err := jpeg.Encode(f, img, nil); err != nil {
+		t.Fatalf("encode jpeg: %v", err)
+	}
+}
+
+func TestFromImages_NoImages(t *testing.T) {
+	dst := filepath.Join(t.TempDir(), "empty.pdf")
+	if err := FromImages(context.Background(), dst, ".png"); err == nil {
+		t.Error("expected error for empty image list")
+	}
+	if _, err := os.Stat(dst); !os.IsNotExist(err) {
+		t.Error("no output should be written on failure")
+	}
+}
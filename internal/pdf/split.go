@@ -0,0 +1,50 @@
+package pdf
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+)
+
+// PageRange is an inclusive, 1-based range of pages
+type PageRange struct {
+	First int
+	Last  int
+}
+
+// String returns the range in pdfcpu page selection syntax, e.g. "3-5"
+func (r PageRange) String() string {
+	if r.First == r.Last {
+		return fmt.Sprintf("%d", r.First)
+	}
+	return fmt.Sprintf("%d-%d", r.First, r.Last)
+}
+
+// PageCount returns the number of pages in a PDF
+func PageCount(path string) (int, error) {
+	n, err := api.PageCountFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("count pages: %w", err)
+	}
+	return n, nil
+}
+
+// Split writes each page range of srcPath to its own PDF in dstDir
+// Returns the output paths in range order, named part-001.pdf, part-002.pdf, ...
+func Split(srcPath, dstDir string, ranges []PageRange) ([]string, error) {
+	if err := os.MkdirAll(dstDir, 0755); err != nil {
+		return nil, fmt.Errorf("create output dir: %w", err)
+	}
+
+	paths := make([]string, 0, len(ranges))
+	for i, r := range ranges {
+		out := filepath.Join(dstDir, fmt.Sprintf("part-%03d.pdf", i+1))
+		if err := api.TrimFile(srcPath, out, []string{r.String()}, nil); err != nil {
+			return nil, fmt.Errorf("extract pages %s: %w", r, err)
+		}
+		paths = append(paths, out)
+	}
+	return paths, nil
+}
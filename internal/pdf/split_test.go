@@ -0,0 +1,48 @@
+package pdf
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestPageRangeString(t *testing.T) {
+	if got := (PageRange{First: 3, Last: 5}).String(); got != "3-5" {
+		t.Errorf("String() = %q, want 3-5", got)
+	}
+	if got := (PageRange{First: 2, Last: 2}).String(); got != "2" {
+		t.Errorf("String() = %q, want 2", got)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	tmpDir := t.TempDir()
+	var srcs []string
+	for _, name := range []string{"1.png", "2.png", "3.png", "4.png", "5.png"} {
+		p := filepath.Join(tmpDir, name)
+		writePNG(t, p)
+		srcs = append(srcs, p)
+	}
+	scan := filepath.Join(tmpDir, "scan.pdf")
+	if err := FromImages(context.Background(), scan, ".png", srcs...); err != nil {
+		t.Fatalf("FromImages failed: %v", err)
+	}
+
+	paths, err := Split(scan, filepath.Join(tmpDir, "parts"), []PageRange{{1, 2}, {4, 5}, {3, 3}})
+	if err != nil {
+		t.Fatalf("Split failed: %v", err)
+	}
+	if len(paths) != 3 {
+		t.Fatalf("Split returned %d parts, want 3", len(paths))
+	}
+
+	for i, want := range []int{2, 2, 1} {
+		n, err := PageCount(paths[i])
+		if err != nil {
+			t.Fatalf("PageCount(%s) failed: %v", paths[i], err)
+		}
+		if n != want {
+			t.Errorf("part %d page count = %d, want %d", i+1, n, want)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package processing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/bketelsen/docko/internal/database/sqlc"
+	"github.com/bketelsen/docko/internal/document"
+	"github.com/bketelsen/docko/internal/email"
+)
+
+// sofficeTimeout bounds a single LibreOffice conversion
+const sofficeTimeout = 5 * time.Minute
+
+// pdfExportFilters maps office content types to a LibreOffice PDF export filter
+// that writes PDF/A-2b (SelectPdfVersion 2) for archival
+var pdfExportFilters = map[string]string{
+	document.ContentTypeDOCX: `pdf:writer_pdf_Export:{"SelectPdfVersion":{"type":"long","value":"2"}}`,
+	document.ContentTypeODT:  `pdf:writer_pdf_Export:{"SelectPdfVersion":{"type":"long","value":"2"}}`,
+	document.ContentTypeRTF:  `pdf:writer_pdf_Export:{"SelectPdfVersion":{"type":"long","value":"2"}}`,
+	document.ContentTypeXLSX: `pdf:calc_pdf_Export:{"SelectPdfVersion":{"type":"long","value":"2"}}`,
+	document.ContentTypeODS:  `pdf:calc_pdf_Export:{"SelectPdfVersion":{"type":"long","value":"2"}}`,
+	document.ContentTypeEML:  `pdf:writer_web_pdf_Export:{"SelectPdfVersion":{"type":"long","value":"2"}}`,
+}
+
+// OfficeConversionAvailable reports whether LibreOffice is installed for office conversion
+func OfficeConversionAvailable() bool {
+	_, err := exec.LookPath("soffice")
+	return err == nil
+}
+
+// HandleConvertJob converts an office or email original to a PDF rendition (implements queue.JobHandler)
+// On success it queues the regular processing job for the rendition.
+func (p *Processor) HandleConvertJob(ctx context.Context, job *sqlc.Job) error {
+	var payload document.IngestPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return fmt.Errorf("unmarshal payload: %w", err)
+	}
+
+	docID := payload.DocumentID
+	start := time.Now()
+
+	slog.Info("converting document",
+		"doc_id", docID,
+		"job_id", job.ID,
+		"attempt", job.Attempt)
+
+	doc, err := p.docSvc.GetByID(ctx, docID)
+	if err != nil {
+		return fmt.Errorf("get document: %w", err)
+	}
+
+	_, err = p.db.Queries.SetDocumentProcessingStatus(ctx, sqlc.SetDocumentProcessingStatusParams{
+		ID:               docID,
+		ProcessingStatus: sqlc.ProcessingStatusProcessing,
+	})
+	if err != nil {
+		return fmt.Errorf("set processing status: %w", err)
+	}
+
+	p.updateStep(ctx, job.ID, docID, StepConverting)
+
+	renditionKey := p.docSvc.RenditionKey(doc)
+	if err := p.convert(ctx, doc, renditionKey); err != nil {
+		if job.Attempt >= job.MaxAttempts {
+			return p.quarantine(ctx, docID, fmt.Sprintf("conversion failed: %v", err))
+		}
+		return fmt.Errorf("convert document: %w", err)
+	}
+
+	if err := p.docSvc.LogEvent(ctx, docID, document.EventConverted, map[string]any{
+		"content_type":   doc.ContentType,
+		"rendition_path": renditionKey,
+	}, nil, time.Since(start)); err != nil {
+		slog.Warn("failed to log conversion event", "doc_id", docID, "error", err)
+	}
+
+	// Hand the rendition to the regular processing pipeline
+	payloadJSON, err := json.Marshal(document.IngestPayload{DocumentID: docID, Text: payload.Text, Split: payload.Split})
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+	_, err = p.db.Queries.EnqueueJob(ctx, sqlc.EnqueueJobParams{
+		QueueName: document.QueueDefault,
+		JobType:   document.JobTypeProcess,
+		Payload:   payloadJSON,
+	})
+	if err != nil {
+		return fmt.Errorf("enqueue processing: %w", err)
+	}
+
+	slog.Info("document converted",
+		"doc_id", docID,
+		"content_type", doc.ContentType,
+		"duration_ms", time.Since(start).Milliseconds())
+
+	return nil
+}
+
+// convert renders a document's original to PDF and stores it under renditionKey
+func (p *Processor) convert(ctx context.Context, doc *sqlc.Document, renditionKey string) error {
+	original, release, err := p.store.Fetch(ctx, p.docSvc.OriginalKey(doc))
+	if err != nil {
+		return fmt.Errorf("fetch original: %w", err)
+	}
+	defer release()
+
+	tmpDir, err := os.MkdirTemp("", "rendition-*")
+	if err != nil {
+		return fmt.Errorf("create temp dir: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	rendition := filepath.Join(tmpDir, "rendition.pdf")
+	if err := convertToPDF(ctx, original, rendition, doc.ContentType); err != nil {
+		return err
+	}
+	if err := p.store.Store(ctx, renditionKey, rendition); err != nil {
+		return fmt.Errorf("store rendition: %w", err)
+	}
+	return nil
+}
+
+// convertToPDF renders an office document or email to PDF/A with headless LibreOffice
+func convertToPDF(ctx context.Context, srcPath, dstPath, contentType string) error {
+	filter, ok := pdfExportFilters[contentType]
+	if !ok {
+		return fmt.Errorf("no pdf export filter for %s", contentType)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "convert-*")
+	if err != nil {
+		return fmt.Errorf("create temp dir: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	// LibreOffice names its output after the input, so stage the original
+	// under a fixed name with the canonical extension
+	var input string
+	if contentType == document.ContentTypeEML {
+		input, err = stageEmail(srcPath, tmpDir)
+		if err != nil {
+			return err
+		}
+	} else {
+		input = filepath.Join(tmpDir, "input"+document.OfficeExtension(contentType))
+		if err := copyFile(srcPath, input); err != nil {
+			return fmt.Errorf("stage original: %w", err)
+		}
+	}
+
+	convCtx, cancel := context.WithTimeout(ctx, sofficeTimeout)
+	defer cancel()
+
+	// A private profile directory lets conversions run concurrently
+	cmd := exec.CommandContext(convCtx, "soffice",
+		"--headless",
+		"--norestore",
+		"--nolockcheck",
+		"-env:UserInstallation=file://"+filepath.Join(tmpDir, "profile"),
+		"--convert-to", filter,
+		"--outdir", tmpDir,
+		input,
+	)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("soffice failed: %w\noutput: %s", err, output)
+	}
+
+	outPath := filepath.Join(tmpDir, "input.pdf")
+	if _, err := os.Stat(outPath); err != nil {
+		return fmt.Errorf("soffice produced no output: %s", output)
+	}
+
+	if err := copyFile(outPath, dstPath); err != nil {
+		return fmt.Errorf("write rendition: %w", err)
+	}
+	return nil
+}
+
+// stageEmail renders an email as an HTML page for LibreOffice to print
+func stageEmail(srcPath, tmpDir string) (string, error) {
+	msg, err := email.ParseFile(srcPath)
+	if err != nil {
+		return "", fmt.Errorf("parse email: %w", err)
+	}
+	input := filepath.Join(tmpDir, "input.html")
+	if err := os.WriteFile(input, []byte(msg.RenderHTML()), 0644); err != nil {
+		return "", fmt.Errorf("stage email: %w", err)
+	}
+	return input, nil
+}
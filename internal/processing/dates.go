@@ -0,0 +1,298 @@
+package processing
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// DateAutoApplyConfidence is the confidence at or above which a detected
+// date is set as the document date; less certain dates go to the review queue
+const DateAutoApplyConfidence = 0.7
+
+// Date detection scoring
+const (
+	// Base confidence by how unambiguous the date format is
+	dateScoreNamedMonth = 0.5 // "15 March 2024", "March 15, 2024"
+	dateScoreISO        = 0.5 // "2024-03-15"
+	dateScoreNumeric    = 0.4 // "15.03.2024", "03/15/2024" with day > 12
+	dateScoreAmbiguous  = 0.3 // "03/04/2024", read month first
+
+	// dateMaxAmbiguous caps an ambiguous date's score below
+	// DateAutoApplyConfidence, labelled or not, so it always goes to review
+	dateMaxAmbiguous = 0.6
+
+	// Bonus when the date follows a label like "Invoice date"
+	dateScoreLabel = 0.4
+	// Bonus when the date follows a bare "Date:" label
+	dateScoreGenericLabel = 0.3
+	// Penalty when the date follows a label like "Due date"
+	dateScoreWrongLabel = 0.3
+	// Bonus for each further occurrence of the same date
+	dateScoreRepeat = 0.05
+
+	// labelWindow is how far before a date a label may appear
+	labelWindow = 40
+	// maxDateSearch bounds how much text is scanned
+	maxDateSearch = 20000
+)
+
+// DetectedDate is the most likely document date found in extracted text
+type DetectedDate struct {
+	Date       time.Time `json:"date"`
+	Confidence float64   `json:"confidence"`      // 0-1
+	Label      string    `json:"label,omitempty"` // Label the date followed, if any
+}
+
+// dateLabels name the date a document was issued, in English, German,
+// French, Spanish and Dutch; they're matched lower-cased
+var dateLabels = []string{
+	"invoice date", "statement date", "date of issue", "issue date", "issued on",
+	"bill date", "billing date", "receipt date", "order date", "letter date", "dated",
+	"rechnungsdatum", "ausstellungsdatum", "belegdatum",
+	"date de facture", "date d'émission", "date de facturation",
+	"fecha de factura", "fecha de emisión", "fecha de expedición",
+	"factuurdatum",
+}
+
+// genericDateLabels are bare "date" labels
+var genericDateLabels = []string{"date", "datum", "fecha", "le"}
+
+// wrongDateLabels name dates that aren't the document date
+var wrongDateLabels = []string{
+	"due", "due date", "payment date", "payable by", "pay by", "delivery", "delivery date",
+	"expires", "expiry", "expiry date", "expiration", "valid until", "period", "from", "to",
+	"through", "birth", "born", "shipped",
+	"fällig", "fälligkeitsdatum", "lieferdatum", "zahlbar bis", "gültig bis",
+	"échéance", "vencimiento", "vervaldatum",
+}
+
+// monthNames maps lower-case month names and abbreviations to months
+var monthNames = map[string]time.Month{}
+
+func init() {
+	names := [][]string{
+		{"january", "jan", "januar", "jänner", "janvier", "janv", "enero", "ene", "januari"},
+		{"february", "feb", "februar", "février", "févr", "fevrier", "febrero", "februari"},
+		{"march", "mar", "märz", "maerz", "mars", "marzo", "maart", "mrt"},
+		{"april", "apr", "avril", "avr", "abril", "abr"},
+		{"may", "mai", "mayo", "mei"},
+		{"june", "jun", "juni", "juin", "junio"},
+		{"july", "jul", "juli", "juillet", "juil", "julio"},
+		{"august", "aug", "août", "aout", "agosto", "ago", "augustus"},
+		{"september", "sep", "sept", "septembre", "septiembre", "setiembre"},
+		{"october", "oct", "oktober", "okt", "octobre", "octubre"},
+		{"november", "nov", "novembre", "noviembre"},
+		{"december", "dec", "dezember", "dez", "décembre", "decembre", "diciembre", "dic"},
+	}
+	for i, aliases := range names {
+		for _, name := range aliases {
+			monthNames[name] = time.Month(i + 1)
+		}
+	}
+}
+
+var (
+	// 2024-03-15, 2024/03/15, 2024.03.15
+	isoDatePattern = regexp.MustCompile(`\b(\d{4})[-/.](\d{1,2})[-/.](\d{1,2})\b`)
+	// 15.03.2024, 03/15/2024, 15-03-24
+	numericDatePattern = regexp.MustCompile(`\b(\d{1,2})([./-])(\d{1,2})([./-])(\d{4}|\d{2})\b`)
+	// 15 March 2024, 15. März 2024, 15 de marzo de 2024, 1er mars 2024
+	dayMonthPattern = regexp.MustCompile(`(?i)\b(\d{1,2})(?:st|nd|rd|th|er)?\.?\s+(?:de\s+)?(\p{L}{3,10})\.?,?\s+(?:de\s+)?(\d{4})\b`)
+	// March 15, 2024, Mar 15th 2024
+	monthDayPattern = regexp.MustCompile(`(?i)\b(\p{L}{3,10})\.?\s+(\d{1,2})(?:st|nd|rd|th)?,?\s+(\d{4})\b`)
+)
+
+// dateCandidate is one date found in the text
+type dateCandidate struct {
+	date      time.Time
+	pos       int
+	score     float64
+	label     string
+	ambiguous bool // Day and month order unknown
+}
+
+// DetectDate finds the most likely document date in extracted text
+// Dates after now or before 1900 are ignored. Returns false if no date was found.
+func DetectDate(text string, now time.Time) (DetectedDate, bool) {
+	if len(text) > maxDateSearch {
+		text = text[:maxDateSearch]
+	}
+
+	var candidates []dateCandidate
+	add := func(pos int, year, month, day int, score float64) bool {
+		if year < 100 {
+			year += 2000
+			if year > now.Year() {
+				year -= 100
+			}
+		}
+		if month < 1 || month > 12 || day < 1 || day > 31 {
+			return false
+		}
+		d := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+		// time.Date normalizes out-of-range days, e.g. 31 February
+		if d.Day() != day || d.Year() < 1900 || d.After(now) {
+			return false
+		}
+		candidates = append(candidates, dateCandidate{date: d, pos: pos, score: score})
+		return true
+	}
+
+	for _, m := range isoDatePattern.FindAllStringSubmatchIndex(text, -1) {
+		add(m[0], atoi(text[m[2]:m[3]]), atoi(text[m[4]:m[5]]), atoi(text[m[6]:m[7]]), dateScoreISO)
+	}
+
+	numeric := numericDatePattern.FindAllStringSubmatchIndex(text, -1)
+	dayFirst, monthFirst := numericDateOrder(text, numeric)
+	for _, m := range numeric {
+		// Mixed separators ("15.03/2024") are more likely not a date
+		if text[m[4]:m[5]] != text[m[8]:m[9]] {
+			continue
+		}
+		a, b := atoi(text[m[2]:m[3]]), atoi(text[m[6]:m[7]])
+		year := atoi(text[m[10]:m[11]])
+		switch {
+		case a > 12:
+			add(m[0], year, b, a, dateScoreNumeric)
+		case b > 12:
+			add(m[0], year, a, b, dateScoreNumeric)
+		case text[m[4]:m[5]] == ".":
+			// Dotted dates are day first across Europe
+			add(m[0], year, b, a, dateScoreNumeric)
+		case a == b:
+			add(m[0], year, a, b, dateScoreNumeric)
+		case dayFirst && !monthFirst:
+			// Other dates in the text settle the order
+			add(m[0], year, b, a, dateScoreNumeric)
+		case monthFirst && !dayFirst:
+			add(m[0], year, a, b, dateScoreNumeric)
+		default:
+			if add(m[0], year, a, b, dateScoreAmbiguous) {
+				candidates[len(candidates)-1].ambiguous = true
+			}
+		}
+	}
+
+	for _, m := range dayMonthPattern.FindAllStringSubmatchIndex(text, -1) {
+		if month, ok := monthNames[strings.ToLower(text[m[4]:m[5]])]; ok {
+			add(m[0], atoi(text[m[6]:m[7]]), int(month), atoi(text[m[2]:m[3]]), dateScoreNamedMonth)
+		}
+	}
+
+	for _, m := range monthDayPattern.FindAllStringSubmatchIndex(text, -1) {
+		if month, ok := monthNames[strings.ToLower(text[m[2]:m[3]])]; ok {
+			add(m[0], atoi(text[m[6]:m[7]]), int(month), atoi(text[m[4]:m[5]]), dateScoreNamedMonth)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return DetectedDate{}, false
+	}
+
+	counts := make(map[time.Time]int)
+	for _, c := range candidates {
+		counts[c.date]++
+	}
+	for i := range candidates {
+		c := &candidates[i]
+		before := strings.ToLower(text[max(0, c.pos-labelWindow):c.pos])
+		c.label, c.score = scoreLabel(before, c.score)
+		c.score += dateScoreRepeat * float64(counts[c.date]-1)
+		if c.ambiguous {
+			c.score = min(c.score, dateMaxAmbiguous)
+		}
+	}
+
+	best := candidates[0]
+	for _, c := range candidates[1:] {
+		// On a tie the earliest date in the text wins
+		if c.score > best.score || (c.score == best.score && c.pos < best.pos) {
+			best = c
+		}
+	}
+
+	return DetectedDate{
+		Date:       best.date,
+		Confidence: min(1, max(0, best.score)),
+		Label:      best.label,
+	}, true
+}
+
+// numericDateOrder reports whether the slash and dash dates in the text that
+// can only be read one way put the day first, the month first, or both
+func numericDateOrder(text string, matches [][]int) (dayFirst, monthFirst bool) {
+	for _, m := range matches {
+		sep := text[m[4]:m[5]]
+		if sep == "." || sep != text[m[8]:m[9]] {
+			continue
+		}
+		a, b := atoi(text[m[2]:m[3]]), atoi(text[m[6]:m[7]])
+		switch {
+		case a > 12 && a <= 31 && b <= 12:
+			dayFirst = true
+		case b > 12 && b <= 31 && a <= 12:
+			monthFirst = true
+		}
+	}
+	return dayFirst, monthFirst
+}
+
+// scoreLabel adjusts a date's score for the label closest before it
+// Where labels end at the same place ("invoice date", "date"), the longest wins.
+func scoreLabel(before string, score float64) (string, float64) {
+	label, kind, end := "", 0, -1
+	find := func(words []string, k int) {
+		for _, w := range words {
+			i := lastWordIndex(before, w)
+			if i < 0 {
+				continue
+			}
+			if e := i + len(w); e > end || (e == end && len(w) > len(label)) {
+				label, kind, end = w, k, e
+			}
+		}
+	}
+	find(genericDateLabels, 1)
+	find(dateLabels, 2)
+	find(wrongDateLabels, 3)
+
+	switch kind {
+	case 1:
+		return label, score + dateScoreGenericLabel
+	case 2:
+		return label, score + dateScoreLabel
+	case 3:
+		return label, score - dateScoreWrongLabel
+	}
+	return "", score
+}
+
+// lastWordIndex returns the index of the last whole-word occurrence of word in s, or -1
+func lastWordIndex(s, word string) int {
+	for end := len(s); end > 0; {
+		i := strings.LastIndex(s[:end], word)
+		if i < 0 {
+			return -1
+		}
+		startOK := i == 0 || !isWordRune(rune(s[i-1]))
+		after := i + len(word)
+		endOK := after == len(s) || !isWordRune(rune(s[after]))
+		if startOK && endOK {
+			return i
+		}
+		end = i
+	}
+	return -1
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
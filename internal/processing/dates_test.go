@@ -0,0 +1,131 @@
+package processing
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDetectDate(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		text     string
+		want     string
+		wantAuto bool
+	}{
+		{
+			name:     "labelled invoice date wins over due date",
+			text:     "ACME Corp\nInvoice date: 03/15/2024\nDue date: 04/14/2024\nTotal $120.00",
+			want:     "2024-03-15",
+			wantAuto: true,
+		},
+		{
+			name:     "due date alone is low confidence",
+			text:     "Payment due 2024-04-14",
+			want:     "2024-04-14",
+			wantAuto: false,
+		},
+		{
+			name:     "named month",
+			text:     "Statement date: March 5, 2024\nOpening balance",
+			want:     "2024-03-05",
+			wantAuto: true,
+		},
+		{
+			name:     "german dotted date",
+			text:     "Rechnungsdatum: 15.03.2024\nFällig: 29.03.2024",
+			want:     "2024-03-15",
+			wantAuto: true,
+		},
+		{
+			name:     "german month name",
+			text:     "Berlin, den 2. März 2024",
+			want:     "2024-03-02",
+			wantAuto: false,
+		},
+		{
+			name:     "french date",
+			text:     "Date de facture : 7 février 2024",
+			want:     "2024-02-07",
+			wantAuto: true,
+		},
+		{
+			name:     "spanish date",
+			text:     "Fecha de emisión: 9 de octubre de 2023",
+			want:     "2023-10-09",
+			wantAuto: true,
+		},
+		{
+			name:     "day first when unambiguous",
+			text:     "Date: 25/12/2023",
+			want:     "2023-12-25",
+			wantAuto: true,
+		},
+		{
+			name:     "ambiguous slash date reads month first",
+			text:     "03/04/2024",
+			want:     "2024-03-04",
+			wantAuto: false,
+		},
+		{
+			name:     "labelled ambiguous slash date still needs review",
+			text:     "Invoice date: 03/04/2024",
+			want:     "2024-03-04",
+			wantAuto: false,
+		},
+		{
+			name:     "day first order from another date in the text",
+			text:     "Invoice date: 03/04/2024\nDue date: 17/04/2024",
+			want:     "2024-04-03",
+			wantAuto: true,
+		},
+		{
+			name:     "month first order from another date in the text",
+			text:     "Invoice date: 03/04/2024\nDue date: 04/17/2024",
+			want:     "2024-03-04",
+			wantAuto: true,
+		},
+		{
+			name:     "two digit year",
+			text:     "Dated 15.01.99",
+			want:     "1999-01-15",
+			wantAuto: true,
+		},
+		{
+			name:     "future dates are ignored",
+			text:     "Valid from 2024-01-10 until 2030-01-01",
+			want:     "2024-01-10",
+			wantAuto: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := DetectDate(tt.text, now)
+			if !ok {
+				t.Fatal("DetectDate() found no date")
+			}
+			if d := got.Date.Format("2006-01-02"); d != tt.want {
+				t.Errorf("DetectDate() date = %s, want %s", d, tt.want)
+			}
+			if auto := got.Confidence >= DateAutoApplyConfidence; auto != tt.wantAuto {
+				t.Errorf("DetectDate() confidence = %.2f, auto-apply = %v, want %v", got.Confidence, auto, tt.wantAuto)
+			}
+		})
+	}
+}
+
+func TestDetectDate_NoDate(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	for _, text := range []string{
+		"",
+		"No dates in here, just 42 apples and 7 pears",
+		"Order 12.34.56, phone 555-1234",
+		"31.02.2024",
+	} {
+		if got, ok := DetectDate(text, now); ok {
+			t.Errorf("DetectDate(%q) = %v, want none", text, got.Date)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package processing
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"image"
+	"image/png"
+	"math/bits"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Near-duplicate thresholds, as Hamming distances between 64-bit fingerprints
+const (
+	// textDistanceThreshold matches re-saved PDFs whose text is (almost) unchanged
+	textDistanceThreshold = 3
+	// looseTextDistanceThreshold matches rescans whose OCR text differs slightly,
+	// provided the first page also looks the same
+	looseTextDistanceThreshold = 10
+	// imageDistanceThreshold is the first page difference hash distance
+	// for documents that look the same
+	imageDistanceThreshold = 5
+	// imageOnlyDistanceThreshold applies when there is no text to compare
+	imageOnlyDistanceThreshold = 2
+)
+
+// shingleSize is the number of words per text shingle
+const shingleSize = 3
+
+// minFingerprintWords is the minimum word count for a meaningful text simhash
+const minFingerprintWords = 10
+
+// Fingerprint holds the similarity hashes of a document
+// A nil hash means it could not be computed
+type Fingerprint struct {
+	TextSimhash *int64
+	ImageHash   *int64
+}
+
+// Match is a near-duplicate comparison result
+type Match struct {
+	TextDistance  *int32
+	ImageDistance *int32
+}
+
+// SimHash computes a 64-bit simhash over the word shingles of text
+// Returns false if the text has too few words to fingerprint
+func SimHash(text string) (uint64, bool) {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) < minFingerprintWords {
+		return 0, false
+	}
+
+	var weights [64]int
+	for i := 0; i+shingleSize <= len(words); i++ {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(strings.Join(words[i:i+shingleSize], " ")))
+		sum := h.Sum64()
+		for bit := 0; bit < 64; bit++ {
+			if sum&(1<<bit) != 0 {
+				weights[bit]++
+			} else {
+				weights[bit]--
+			}
+		}
+	}
+
+	var hash uint64
+	for bit := 0; bit < 64; bit++ {
+		if weights[bit] > 0 {
+			hash |= 1 << bit
+		}
+	}
+	return hash, true
+}
+
+// DHash computes a 64-bit difference hash of an image
+// The image is reduced to a 9x8 grayscale grid and each bit records
+// whether a cell is brighter than its right-hand neighbour
+func DHash(img image.Image) uint64 {
+	const cols, rows = 9, 8
+
+	bounds := img.Bounds()
+	var grid [rows][cols]float64
+	for y := 0; y < rows; y++ {
+		y0 := bounds.Min.Y + y*bounds.Dy()/rows
+		y1 := max(bounds.Min.Y+(y+1)*bounds.Dy()/rows, y0+1)
+		for x := 0; x < cols; x++ {
+			x0 := bounds.Min.X + x*bounds.Dx()/cols
+			x1 := max(bounds.Min.X+(x+1)*bounds.Dx()/cols, x0+1)
+
+			var sum float64
+			var n int
+			for py := y0; py < y1 && py < bounds.Max.Y; py++ {
+				for px := x0; px < x1 && px < bounds.Max.X; px++ {
+					r, g, b, _ := img.At(px, py).RGBA()
+					sum += 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)
+					n++
+				}
+			}
+			if n > 0 {
+				grid[y][x] = sum / float64(n)
+			}
+		}
+	}
+
+	var hash uint64
+	for y := 0; y < rows; y++ {
+		for x := 0; x < cols-1; x++ {
+			if grid[y][x] > grid[y][x+1] {
+				hash |= 1 << (y*(cols-1) + x)
+			}
+		}
+	}
+	return hash
+}
+
+// PageHash renders the first page of a PDF and returns its difference hash
+func PageHash(ctx context.Context, pdfPath string) (uint64, error) {
+	tmpDir, err := os.MkdirTemp("", "pagehash-*")
+	if err != nil {
+		return 0, fmt.Errorf("create temp dir: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	renderCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	pngPrefix := filepath.Join(tmpDir, "page")
+	cmd := exec.CommandContext(renderCtx, "pdftoppm",
+		"-png",
+		"-gray",
+		"-f", "1",
+		"-singlefile",
+		"-scale-to", "128",
+		pdfPath,
+		pngPrefix,
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return 0, fmt.Errorf("pdftoppm failed: %w: %s", err, string(output))
+	}
+
+	f, err := os.Open(pngPrefix + ".png")
+	if err != nil {
+		return 0, fmt.Errorf("open page image: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	img, err := png.Decode(f)
+	if err != nil {
+		return 0, fmt.Errorf("decode page image: %w", err)
+	}
+	return DHash(img), nil
+}
+
+// Compare reports whether two fingerprints belong to likely duplicates
+func Compare(a, b Fingerprint) (Match, bool) {
+	var m Match
+	if a.TextSimhash != nil && b.TextSimhash != nil {
+		m.TextDistance = hammingDistance(*a.TextSimhash, *b.TextSimhash)
+	}
+	if a.ImageHash != nil && b.ImageHash != nil {
+		m.ImageDistance = hammingDistance(*a.ImageHash, *b.ImageHash)
+	}
+
+	switch {
+	case m.TextDistance != nil && m.ImageDistance != nil:
+		if *m.TextDistance <= textDistanceThreshold {
+			return m, true
+		}
+		return m, *m.TextDistance <= looseTextDistanceThreshold && *m.ImageDistance <= imageDistanceThreshold
+	case m.TextDistance != nil:
+		return m, *m.TextDistance <= textDistanceThreshold
+	case m.ImageDistance != nil:
+		// Only compare on looks when neither document has text
+		if a.TextSimhash != nil || b.TextSimhash != nil {
+			return m, false
+		}
+		return m, *m.ImageDistance <= imageOnlyDistanceThreshold
+	}
+	return m, false
+}
+
+// hammingDistance counts the differing bits of two hashes
+func hammingDistance(a, b int64) *int32 {
+	d := int32(bits.OnesCount64(uint64(a) ^ uint64(b)))
+	return &d
+}
@@ -0,0 +1,134 @@
+package processing
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+const invoiceText = `Invoice 2026-0142 from Acme Supplies for office chairs and desks.
+Payment is due within thirty days of the invoice date. Please reference the
+invoice number on your remittance. Thank you for your business.`
+
+func TestSimHash(t *testing.T) {
+	a, ok := SimHash(invoiceText)
+	if !ok {
+		t.Fatal("SimHash() ok = false for long text")
+	}
+
+	// Same words, different whitespace and case
+	b, _ := SimHash("INVOICE 2026-0142   from Acme Supplies for office chairs and desks. " +
+		"Payment is due within thirty days of the invoice date. Please reference the " +
+		"invoice number on your remittance. Thank you for your business.")
+	if a != b {
+		t.Errorf("SimHash() differs for reformatted text: %x vs %x", a, b)
+	}
+
+	// One word changed (OCR noise)
+	c, _ := SimHash(invoiceText[:len(invoiceText)-10] + "businesss.")
+	if d := *hammingDistance(int64(a), int64(c)); d > looseTextDistanceThreshold {
+		t.Errorf("distance after one word changed = %d, want <= %d", d, looseTextDistanceThreshold)
+	}
+
+	// Unrelated text
+	u, _ := SimHash("The quarterly board meeting minutes record the approval of the new budget, " +
+		"the election of a treasurer and a motion to renovate the community garden next spring.")
+	if d := *hammingDistance(int64(a), int64(u)); d <= textDistanceThreshold {
+		t.Errorf("distance to unrelated text = %d, want > %d", d, textDistanceThreshold)
+	}
+
+	if _, ok := SimHash("too few words"); ok {
+		t.Error("SimHash() ok = true for short text")
+	}
+}
+
+// gradientImage returns a grayscale image whose brightness falls from left to right
+func gradientImage(w, h int, invert bool) image.Image {
+	img := image.NewGray(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := uint8(255 - x*255/w)
+			if invert {
+				v = 255 - v
+			}
+			img.SetGray(x, y, color.Gray{Y: v})
+		}
+	}
+	return img
+}
+
+func TestDHash(t *testing.T) {
+	// Brighter on the left: every cell is brighter than its neighbour
+	if got := DHash(gradientImage(128, 160, false)); got != ^uint64(0) {
+		t.Errorf("DHash(falling gradient) = %x, want all bits set", got)
+	}
+	if got := DHash(gradientImage(128, 160, true)); got != 0 {
+		t.Errorf("DHash(rising gradient) = %x, want 0", got)
+	}
+
+	// Scale doesn't matter
+	if DHash(gradientImage(64, 80, false)) != DHash(gradientImage(300, 400, false)) {
+		t.Error("DHash() differs between scales of the same image")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	hash := func(v int64) *int64 { return &v }
+
+	tests := []struct {
+		name string
+		a, b Fingerprint
+		want bool
+	}{
+		{
+			name: "identical text",
+			a:    Fingerprint{TextSimhash: hash(0x0f0f)},
+			b:    Fingerprint{TextSimhash: hash(0x0f0f)},
+			want: true,
+		},
+		{
+			name: "text differs beyond threshold",
+			a:    Fingerprint{TextSimhash: hash(0x0)},
+			b:    Fingerprint{TextSimhash: hash(0xff)},
+			want: false,
+		},
+		{
+			name: "rescan with noisy text and same page",
+			a:    Fingerprint{TextSimhash: hash(0x0), ImageHash: hash(0x1)},
+			b:    Fingerprint{TextSimhash: hash(0xff), ImageHash: hash(0x3)},
+			want: true,
+		},
+		{
+			name: "noisy text and different page",
+			a:    Fingerprint{TextSimhash: hash(0x0), ImageHash: hash(0x0)},
+			b:    Fingerprint{TextSimhash: hash(0xff), ImageHash: hash(0xffff)},
+			want: false,
+		},
+		{
+			name: "image only",
+			a:    Fingerprint{ImageHash: hash(0x0)},
+			b:    Fingerprint{ImageHash: hash(0x1)},
+			want: true,
+		},
+		{
+			name: "image only when one has text",
+			a:    Fingerprint{TextSimhash: hash(0x0), ImageHash: hash(0x0)},
+			b:    Fingerprint{ImageHash: hash(0x0)},
+			want: false,
+		},
+		{
+			name: "nothing to compare",
+			a:    Fingerprint{},
+			b:    Fingerprint{},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, got := Compare(tt.a, tt.b); got != tt.want {
+				t.Errorf("Compare() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
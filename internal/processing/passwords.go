@@ -0,0 +1,84 @@
+package processing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/bketelsen/docko/internal/database"
+	"github.com/bketelsen/docko/internal/database/sqlc"
+	"github.com/bketelsen/docko/internal/network"
+	"github.com/bketelsen/docko/internal/pdf"
+)
+
+// Passwords is the managed list of passwords tried on encrypted PDFs
+// They're encrypted at rest like network source credentials.
+type Passwords struct {
+	db     *database.DB
+	crypto *network.CredentialCrypto
+}
+
+// NewPasswords creates a password list
+func NewPasswords(db *database.DB, crypto *network.CredentialCrypto) *Passwords {
+	return &Passwords{db: db, crypto: crypto}
+}
+
+// List returns the stored passwords, still encrypted, in the order they're tried
+func (p *Passwords) List(ctx context.Context) ([]sqlc.PdfPassword, error) {
+	return p.db.Queries.ListPDFPasswords(ctx)
+}
+
+// Add stores a password under a label
+func (p *Passwords) Add(ctx context.Context, label, password string) (sqlc.PdfPassword, error) {
+	if password == "" {
+		return sqlc.PdfPassword{}, fmt.Errorf("password is required")
+	}
+	encrypted, err := p.crypto.Encrypt(password)
+	if err != nil {
+		return sqlc.PdfPassword{}, fmt.Errorf("encrypt password: %w", err)
+	}
+	return p.db.Queries.CreatePDFPassword(ctx, sqlc.CreatePDFPasswordParams{
+		Label:             label,
+		PasswordEncrypted: encrypted,
+	})
+}
+
+// Delete removes a password from the list
+func (p *Passwords) Delete(ctx context.Context, id uuid.UUID) error {
+	return p.db.Queries.DeletePDFPassword(ctx, id)
+}
+
+// Unlock writes a decrypted copy of srcPath to dstPath with the first stored
+// password that opens it, returning that password's label
+// Returns pdf.ErrWrongPassword if none does.
+func (p *Passwords) Unlock(ctx context.Context, srcPath, dstPath string) (string, error) {
+	passwords, err := p.List(ctx)
+	if err != nil {
+		return "", fmt.Errorf("list passwords: %w", err)
+	}
+
+	for _, stored := range passwords {
+		password, err := p.crypto.Decrypt(stored.PasswordEncrypted)
+		if err != nil {
+			// Saved before CREDENTIAL_ENCRYPTION_KEY changed
+			slog.Warn("failed to decrypt stored pdf password", "label", stored.Label, "error", err)
+			continue
+		}
+		err = pdf.Decrypt(srcPath, dstPath, password)
+		if errors.Is(err, pdf.ErrWrongPassword) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+
+		if err := p.db.Queries.TouchPDFPassword(ctx, stored.ID); err != nil {
+			slog.Warn("failed to record pdf password use", "label", stored.Label, "error", err)
+		}
+		return stored.Label, nil
+	}
+	return "", pdf.ErrWrongPassword
+}
@@ -3,17 +3,22 @@ package processing
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 
 	"github.com/bketelsen/docko/internal/database"
 	"github.com/bketelsen/docko/internal/database/sqlc"
 	"github.com/bketelsen/docko/internal/document"
+	"github.com/bketelsen/docko/internal/pdf"
 	"github.com/bketelsen/docko/internal/storage"
 )
 
@@ -21,23 +26,31 @@ import (
 type Processor struct {
 	db          *database.DB
 	docSvc      *document.Service
+	store       *storage.Storage
 	textExt     *TextExtractor
 	thumbGen    *ThumbnailGenerator
+	splitter    *SeparatorDetector
+	passwords   *Passwords
 	broadcaster *StatusBroadcaster
 }
 
 // New creates a new Processor
-func New(db *database.DB, docSvc *document.Service, store *storage.Storage, placeholderPath string, broadcaster *StatusBroadcaster) *Processor {
-	// Get storage path for OCR volumes
-	storagePath := store.BasePath()
+// splitter splits batch scans on separator pages; nil disables splitting.
+// passwords are tried on encrypted PDFs; with nil, they wait for one by hand.
+func New(db *database.DB, docSvc *document.Service, store *storage.Storage, placeholderPath string, broadcaster *StatusBroadcaster, splitter *SeparatorDetector, passwords *Passwords) *Processor {
+	// OCR volumes live in local scratch space, whatever the storage backend
+	storagePath := store.WorkDir()
 	ocrInputPath := storagePath + "/ocr-input"
 	ocrOutputPath := storagePath + "/ocr-output"
 
 	return &Processor{
 		db:          db,
 		docSvc:      docSvc,
+		store:       store,
 		textExt:     NewTextExtractor(ocrInputPath, ocrOutputPath),
 		thumbGen:    NewThumbnailGenerator(store, placeholderPath),
+		splitter:    splitter,
+		passwords:   passwords,
 		broadcaster: broadcaster,
 	}
 }
@@ -61,6 +74,11 @@ func (p *Processor) HandleJob(ctx context.Context, job *sqlc.Job) error {
 
 	// Get document from service
 	doc, err := p.docSvc.GetByID(ctx, docID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Purged while the job was queued
+		slog.Info("document no longer exists, skipping", "doc_id", docID, "job_id", job.ID)
+		return nil
+	}
 	if err != nil {
 		return fmt.Errorf("get document: %w", err)
 	}
@@ -77,21 +95,59 @@ func (p *Processor) HandleJob(ctx context.Context, job *sqlc.Job) error {
 	// Update step: starting
 	p.updateStep(ctx, job.ID, docID, StepStarting)
 
-	// Get PDF path
-	pdfPath := p.docSvc.OriginalPath(doc)
+	// Stage the PDF locally for the extraction and rendering tools
+	pdfPath, release, err := p.store.Fetch(ctx, p.docSvc.PDFKey(doc))
+	if err != nil {
+		return fmt.Errorf("fetch pdf: %w", err)
+	}
+	defer release()
+
+	tmpDir, err := os.MkdirTemp("", "process-*")
+	if err != nil {
+		return fmt.Errorf("create temp dir: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	// Encrypted PDFs are processed from a decrypted copy
+	unlocked, err := p.unlock(ctx, doc, pdfPath, filepath.Join(tmpDir, "unlocked.pdf"))
+	if errors.Is(err, pdf.ErrWrongPassword) {
+		return p.needsPassword(ctx, docID)
+	}
+	if err != nil {
+		return fmt.Errorf("unlock pdf: %w", err)
+	}
+	if unlocked != "" {
+		pdfPath = unlocked
+	}
+
+	// Split batch scans into one document per section between separator pages
+	if p.shouldSplit(payload) {
+		p.updateStep(ctx, job.ID, docID, StepSplitting)
+		split, err := p.splitBatch(ctx, doc, pdfPath)
+		if err != nil {
+			return fmt.Errorf("split batch scan: %w", err)
+		}
+		if split {
+			return nil
+		}
+	}
 
 	// Update step: extracting text
 	p.updateStep(ctx, job.ID, docID, StepExtractingText)
 
 	// Extract text
 	textStart := time.Now()
-	text, method, err := p.textExt.Extract(ctx, pdfPath)
-	if err != nil {
-		// Check if this is the final attempt
-		if job.Attempt >= job.MaxAttempts {
-			return p.quarantine(ctx, docID, fmt.Sprintf("text extraction failed: %v", err))
+	archivePath := filepath.Join(tmpDir, "archive.pdf")
+	text, method := payload.Text, "imported"
+	if text == "" {
+		text, method, err = p.textExt.Extract(ctx, pdfPath, archivePath)
+		if err != nil {
+			// Check if this is the final attempt
+			if job.Attempt >= job.MaxAttempts {
+				return p.quarantine(ctx, docID, fmt.Sprintf("text extraction failed: %v", err))
+			}
+			return fmt.Errorf("extract text: %w", err)
 		}
-		return fmt.Errorf("extract text: %w", err)
 	}
 	textDuration := time.Since(textStart)
 
@@ -120,7 +176,7 @@ func (p *Processor) HandleJob(ctx context.Context, job *sqlc.Job) error {
 
 	// Generate thumbnail
 	thumbStart := time.Now()
-	thumbPath, err := p.thumbGen.Generate(ctx, pdfPath, docID)
+	thumbKey, err := p.thumbGen.Generate(ctx, pdfPath, docID)
 	if err != nil {
 		// Check if this is the final attempt
 		if job.Attempt >= job.MaxAttempts {
@@ -132,12 +188,40 @@ func (p *Processor) HandleJob(ctx context.Context, job *sqlc.Job) error {
 
 	slog.Info("thumbnail generated",
 		"doc_id", docID,
-		"path", thumbPath,
+		"key", thumbKey,
 		"duration_ms", thumbDuration.Milliseconds())
 
 	// Update step: finalizing
 	p.updateStep(ctx, job.ID, docID, StepFinalizing)
 
+	// Keep the OCR'd searchable PDF as the archive version, or the
+	// decrypted copy of an encrypted PDF; otherwise there is none, so drop
+	// any left over from an earlier version. An archive imported along with
+	// the text was stored at ingest.
+	archiveSrc := unlocked
+	if _, err := os.Stat(archivePath); method == "ocr" && err == nil {
+		archiveSrc = archivePath
+	}
+	var archiveChecksum *string
+	archiveKey := p.docSvc.ArchiveKey(doc)
+	if method == "imported" {
+		archiveChecksum = doc.ArchiveChecksum
+	} else if archiveSrc != "" {
+		checksum, _, err := p.store.StoreAndHash(ctx, archiveKey, archiveSrc)
+		if err != nil {
+			return fmt.Errorf("store archive: %w", err)
+		}
+		archiveChecksum = &checksum
+	} else {
+		_ = p.store.Delete(ctx, archiveKey)
+	}
+
+	// Compute similarity fingerprint for near-duplicate detection
+	fp := p.fingerprint(ctx, docID, pdfPath, text)
+
+	// Read page count, title, author and creation date from the PDF
+	meta := p.metadata(docID, pdfPath)
+
 	// All-or-nothing transaction: update document with results
 	tx, err := p.db.Pool.Begin(ctx)
 	if err != nil {
@@ -147,6 +231,12 @@ func (p *Processor) HandleJob(ctx context.Context, job *sqlc.Job) error {
 
 	qtx := p.db.Queries.WithTx(tx)
 
+	// Metadata goes first: the title is only pre-filled while processed_at
+	// is still unset
+	if err := qtx.UpdateDocumentMetadata(ctx, meta); err != nil {
+		return fmt.Errorf("update document metadata: %w", err)
+	}
+
 	// Update document with extracted text and processing status
 	_, err = qtx.UpdateDocumentProcessing(ctx, sqlc.UpdateDocumentProcessingParams{
 		ID:                 docID,
@@ -163,13 +253,45 @@ func (p *Processor) HandleJob(ctx context.Context, job *sqlc.Job) error {
 		return fmt.Errorf("update document processing: %w", err)
 	}
 
+	// Date the document from its text, or queue an uncertain date for review
+	detected, err := p.detectDate(ctx, qtx, job.ID, doc, text)
+	if err != nil {
+		return fmt.Errorf("detect document date: %w", err)
+	}
+
+	err = qtx.SetDocumentArchive(ctx, sqlc.SetDocumentArchiveParams{
+		ID:              docID,
+		ArchiveChecksum: archiveChecksum,
+	})
+	if err != nil {
+		return fmt.Errorf("set document archive: %w", err)
+	}
+
+	// Store fingerprint and flag likely duplicates for review
+	err = qtx.UpdateDocumentFingerprint(ctx, sqlc.UpdateDocumentFingerprintParams{
+		ID:          docID,
+		TextSimhash: fp.TextSimhash,
+		ImageHash:   fp.ImageHash,
+	})
+	if err != nil {
+		return fmt.Errorf("update document fingerprint: %w", err)
+	}
+	duplicates, err := p.flagNearDuplicates(ctx, qtx, docID, fp)
+	if err != nil {
+		return fmt.Errorf("flag near duplicates: %w", err)
+	}
+
 	// Log success event
 	eventPayload, _ := json.Marshal(map[string]any{
 		"text_length":      len(text),
 		"text_method":      method,
 		"text_duration_ms": textDuration.Milliseconds(),
-		"thumb_path":       thumbPath,
+		"thumb_path":       thumbKey,
 		"thumb_duration_ms": thumbDuration.Milliseconds(),
+		"near_duplicates":   duplicates,
+		"archived":          archiveChecksum != nil,
+		"decrypted":         unlocked != "",
+		"detected_date":     detected,
 		"total_duration_ms": time.Since(start).Milliseconds(),
 	})
 
@@ -224,6 +346,335 @@ func (p *Processor) HandleJob(ctx context.Context, job *sqlc.Job) error {
 	return nil
 }
 
+// shouldSplit reports whether a job may split its document as a batch scan
+// Only a new upload's first processing job may; revisions, page edits,
+// reprocessing and unlocking an established document never split it.
+func (p *Processor) shouldSplit(payload document.IngestPayload) bool {
+	return payload.Split && p.splitter.Enabled()
+}
+
+// splitBatch detects separator pages and, when they divide the PDF into
+// more than one section, ingests each section as its own document with the
+// scan's tags, correspondent and type, and moves the batch scan to the
+// trash. Returns false if the document wasn't split.
+// Re-running after a partial failure is safe: sections already ingested
+// come back as duplicates.
+func (p *Processor) splitBatch(ctx context.Context, doc *sqlc.Document, pdfPath string) (bool, error) {
+	pageCount, err := pdf.PageCount(pdfPath)
+	if err != nil || pageCount < 2 {
+		return false, nil
+	}
+
+	separators, err := p.splitter.Detect(ctx, pdfPath)
+	if err != nil {
+		// Detection problems shouldn't stop the scan being processed whole
+		slog.Warn("separator detection failed", "doc_id", doc.ID, "error", err)
+		return false, nil
+	}
+	ranges := PageRanges(separators)
+	if len(ranges) < 2 {
+		return false, nil
+	}
+
+	tmpDir, err := os.MkdirTemp("", "split-*")
+	if err != nil {
+		return false, fmt.Errorf("create temp dir: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	parts, err := pdf.Split(pdfPath, tmpDir, ranges)
+	if err != nil {
+		return false, err
+	}
+
+	// The parts keep the scan's metadata, such as its inbox's defaults
+	opts, err := p.docSvc.ChildOptions(ctx, doc)
+	if err != nil {
+		return false, err
+	}
+
+	base := strings.TrimSuffix(doc.OriginalFilename, filepath.Ext(doc.OriginalFilename))
+	partIDs := make([]uuid.UUID, 0, len(parts))
+	for i, part := range parts {
+		name := fmt.Sprintf("%s-part%d.pdf", base, i+1)
+		child, isDuplicate, err := p.docSvc.IngestWithOptions(ctx, part, name, opts)
+		if err != nil {
+			return false, fmt.Errorf("ingest part %d: %w", i+1, err)
+		}
+		partIDs = append(partIDs, child.ID)
+		if isDuplicate {
+			continue
+		}
+
+		_ = p.docSvc.LogEvent(ctx, child.ID, document.EventSplitFrom, map[string]any{
+			"parent_id":       doc.ID,
+			"parent_filename": doc.OriginalFilename,
+			"pages":           ranges[i].String(),
+			"part":            i + 1,
+		}, nil, 0)
+	}
+
+	_ = p.docSvc.LogEvent(ctx, doc.ID, document.EventSplit, map[string]any{
+		"parts":      partIDs,
+		"page_count": pageCount,
+	}, nil, 0)
+
+	// The batch scan itself is done; its content lives on in the parts
+	if _, err := p.db.Queries.SetDocumentProcessingStatus(ctx, sqlc.SetDocumentProcessingStatusParams{
+		ID:               doc.ID,
+		ProcessingStatus: sqlc.ProcessingStatusCompleted,
+	}); err != nil {
+		return false, fmt.Errorf("set processing status: %w", err)
+	}
+	if _, err := p.docSvc.Trash(ctx, doc.ID); err != nil {
+		return false, fmt.Errorf("trash batch scan: %w", err)
+	}
+
+	slog.Info("batch scan split",
+		"doc_id", doc.ID,
+		"pages", pageCount,
+		"parts", len(parts))
+
+	p.broadcast(StatusUpdate{
+		DocumentID: doc.ID,
+		Status:     StatusCompleted,
+		QueueName:  document.QueueDefault,
+	})
+	return true, nil
+}
+
+// validateArchive checks that an archive PDF opens and, when the source
+// PDF's pages can be counted, has as many pages
+func validateArchive(archivePath, srcPath string) error {
+	pages, err := pdf.PageCount(archivePath)
+	if err != nil {
+		return err
+	}
+	if want, err := pdf.PageCount(srcPath); err == nil && pages != want {
+		return fmt.Errorf("archive has %d pages, source has %d", pages, want)
+	}
+	return nil
+}
+
+// metadata reads a document's PDF info and XMP metadata into update params
+// Failures are logged and leave the fields unset rather than failing the job
+func (p *Processor) metadata(docID uuid.UUID, pdfPath string) sqlc.UpdateDocumentMetadataParams {
+	params := sqlc.UpdateDocumentMetadataParams{ID: docID}
+	meta, err := pdf.ReadMetadata(pdfPath)
+	if err != nil {
+		slog.Warn("failed to read pdf metadata", "doc_id", docID, "error", err)
+		return params
+	}
+
+	params.PageCount = intPtr(int32(meta.PageCount))
+	if meta.Title != "" {
+		params.PdfTitle = &meta.Title
+	}
+	if meta.Author != "" {
+		params.PdfAuthor = &meta.Author
+	}
+	if !meta.CreatedAt.IsZero() {
+		params.PdfCreatedAt = pgtype.Timestamptz{Time: meta.CreatedAt, Valid: true}
+	}
+	return params
+}
+
+// detectDate finds the document date in the extracted text. Confident
+// dates are set on the document; others become pending date suggestions
+// in the review queue. Dates from the source or a person are kept.
+func (p *Processor) detectDate(ctx context.Context, qtx *sqlc.Queries, jobID uuid.UUID, doc *sqlc.Document, text string) (*DetectedDate, error) {
+	// Replace any suggestion left from an earlier version of the document
+	if err := qtx.DeletePendingDateSuggestions(ctx, doc.ID); err != nil {
+		return nil, fmt.Errorf("delete date suggestions: %w", err)
+	}
+
+	if conf, err := doc.DateConfidence.Float64Value(); err == nil && conf.Valid && conf.Float64 >= 1 {
+		return nil, nil
+	}
+
+	detected, ok := DetectDate(text, time.Now())
+	if !ok {
+		return nil, nil
+	}
+
+	var confidence pgtype.Numeric
+	_ = confidence.Scan(fmt.Sprintf("%.2f", detected.Confidence))
+
+	if detected.Confidence >= DateAutoApplyConfidence {
+		err := qtx.SetDetectedDocumentDate(ctx, sqlc.SetDetectedDocumentDateParams{
+			ID:             doc.ID,
+			DocumentDate:   detected.Date,
+			DateConfidence: confidence,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("set document date: %w", err)
+		}
+		return &detected, nil
+	}
+
+	reasoning := "Found in the document text"
+	if detected.Label != "" {
+		reasoning = fmt.Sprintf("Found after %q", detected.Label)
+	}
+	_, err := qtx.CreateAISuggestion(ctx, sqlc.CreateAISuggestionParams{
+		DocumentID:     doc.ID,
+		JobID:          pgtype.UUID{Bytes: jobID, Valid: true},
+		SuggestionType: sqlc.SuggestionTypeDate,
+		Value:          detected.Date.Format("2006-01-02"),
+		Confidence:     confidence,
+		Reasoning:      &reasoning,
+		Status:         sqlc.SuggestionStatusPending,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create date suggestion: %w", err)
+	}
+	return &detected, nil
+}
+
+// fingerprint computes a document's similarity hashes
+// Failures are logged and leave that hash unset rather than failing the job
+func (p *Processor) fingerprint(ctx context.Context, docID uuid.UUID, pdfPath, text string) Fingerprint {
+	var fp Fingerprint
+	if hash, ok := SimHash(text); ok {
+		v := int64(hash)
+		fp.TextSimhash = &v
+	}
+	hash, err := PageHash(ctx, pdfPath)
+	if err != nil {
+		slog.Warn("failed to compute page hash", "doc_id", docID, "error", err)
+	} else {
+		v := int64(hash)
+		fp.ImageHash = &v
+	}
+	return fp
+}
+
+// flagNearDuplicates compares a fingerprint against the other documents
+// close enough to match and records likely duplicates for review, returning
+// how many were found
+func (p *Processor) flagNearDuplicates(ctx context.Context, qtx *sqlc.Queries, docID uuid.UUID, fp Fingerprint) (int, error) {
+	if fp.TextSimhash == nil && fp.ImageHash == nil {
+		return 0, nil
+	}
+
+	// The database narrows the candidates to the loosest thresholds Compare
+	// could accept, so processing doesn't scan every document's fingerprint
+	others, err := qtx.ListNearDuplicateFingerprints(ctx, sqlc.ListNearDuplicateFingerprintsParams{
+		ID:               docID,
+		TextSimhash:      fp.TextSimhash,
+		MaxTextDistance:  looseTextDistanceThreshold,
+		ImageHash:        fp.ImageHash,
+		MaxImageDistance: imageOnlyDistanceThreshold,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("list fingerprints: %w", err)
+	}
+
+	found := 0
+	for _, other := range others {
+		match, ok := Compare(fp, Fingerprint{TextSimhash: other.TextSimhash, ImageHash: other.ImageHash})
+		if !ok {
+			continue
+		}
+		err := qtx.CreateDuplicateCandidate(ctx, sqlc.CreateDuplicateCandidateParams{
+			DocumentID:    docID,
+			DuplicateOfID: other.ID,
+			TextDistance:  match.TextDistance,
+			ImageDistance: match.ImageDistance,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("create duplicate candidate: %w", err)
+		}
+
+		eventPayload, _ := json.Marshal(map[string]any{
+			"duplicate_of_id": other.ID,
+			"text_distance":   match.TextDistance,
+			"image_distance":  match.ImageDistance,
+		})
+		_, err = qtx.CreateDocumentEvent(ctx, sqlc.CreateDocumentEventParams{
+			DocumentID: docID,
+			EventType:  document.EventNearDuplicateFound,
+			Payload:    eventPayload,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("create event: %w", err)
+		}
+
+		slog.Info("near duplicate found",
+			"doc_id", docID,
+			"duplicate_of", other.ID,
+			"text_distance", match.TextDistance,
+			"image_distance", match.ImageDistance)
+		found++
+	}
+	return found, nil
+}
+
+// unlock writes a decrypted copy of an encrypted PDF to dstPath and returns
+// it, or "" if the PDF opens without a password. An archive version
+// decrypted earlier is used, else each stored password is tried in turn.
+// Returns pdf.ErrWrongPassword if nothing opens it.
+func (p *Processor) unlock(ctx context.Context, doc *sqlc.Document, pdfPath, dstPath string) (string, error) {
+	if locked, err := pdf.NeedsPassword(pdfPath); err != nil || !locked {
+		return "", nil // Unreadable PDFs are left for text extraction to report
+	}
+
+	if doc.ArchiveChecksum != nil {
+		archivePath, release, err := p.store.Fetch(ctx, p.docSvc.ArchiveKey(doc))
+		if err == nil {
+			defer release()
+			if locked, err := pdf.NeedsPassword(archivePath); err == nil && !locked {
+				if err := copyFile(archivePath, dstPath); err != nil {
+					return "", fmt.Errorf("copy archive: %w", err)
+				}
+				return dstPath, nil
+			}
+		}
+	}
+
+	if p.passwords == nil {
+		return "", pdf.ErrWrongPassword
+	}
+	label, err := p.passwords.Unlock(ctx, pdfPath, dstPath)
+	if err != nil {
+		return "", err
+	}
+	slog.Info("pdf unlocked", "doc_id", doc.ID, "password", label)
+	_ = p.docSvc.LogEvent(ctx, doc.ID, document.EventUnlocked, map[string]any{"password": label}, nil, 0)
+	return dstPath, nil
+}
+
+// needsPassword leaves an encrypted PDF that no stored password opens
+// waiting for someone to enter its password on the document page
+func (p *Processor) needsPassword(ctx context.Context, docID uuid.UUID) error {
+	reason := "PDF is password-protected"
+	slog.Info("document needs password", "doc_id", docID)
+
+	_, err := p.db.Queries.UpdateDocumentProcessing(ctx, sqlc.UpdateDocumentProcessingParams{
+		ID:                 docID,
+		TextContent:        nil,
+		ThumbnailGenerated: false,
+		ProcessingStatus:   sqlc.ProcessingStatusNeedsPassword,
+		ProcessingError:    &reason,
+		ProcessedAt: pgtype.Timestamptz{
+			Time:  time.Now(),
+			Valid: true,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("update document status: %w", err)
+	}
+	_ = p.docSvc.LogEvent(ctx, docID, document.EventNeedsPassword, nil, nil, 0)
+
+	p.broadcast(StatusUpdate{
+		DocumentID: docID,
+		Status:     StatusNeedsPassword,
+		Error:      reason,
+		QueueName:  document.QueueDefault,
+	})
+	return nil
+}
+
 // quarantine moves a document to failed status after repeated failures
 func (p *Processor) quarantine(ctx context.Context, docID uuid.UUID, reason string) error {
 	slog.Warn("quarantining document",
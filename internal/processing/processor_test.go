@@ -2,6 +2,10 @@ package processing
 
 import (
 	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/bketelsen/docko/internal/document"
 )
 
 func TestNew(t *testing.T) {
@@ -74,3 +78,21 @@ func TestProcessorIntegration(t *testing.T) {
 	//    - 'processing_complete' event logged
 	t.Log("Integration test would verify end-to-end processing flow")
 }
+
+func TestShouldSplit(t *testing.T) {
+	p := &Processor{splitter: NewSeparatorDetector("", true)}
+	docID := uuid.New()
+
+	if !p.shouldSplit(document.IngestPayload{DocumentID: docID, Split: true}) {
+		t.Error("a new upload's first processing job should split")
+	}
+	// Revisions, page edits, reprocessing and unlocking queue plain payloads
+	if p.shouldSplit(document.IngestPayload{DocumentID: docID}) {
+		t.Error("a reprocessed document should never split")
+	}
+
+	disabled := &Processor{}
+	if disabled.shouldSplit(document.IngestPayload{DocumentID: docID, Split: true}) {
+		t.Error("splitting disabled should never split")
+	}
+}
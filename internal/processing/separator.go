@@ -0,0 +1,212 @@
+package processing
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bketelsen/docko/internal/pdf"
+)
+
+// Blank page detection thresholds
+const (
+	// blankInkLevel is the gray level below which a pixel counts as ink
+	blankInkLevel = 160
+	// blankMaxInkRatio is the largest share of ink pixels on a blank page,
+	// allowing for scanner noise and dust
+	blankMaxInkRatio = 0.002
+)
+
+// separatorDPI is the render resolution for separator detection
+const separatorDPI = 150
+
+// zbarNoSymbols is the zbarimg exit status when no barcode was found
+const zbarNoSymbols = 4
+
+// SeparatorDetector finds separator pages in batch scans
+type SeparatorDetector struct {
+	barcode string // Barcode/QR value marking a separator page, empty to disable
+	blank   bool   // Treat blank pages as separators
+}
+
+// NewSeparatorDetector creates a SeparatorDetector
+// barcode is the barcode or QR code value printed on separator sheets;
+// blank enables treating blank pages as separators.
+func NewSeparatorDetector(barcode string, blank bool) *SeparatorDetector {
+	return &SeparatorDetector{barcode: barcode, blank: blank}
+}
+
+// Enabled reports whether any separator detection is configured
+func (d *SeparatorDetector) Enabled() bool {
+	return d != nil && (d.barcode != "" || d.blank)
+}
+
+// Detect renders every page of a PDF and reports which are separators
+func (d *SeparatorDetector) Detect(ctx context.Context, pdfPath string) ([]bool, error) {
+	tmpDir, err := os.MkdirTemp("", "separator-*")
+	if err != nil {
+		return nil, fmt.Errorf("create temp dir: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	renderCtx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+	defer cancel()
+
+	cmd := exec.CommandContext(renderCtx, "pdftoppm",
+		"-png",
+		"-gray",
+		"-r", fmt.Sprintf("%d", separatorDPI),
+		pdfPath,
+		filepath.Join(tmpDir, "page"),
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("pdftoppm failed: %w: %s", err, string(output))
+	}
+
+	// pdftoppm zero-pads page numbers to the width of the page count,
+	// so the names sort in page order
+	pages, err := filepath.Glob(filepath.Join(tmpDir, "page-*.png"))
+	if err != nil {
+		return nil, fmt.Errorf("list rendered pages: %w", err)
+	}
+	sort.Strings(pages)
+
+	separators := make([]bool, len(pages))
+	for i, page := range pages {
+		separators[i], err = d.isSeparator(renderCtx, page)
+		if err != nil {
+			return nil, fmt.Errorf("check page %d: %w", i+1, err)
+		}
+	}
+	return separators, nil
+}
+
+// isSeparator checks one rendered page against the configured separators
+func (d *SeparatorDetector) isSeparator(ctx context.Context, pngPath string) (bool, error) {
+	if d.blank {
+		blank, err := isBlankFile(pngPath)
+		if err != nil {
+			return false, err
+		}
+		if blank {
+			return true, nil
+		}
+	}
+	if d.barcode != "" {
+		values, err := readBarcodes(ctx, pngPath)
+		if err != nil {
+			return false, err
+		}
+		for _, v := range values {
+			if v == d.barcode {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// isBlankFile decodes a rendered page and checks whether it is blank
+func isBlankFile(pngPath string) (bool, error) {
+	f, err := os.Open(pngPath)
+	if err != nil {
+		return false, fmt.Errorf("open page image: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	img, err := png.Decode(f)
+	if err != nil {
+		return false, fmt.Errorf("decode page image: %w", err)
+	}
+	return IsBlankPage(img), nil
+}
+
+// IsBlankPage reports whether an image has (almost) no ink on it
+func IsBlankPage(img image.Image) bool {
+	bounds := img.Bounds()
+	total := bounds.Dx() * bounds.Dy()
+	if total == 0 {
+		return true
+	}
+
+	maxInk := int(float64(total) * blankMaxInkRatio)
+	ink := 0
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b, _ := img.At(x, y).RGBA()
+			// RGBA is 16-bit; compare luminance on the 8-bit scale
+			lum := (299*r + 587*g + 114*b) / 1000 >> 8
+			if lum < blankInkLevel {
+				ink++
+				if ink > maxInk {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// readBarcodes returns the values of all barcodes and QR codes on an image using zbarimg
+func readBarcodes(ctx context.Context, pngPath string) ([]string, error) {
+	cmd := exec.CommandContext(ctx, "zbarimg", "--quiet", "--raw", pngPath)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == zbarNoSymbols {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("zbarimg failed: %w: %s", err, stderr.String())
+	}
+
+	var values []string
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		if v := strings.TrimSpace(scanner.Text()); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values, nil
+}
+
+// PageRanges groups the pages between separators into documents
+// Separator pages are dropped, and runs of separators never produce empty documents.
+func PageRanges(separators []bool) []pdf.PageRange {
+	var ranges []pdf.PageRange
+	start := 0
+	for i, sep := range separators {
+		page := i + 1
+		if sep {
+			if start > 0 {
+				ranges = append(ranges, pdf.PageRange{First: start, Last: page - 1})
+				start = 0
+			}
+			continue
+		}
+		if start == 0 {
+			start = page
+		}
+	}
+	if start > 0 {
+		ranges = append(ranges, pdf.PageRange{First: start, Last: len(separators)})
+	}
+	return ranges
+}
+
+// SeparatorDetectionAvailable reports whether zbarimg is installed for barcode separators
+func SeparatorDetectionAvailable() bool {
+	_, err := exec.LookPath("zbarimg")
+	return err == nil
+}
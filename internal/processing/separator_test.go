@@ -0,0 +1,97 @@
+package processing
+
+import (
+	"image"
+	"image/color"
+	"reflect"
+	"testing"
+
+	"github.com/bketelsen/docko/internal/pdf"
+)
+
+// pageImage returns a white page with the given number of black pixels
+func pageImage(inkPixels int) image.Image {
+	img := image.NewGray(image.Rect(0, 0, 100, 100))
+	for y := 0; y < 100; y++ {
+		for x := 0; x < 100; x++ {
+			img.SetGray(x, y, color.Gray{Y: 255})
+		}
+	}
+	for i := 0; i < inkPixels; i++ {
+		img.SetGray(i%100, i/100, color.Gray{Y: 0})
+	}
+	return img
+}
+
+func TestIsBlankPage(t *testing.T) {
+	tests := []struct {
+		name string
+		ink  int
+		want bool
+	}{
+		{"white page", 0, true},
+		{"scanner dust", 10, true},
+		{"text", 500, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsBlankPage(pageImage(tt.ink)); got != tt.want {
+				t.Errorf("IsBlankPage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPageRanges(t *testing.T) {
+	tests := []struct {
+		name       string
+		separators []bool
+		want       []pdf.PageRange
+	}{
+		{
+			name:       "no separators",
+			separators: []bool{false, false, false},
+			want:       []pdf.PageRange{{First: 1, Last: 3}},
+		},
+		{
+			name:       "separator between documents",
+			separators: []bool{false, false, true, false},
+			want:       []pdf.PageRange{{First: 1, Last: 2}, {First: 4, Last: 4}},
+		},
+		{
+			name:       "leading, trailing and repeated separators",
+			separators: []bool{true, false, true, true, false, false, true},
+			want:       []pdf.PageRange{{First: 2, Last: 2}, {First: 5, Last: 6}},
+		},
+		{
+			name:       "only separators",
+			separators: []bool{true, true},
+			want:       nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PageRanges(tt.separators); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PageRanges() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSeparatorDetectorEnabled(t *testing.T) {
+	var nilDetector *SeparatorDetector
+	if nilDetector.Enabled() {
+		t.Error("nil detector should be disabled")
+	}
+	if NewSeparatorDetector("", false).Enabled() {
+		t.Error("detector without barcode or blank pages should be disabled")
+	}
+	if !NewSeparatorDetector("DOCKO-SEPARATOR", false).Enabled() {
+		t.Error("detector with barcode should be enabled")
+	}
+	if !NewSeparatorDetector("", true).Enabled() {
+		t.Error("detector with blank pages should be enabled")
+	}
+}
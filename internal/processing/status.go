@@ -13,18 +13,21 @@ const MaxSubscribers = 100
 
 // Processing status constants for SSE events
 const (
-	StatusPending      = "pending"
-	StatusProcessing   = "processing"
-	StatusCompleted    = "completed"
-	StatusFailed       = "failed"
-	StatusAIProcessing = "ai_processing"
-	StatusAIComplete   = "ai_complete"
-	StatusAIFailed     = "ai_failed"
+	StatusPending       = "pending"
+	StatusProcessing    = "processing"
+	StatusCompleted     = "completed"
+	StatusFailed        = "failed"
+	StatusNeedsPassword = "needs_password"
+	StatusAIProcessing  = "ai_processing"
+	StatusAIComplete    = "ai_complete"
+	StatusAIFailed      = "ai_failed"
 )
 
 // Processing step constants for progress tracking
 const (
 	StepStarting            = "starting"
+	StepConverting          = "converting"
+	StepSplitting           = "splitting"
 	StepExtractingText      = "extracting_text"
 	StepRunningOCR          = "running_ocr"
 	StepGeneratingThumbnail = "generating_thumbnail"
@@ -34,8 +37,8 @@ const (
 // StatusUpdate represents a processing status change
 type StatusUpdate struct {
 	DocumentID  uuid.UUID
-	Status      string // pending, processing, completed, failed
-	CurrentStep string // starting, extracting_text, running_ocr, generating_thumbnail, finalizing
+	Status      string // pending, processing, completed, failed, needs_password
+	CurrentStep string // starting, converting, splitting, extracting_text, running_ocr, generating_thumbnail, finalizing
 	Error       string // error message if failed
 	QueueName   string // queue name for queue-level SSE events
 }
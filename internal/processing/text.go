@@ -15,6 +15,9 @@ import (
 	"github.com/ledongthuc/pdf"
 )
 
+// ocrPDFGracePeriod is how long to wait for OCRmyPDF's PDF output after its text output appears
+const ocrPDFGracePeriod = 10 * time.Second
+
 // TextExtractor extracts text from PDF files using embedded text extraction
 // and OCR fallback via the OCRmyPDF Docker service
 type TextExtractor struct {
@@ -36,8 +39,10 @@ func NewTextExtractor(ocrInputPath, ocrOutputPath string) *TextExtractor {
 }
 
 // Extract extracts text from a PDF file
+// When OCR is used, the searchable PDF it produces is kept at archivePath
+// (discarded if archivePath is empty).
 // Returns: text content, method used ("embedded" or "ocr"), error
-func (e *TextExtractor) Extract(ctx context.Context, pdfPath string) (string, string, error) {
+func (e *TextExtractor) Extract(ctx context.Context, pdfPath, archivePath string) (string, string, error) {
 	start := time.Now()
 
 	// Try embedded text extraction first
@@ -61,7 +66,7 @@ func (e *TextExtractor) Extract(ctx context.Context, pdfPath string) (string, st
 		"pdf_path", pdfPath,
 		"embedded_length", len(text))
 
-	ocrText, err := e.ocrViaService(ctx, pdfPath)
+	ocrText, err := e.ocrViaService(ctx, pdfPath, archivePath)
 	if err != nil {
 		return "", "", fmt.Errorf("ocr extraction: %w", err)
 	}
@@ -101,8 +106,9 @@ func (e *TextExtractor) extractEmbedded(pdfPath string) (string, bool, error) {
 }
 
 // ocrViaService sends PDF to OCRmyPDF service and retrieves extracted text
+// The searchable PDF/A output is copied to archivePath unless it is empty.
 // Uses shared volumes configured in docker-compose.yml
-func (e *TextExtractor) ocrViaService(ctx context.Context, pdfPath string) (string, error) {
+func (e *TextExtractor) ocrViaService(ctx context.Context, pdfPath, archivePath string) (string, error) {
 	// Generate unique job ID
 	jobID := uuid.New().String()
 
@@ -126,6 +132,11 @@ func (e *TextExtractor) ocrViaService(ctx context.Context, pdfPath string) (stri
 
 	timeout := time.After(e.ocrTimeout)
 
+	// The sidecar text lands before the PDF, which isn't written atomically;
+	// give the PDF a grace period to appear and stop growing
+	var textReadyAt time.Time
+	pdfSize := int64(-1)
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -135,12 +146,37 @@ func (e *TextExtractor) ocrViaService(ctx context.Context, pdfPath string) (stri
 		case <-ticker.C:
 			// Check if output text file exists
 			if _, err := os.Stat(outputTextPath); err == nil {
+				if archivePath != "" && !settled(outputPDFPath, &pdfSize) {
+					if textReadyAt.IsZero() {
+						textReadyAt = time.Now()
+					}
+					if time.Since(textReadyAt) < ocrPDFGracePeriod {
+						continue
+					}
+				}
+
 				// Output ready, read it
 				text, err := os.ReadFile(outputTextPath)
 				if err != nil {
 					return "", fmt.Errorf("read OCR output: %w", err)
 				}
 
+				// Keep the searchable PDF as the archive version, unless it
+				// is incomplete
+				if archivePath != "" {
+					if err := copyFile(outputPDFPath, archivePath); err != nil {
+						slog.Warn("failed to keep OCR archive PDF",
+							"pdf_path", pdfPath,
+							"error", err)
+						_ = os.Remove(archivePath)
+					} else if err := validateArchive(archivePath, pdfPath); err != nil {
+						slog.Warn("skipping invalid OCR archive PDF",
+							"pdf_path", pdfPath,
+							"error", err)
+						_ = os.Remove(archivePath)
+					}
+				}
+
 				// Clean up output files
 				_ = os.Remove(outputTextPath)
 				_ = os.Remove(outputPDFPath)
@@ -151,6 +187,21 @@ func (e *TextExtractor) ocrViaService(ctx context.Context, pdfPath string) (stri
 	}
 }
 
+// settled reports whether the file at path exists with the same non-zero
+// size as on the previous call, tracked in lastSize
+func settled(path string, lastSize *int64) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		*lastSize = -1
+		return false
+	}
+	if info.Size() != *lastSize {
+		*lastSize = info.Size()
+		return false
+	}
+	return info.Size() > 0
+}
+
 // copyFile copies a file from src to dst
 func copyFile(src, dst string) error {
 	// Ensure destination directory exists
@@ -2,12 +2,39 @@ package processing
 
 import (
 	"context"
+	"fmt"
+	"image/png"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/bketelsen/docko/internal/pdf"
 )
 
+// writeTestPDF creates a PDF of blank pages at path
+func writeTestPDF(t *testing.T, path string, pages int) {
+	t.Helper()
+	dir := t.TempDir()
+	var images []string
+	for i := 0; i < pages; i++ {
+		img := filepath.Join(dir, fmt.Sprintf("page-%d.png", i))
+		f, err := os.Create(img)
+		if err != nil {
+			t.Fatalf("create png: %v", err)
+		}
+		if err := png.Encode(f, pageImage(0)); err != nil {
+			t.Fatalf("encode png: %v", err)
+		}
+		_ = f.Close()
+		images = append(images, img)
+	}
+	if err := pdf.FromImages(context.Background(), path, ".png", images...); err != nil {
+		t.Fatalf("FromImages failed: %v", err)
+	}
+}
+
 func TestNewTextExtractor(t *testing.T) {
 	e := NewTextExtractor("/input", "/output")
 
@@ -91,7 +118,7 @@ func TestOcrViaService_Timeout(t *testing.T) {
 	e.ocrTimeout = 100 * time.Millisecond
 
 	ctx := context.Background()
-	_, err := e.ocrViaService(ctx, pdfPath)
+	_, err := e.ocrViaService(ctx, pdfPath, "")
 
 	// Should timeout since no OCR service is writing output
 	if err == nil {
@@ -118,7 +145,7 @@ func TestOcrViaService_ContextCancellation(t *testing.T) {
 	// Cancel immediately
 	cancel()
 
-	_, err := e.ocrViaService(ctx, pdfPath)
+	_, err := e.ocrViaService(ctx, pdfPath, "")
 
 	if err == nil {
 		t.Error("expected context cancellation error")
@@ -168,7 +195,7 @@ func TestOcrViaService_Success(t *testing.T) {
 		_ = os.WriteFile(outputPDFPath, minimalPDF, 0644)
 	}()
 
-	text, err := e.ocrViaService(ctx, pdfPath)
+	text, err := e.ocrViaService(ctx, pdfPath, "")
 	if err != nil {
 		t.Fatalf("ocrViaService failed: %v", err)
 	}
@@ -185,6 +212,95 @@ func TestOcrViaService_Success(t *testing.T) {
 	}
 }
 
+func TestOcrViaService_KeepsArchive(t *testing.T) {
+	tests := []struct {
+		name     string
+		truncate bool
+		wantKept bool
+	}{
+		{name: "complete PDF", wantKept: true},
+		{name: "truncated PDF", truncate: true, wantKept: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inputDir := t.TempDir()
+			outputDir := t.TempDir()
+			archivePath := filepath.Join(t.TempDir(), "archive", "doc.pdf")
+
+			pdfPath := filepath.Join(t.TempDir(), "test.pdf")
+			writeTestPDF(t, pdfPath, 2)
+			searchable := filepath.Join(t.TempDir(), "searchable.pdf")
+			writeTestPDF(t, searchable, 2)
+			output, err := os.ReadFile(searchable)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.truncate {
+				output = output[:len(output)/2]
+			}
+
+			e := NewTextExtractor(inputDir, outputDir)
+			e.ocrTimeout = 5 * time.Second
+
+			// Simulate the OCR service writing the text before the PDF
+			go func() {
+				time.Sleep(200 * time.Millisecond)
+				entries, err := os.ReadDir(inputDir)
+				if err != nil || len(entries) == 0 {
+					return
+				}
+				jobID := strings.TrimSuffix(entries[0].Name(), ".pdf")
+
+				_ = os.WriteFile(filepath.Join(outputDir, jobID+".txt"), []byte("OCR text"), 0644)
+				time.Sleep(700 * time.Millisecond)
+				_ = os.WriteFile(filepath.Join(outputDir, jobID+".pdf"), output, 0644)
+			}()
+
+			if _, err := e.ocrViaService(context.Background(), pdfPath, archivePath); err != nil {
+				t.Fatalf("ocrViaService failed: %v", err)
+			}
+
+			archived, err := os.ReadFile(archivePath)
+			if tt.wantKept {
+				if err != nil {
+					t.Fatalf("archive PDF not kept: %v", err)
+				}
+				if string(archived) != string(output) {
+					t.Error("archive content differs from OCR output PDF")
+				}
+			} else if err == nil {
+				t.Error("truncated archive PDF was kept")
+			}
+
+			entries, _ := os.ReadDir(outputDir)
+			if len(entries) != 0 {
+				t.Errorf("output directory not cleaned up, has %d files", len(entries))
+			}
+		})
+	}
+}
+
+func TestSettled(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.pdf")
+	size := int64(-1)
+
+	if settled(path, &size) {
+		t.Error("missing file reported settled")
+	}
+	_ = os.WriteFile(path, []byte("%PDF-1.4 part"), 0644)
+	if settled(path, &size) {
+		t.Error("file reported settled on first sight")
+	}
+	if !settled(path, &size) {
+		t.Error("unchanged file not reported settled")
+	}
+	_ = os.WriteFile(path, []byte("%PDF-1.4 part and more"), 0644)
+	if settled(path, &size) {
+		t.Error("growing file reported settled")
+	}
+}
+
 func TestExtract_ReturnsEmbeddedMethod(t *testing.T) {
 	// This test requires a real PDF with embedded text
 	// Skip if we don't have a test fixture
@@ -198,7 +314,7 @@ func TestExtract_ReturnsEmbeddedMethod(t *testing.T) {
 	e := NewTextExtractor(inputDir, outputDir)
 
 	ctx := context.Background()
-	text, method, err := e.Extract(ctx, testPDF)
+	text, method, err := e.Extract(ctx, testPDF, "")
 
 	if err != nil {
 		t.Fatalf("Extract failed: %v", err)
@@ -252,7 +368,7 @@ func TestExtract_FallsBackToOCR(t *testing.T) {
 		_ = os.WriteFile(outputPDFPath, minimalPDF, 0644)
 	}()
 
-	text, method, err := e.Extract(ctx, pdfPath)
+	text, method, err := e.Extract(ctx, pdfPath, "")
 	if err != nil {
 		t.Fatalf("Extract failed: %v", err)
 	}
@@ -2,6 +2,7 @@ package processing
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log/slog"
@@ -12,13 +13,15 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/bketelsen/docko/internal/database/sqlc"
+	"github.com/bketelsen/docko/internal/document"
 	"github.com/bketelsen/docko/internal/storage"
 )
 
 // ThumbnailGenerator creates thumbnails from PDF files
 type ThumbnailGenerator struct {
 	placeholderPath string           // Path to placeholder.webp for failures
-	storage         *storage.Storage // Storage for path generation
+	storage         *storage.Storage // Storage the thumbnails are saved to
 }
 
 // NewThumbnailGenerator creates a ThumbnailGenerator
@@ -31,25 +34,33 @@ func NewThumbnailGenerator(storage *storage.Storage, placeholderPath string) *Th
 }
 
 // Generate creates a 300px WebP thumbnail from the first page of a PDF
-// Returns the path to the generated thumbnail
+// Returns the storage key of the saved thumbnail
 func (g *ThumbnailGenerator) Generate(ctx context.Context, pdfPath string, docID uuid.UUID) (string, error) {
-	start := time.Now()
-
-	// Compute thumbnail path using storage
-	thumbPath := g.storage.PathForUUID(storage.CategoryThumbnails, docID, ".webp")
-
-	// Ensure thumbnail directory exists
-	if err := os.MkdirAll(filepath.Dir(thumbPath), 0755); err != nil {
-		return "", fmt.Errorf("create thumbnail dir: %w", err)
-	}
+	thumbKey := storage.KeyForUUID(storage.CategoryThumbnails, docID, ".webp")
 
-	// Create temp directory for intermediate PNG
+	// Create temp directory for intermediate PNG and the WebP
 	tmpDir, err := os.MkdirTemp("", "thumb-*")
 	if err != nil {
 		return "", fmt.Errorf("create temp dir: %w", err)
 	}
 	defer func() { _ = os.RemoveAll(tmpDir) }()
 
+	thumbPath := filepath.Join(tmpDir, "thumb.webp")
+	if err := g.render(ctx, pdfPath, thumbPath, tmpDir, docID); err != nil {
+		return "", err
+	}
+
+	if err := g.storage.Store(ctx, thumbKey, thumbPath); err != nil {
+		return "", fmt.Errorf("store thumbnail: %w", err)
+	}
+	return thumbKey, nil
+}
+
+// render writes the thumbnail for a PDF to thumbPath, falling back to the
+// placeholder when the PDF can't be rendered
+func (g *ThumbnailGenerator) render(ctx context.Context, pdfPath, thumbPath, tmpDir string, docID uuid.UUID) error {
+	start := time.Now()
+
 	// Output prefix for pdftoppm (will create thumb.png)
 	pngPrefix := filepath.Join(tmpDir, "thumb")
 
@@ -77,13 +88,12 @@ func (g *ThumbnailGenerator) Generate(ctx context.Context, pdfPath string, docID
 			"error", err,
 			"output", string(output))
 		if placeholderErr := g.usePlaceholder(thumbPath); placeholderErr != nil {
-			return "", fmt.Errorf("pdftoppm failed and placeholder copy failed: %w (original: %v)", placeholderErr, err)
+			return fmt.Errorf("pdftoppm failed and placeholder copy failed: %w (original: %v)", placeholderErr, err)
 		}
 		slog.Info("thumbnail generated (placeholder)",
 			"doc_id", docID,
-			"path", thumbPath,
 			"duration_ms", time.Since(start).Milliseconds())
-		return thumbPath, nil
+		return nil
 	}
 
 	// pdftoppm creates thumb.png
@@ -95,9 +105,9 @@ func (g *ThumbnailGenerator) Generate(ctx context.Context, pdfPath string, docID
 			"doc_id", docID,
 			"expected_path", pngPath)
 		if placeholderErr := g.usePlaceholder(thumbPath); placeholderErr != nil {
-			return "", fmt.Errorf("pdftoppm produced no output and placeholder copy failed: %w", placeholderErr)
+			return fmt.Errorf("pdftoppm produced no output and placeholder copy failed: %w", placeholderErr)
 		}
-		return thumbPath, nil
+		return nil
 	}
 
 	// Step 2: PNG to WebP
@@ -110,15 +120,14 @@ func (g *ThumbnailGenerator) Generate(ctx context.Context, pdfPath string, docID
 	output, err = cmd.CombinedOutput()
 	if err != nil {
 		// cwebp failure is unexpected if PNG exists - return error
-		return "", fmt.Errorf("cwebp failed: %w\noutput: %s", err, output)
+		return fmt.Errorf("cwebp failed: %w\noutput: %s", err, output)
 	}
 
 	slog.Info("thumbnail generated",
 		"doc_id", docID,
-		"path", thumbPath,
 		"duration_ms", time.Since(start).Milliseconds())
 
-	return thumbPath, nil
+	return nil
 }
 
 // usePlaceholder copies the placeholder image to the thumbnail location
@@ -157,3 +166,46 @@ func CheckDependencies() error {
 	}
 	return nil
 }
+
+// HandleThumbnailJob renders a document's thumbnail again without reprocessing
+// it (implements queue.JobHandler)
+func (p *Processor) HandleThumbnailJob(ctx context.Context, job *sqlc.Job) error {
+	var payload document.IngestPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return fmt.Errorf("unmarshal payload: %w", err)
+	}
+
+	docID := payload.DocumentID
+	start := time.Now()
+
+	doc, err := p.docSvc.GetByID(ctx, docID)
+	if err != nil {
+		return fmt.Errorf("get document: %w", err)
+	}
+
+	pdfPath, release, err := p.store.Fetch(ctx, p.docSvc.PDFKey(doc))
+	if err != nil {
+		return fmt.Errorf("fetch pdf: %w", err)
+	}
+	defer release()
+
+	thumbKey, err := p.thumbGen.Generate(ctx, pdfPath, docID)
+	if err != nil {
+		return fmt.Errorf("generate thumbnail: %w", err)
+	}
+
+	err = p.db.Queries.SetDocumentThumbnailGenerated(ctx, sqlc.SetDocumentThumbnailGeneratedParams{
+		ID:                 docID,
+		ThumbnailGenerated: true,
+	})
+	if err != nil {
+		return fmt.Errorf("set thumbnail generated: %w", err)
+	}
+
+	_ = p.docSvc.LogEvent(ctx, docID, document.EventThumbnailGenerated, map[string]any{
+		"thumb_path": thumbKey,
+	}, nil, time.Since(start))
+
+	slog.Info("thumbnail regenerated", "doc_id", docID, "key", thumbKey)
+	return nil
+}
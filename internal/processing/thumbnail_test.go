@@ -197,21 +197,21 @@ startxref
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	thumbPath, err := gen.Generate(ctx, testPDFPath, docID)
+	thumbKey, err := gen.Generate(ctx, testPDFPath, docID)
 	if err != nil {
 		// Some minimal PDFs may fail - that's okay, we use placeholder
 		t.Logf("Generate returned error (may be expected for minimal PDF): %v", err)
 	}
 
-	// Verify thumbnail path is correct format
-	expectedPath := store.PathForUUID(storage.CategoryThumbnails, docID, ".webp")
-	if thumbPath != expectedPath {
-		t.Errorf("thumbPath = %q, want %q", thumbPath, expectedPath)
+	// Verify thumbnail key is correct format
+	expectedKey := storage.KeyForUUID(storage.CategoryThumbnails, docID, ".webp")
+	if thumbKey != expectedKey {
+		t.Errorf("thumbKey = %q, want %q", thumbKey, expectedKey)
 	}
 
 	// Verify file exists (either generated or placeholder)
-	if _, err := os.Stat(thumbPath); os.IsNotExist(err) {
-		t.Errorf("thumbnail file does not exist at %s", thumbPath)
+	if !store.FileExists(ctx, thumbKey) {
+		t.Errorf("thumbnail file does not exist at %s", thumbKey)
 	}
 }
 
@@ -246,7 +246,7 @@ func TestGenerate_InvalidPDF(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	thumbPath, err := gen.Generate(ctx, invalidPDFPath, docID)
+	thumbKey, err := gen.Generate(ctx, invalidPDFPath, docID)
 
 	// Should not error - should use placeholder
 	if err != nil {
@@ -254,9 +254,11 @@ func TestGenerate_InvalidPDF(t *testing.T) {
 	}
 
 	// Verify thumbnail exists
-	if _, err := os.Stat(thumbPath); os.IsNotExist(err) {
-		t.Error("thumbnail file should exist (placeholder)")
+	thumbPath, release, err := store.Fetch(ctx, thumbKey)
+	if err != nil {
+		t.Fatalf("thumbnail file should exist (placeholder): %v", err)
 	}
+	defer release()
 
 	// Verify it's the placeholder content
 	content, err := os.ReadFile(thumbPath)
@@ -292,7 +294,7 @@ func TestGenerate_NonExistentPDF(t *testing.T) {
 	ctx := context.Background()
 
 	// Try to generate from non-existent PDF
-	thumbPath, err := gen.Generate(ctx, filepath.Join(tmpDir, "nonexistent.pdf"), docID)
+	thumbKey, err := gen.Generate(ctx, filepath.Join(tmpDir, "nonexistent.pdf"), docID)
 
 	// Should use placeholder instead of erroring
 	if err != nil {
@@ -300,20 +302,14 @@ func TestGenerate_NonExistentPDF(t *testing.T) {
 	}
 
 	// Verify thumbnail exists (placeholder)
-	if _, err := os.Stat(thumbPath); os.IsNotExist(err) {
+	if !store.FileExists(ctx, thumbKey) {
 		t.Error("thumbnail file should exist (placeholder)")
 	}
 }
 
 func TestThumbnailPath_CorrectExtension(t *testing.T) {
-	tmpDir := t.TempDir()
-	store, err := storage.New(tmpDir)
-	if err != nil {
-		t.Fatalf("failed to create storage: %v", err)
-	}
-
 	docID := uuid.New()
-	path := store.PathForUUID(storage.CategoryThumbnails, docID, ".webp")
+	path := storage.KeyForUUID(storage.CategoryThumbnails, docID, ".webp")
 
 	// Verify path ends with .webp
 	if filepath.Ext(path) != ".webp" {
@@ -0,0 +1,378 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Encrypted objects use envelope encryption: each object gets a random data
+// key, wrapped with the master key and stored in the header. The content is
+// sealed in fixed-size AES-256-GCM chunks so it can be streamed and seeked
+// without decrypting the whole file.
+//
+//	magic (8) | wrap nonce (12) | wrapped data key (32+16) | chunk | chunk | ...
+//
+// Each chunk holds up to encChunkSize bytes plus a tag. Chunk nonces are the
+// chunk index with a final-chunk flag, which is safe because every data key
+// is used for a single object, and stops chunks being reordered or truncated.
+const (
+	encMagic       = "DOCKENC1"
+	encChunkSize   = 64 * 1024
+	encTagSize     = 16
+	encNonceSize   = 12
+	encKeySize     = 32
+	encHeaderSize  = len(encMagic) + encNonceSize + encKeySize + encTagSize
+	encSealedChunk = encChunkSize + encTagSize
+)
+
+// ErrWrongKey is returned when an object was encrypted with another master key
+var ErrWrongKey = errors.New("object was encrypted with a different master key")
+
+// EncryptedBackend encrypts objects written to another Backend
+// Objects written before encryption was enabled are read as-is, so an
+// archive can be encrypted gradually with EncryptExisting.
+type EncryptedBackend struct {
+	inner Backend
+	key   []byte // 32 bytes derived from the master secret
+}
+
+// NewEncryptedBackend wraps inner, deriving the master key from secret
+func NewEncryptedBackend(inner Backend, secret string) *EncryptedBackend {
+	// Derive 32-byte key using SHA-256
+	hash := sha256.Sum256([]byte(secret))
+	return &EncryptedBackend{inner: inner, key: hash[:]}
+}
+
+// Put encrypts the object and stores it in the inner backend
+func (b *EncryptedBackend) Put(ctx context.Context, key string, r io.Reader, size int64) error {
+	header, aead, err := b.newHeader()
+	if err != nil {
+		return err
+	}
+	enc := &encryptReader{src: bufio.NewReaderSize(r, encChunkSize), aead: aead, buf: header}
+	return b.inner.Put(ctx, key, enc, encryptedSize(size))
+}
+
+// Open returns a decrypting, seekable reader for the object
+func (b *EncryptedBackend) Open(ctx context.Context, key string) (Object, ObjectInfo, error) {
+	obj, info, err := b.inner.Open(ctx, key)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+
+	aead, err := b.readHeader(obj)
+	if err != nil {
+		_ = obj.Close()
+		return nil, ObjectInfo{}, fmt.Errorf("%s: %w", key, err)
+	}
+	if aead == nil {
+		// Written before encryption was enabled
+		if _, err := obj.Seek(0, io.SeekStart); err != nil {
+			_ = obj.Close()
+			return nil, ObjectInfo{}, err
+		}
+		return obj, info, nil
+	}
+
+	if info.Size < int64(encHeaderSize+encTagSize) {
+		_ = obj.Close()
+		return nil, ObjectInfo{}, fmt.Errorf("%s: encrypted object is truncated", key)
+	}
+	plain := plaintextSize(info.Size)
+	return &decryptReader{obj: obj, aead: aead, size: plain, chunk: -1},
+		ObjectInfo{Size: plain, ModTime: info.ModTime}, nil
+}
+
+// Stat describes the object, reporting its plaintext size
+func (b *EncryptedBackend) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	obj, info, err := b.Open(ctx, key)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	_ = obj.Close()
+	return info, nil
+}
+
+// Delete removes the object
+func (b *EncryptedBackend) Delete(ctx context.Context, key string) error {
+	return b.inner.Delete(ctx, key)
+}
+
+// Move renames the object; its content doesn't depend on the key
+func (b *EncryptedBackend) Move(ctx context.Context, src, dst string) error {
+	return b.inner.Move(ctx, src, dst)
+}
+
+// List returns the keys that start with prefix
+func (b *EncryptedBackend) List(ctx context.Context, prefix string) ([]string, error) {
+	return b.inner.List(ctx, prefix)
+}
+
+// EncryptResult summarizes an EncryptExisting run
+type EncryptResult struct {
+	Encrypted int
+	Skipped   int // Already encrypted
+}
+
+// EncryptExisting encrypts every plaintext object in place
+// Objects that are already encrypted are skipped, so it is safe to re-run
+// after an interruption. progress, if set, is called after each object.
+func (b *EncryptedBackend) EncryptExisting(ctx context.Context, progress func(key string, encrypted bool)) (EncryptResult, error) {
+	var result EncryptResult
+	for _, cat := range Categories {
+		keys, err := b.inner.List(ctx, cat+"/")
+		if err != nil {
+			return result, fmt.Errorf("list %s files: %w", cat, err)
+		}
+		for _, key := range keys {
+			encrypted, err := b.encryptObject(ctx, key)
+			if err != nil {
+				return result, fmt.Errorf("encrypt %s: %w", key, err)
+			}
+			if encrypted {
+				result.Encrypted++
+			} else {
+				result.Skipped++
+			}
+			if progress != nil {
+				progress(key, encrypted)
+			}
+		}
+	}
+	return result, nil
+}
+
+// encryptObject rewrites a plaintext object encrypted, reporting whether it did
+// The encrypted copy is written under a temporary key and moved over the
+// original, so a failure never leaves a half-written object behind.
+func (b *EncryptedBackend) encryptObject(ctx context.Context, key string) (bool, error) {
+	obj, info, err := b.inner.Open(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = obj.Close() }()
+
+	magic := make([]byte, len(encMagic))
+	if _, err := io.ReadFull(obj, magic); err == nil && string(magic) == encMagic {
+		return false, nil
+	}
+	if _, err := obj.Seek(0, io.SeekStart); err != nil {
+		return false, err
+	}
+
+	tmp := key + ".encrypting"
+	if err := b.Put(ctx, tmp, obj, info.Size); err != nil {
+		_ = b.inner.Delete(ctx, tmp)
+		return false, err
+	}
+	if err := b.inner.Move(ctx, tmp, key); err != nil {
+		_ = b.inner.Delete(ctx, tmp)
+		return false, err
+	}
+	return true, nil
+}
+
+// newHeader creates a data key and returns the object header and its cipher
+func (b *EncryptedBackend) newHeader() ([]byte, cipher.AEAD, error) {
+	dataKey := make([]byte, encKeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, nil, fmt.Errorf("generate data key: %w", err)
+	}
+	wrapper, err := newGCM(b.key)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce := make([]byte, encNonceSize)
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, nil, fmt.Errorf("generate nonce: %w", err)
+	}
+
+	header := make([]byte, 0, encHeaderSize+encSealedChunk)
+	header = append(header, encMagic...)
+	header = append(header, nonce...)
+	header = wrapper.Seal(header, nonce, dataKey, []byte(encMagic))
+
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return header, aead, nil
+}
+
+// readHeader reads an object header and returns the cipher for its content
+// Returns a nil cipher if the object isn't encrypted.
+func (b *EncryptedBackend) readHeader(r io.Reader) (cipher.AEAD, error) {
+	header := make([]byte, encHeaderSize)
+	n, err := io.ReadFull(r, header)
+	if n < len(encMagic) || !bytes.Equal(header[:len(encMagic)], []byte(encMagic)) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+
+	wrapper, err := newGCM(b.key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := header[len(encMagic) : len(encMagic)+encNonceSize]
+	dataKey, err := wrapper.Open(nil, nonce, header[len(encMagic)+encNonceSize:], []byte(encMagic))
+	if err != nil {
+		return nil, ErrWrongKey
+	}
+	return newGCM(dataKey)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// chunkNonce returns the nonce for a chunk index
+func chunkNonce(index int64, final bool) []byte {
+	nonce := make([]byte, encNonceSize)
+	binary.BigEndian.PutUint64(nonce[2:10], uint64(index))
+	if final {
+		nonce[encNonceSize-1] = 1
+	}
+	return nonce
+}
+
+// encryptedSize returns the stored size of a plaintext of size bytes
+// Even an empty plaintext gets one (empty) chunk.
+func encryptedSize(size int64) int64 {
+	if size < 0 {
+		return -1
+	}
+	chunks := max((size+encChunkSize-1)/encChunkSize, 1)
+	return int64(encHeaderSize) + size + chunks*encTagSize
+}
+
+// plaintextSize is the inverse of encryptedSize
+func plaintextSize(size int64) int64 {
+	body := size - int64(encHeaderSize)
+	chunks := (body + encSealedChunk - 1) / encSealedChunk
+	return max(body-chunks*encTagSize, 0)
+}
+
+// encryptReader seals its source chunk by chunk
+type encryptReader struct {
+	src   *bufio.Reader
+	aead  cipher.AEAD
+	buf   []byte // Sealed bytes not yet read
+	index int64
+	done  bool
+}
+
+func (e *encryptReader) Read(p []byte) (int, error) {
+	for len(e.buf) == 0 {
+		if e.done {
+			return 0, io.EOF
+		}
+		if err := e.sealNext(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, e.buf)
+	e.buf = e.buf[n:]
+	return n, nil
+}
+
+func (e *encryptReader) sealNext() error {
+	chunk := make([]byte, encChunkSize)
+	n, err := io.ReadFull(e.src, chunk)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return err
+	}
+	// The chunk is final if the source has nothing after it
+	final := n < encChunkSize
+	if !final {
+		if _, err := e.src.Peek(1); err == io.EOF {
+			final = true
+		} else if err != nil {
+			return err
+		}
+	}
+	e.buf = e.aead.Seal(e.buf[:0], chunkNonce(e.index, final), chunk[:n], nil)
+	e.index++
+	e.done = final
+	return nil
+}
+
+// decryptReader decrypts an encrypted object, one chunk at a time
+type decryptReader struct {
+	obj   Object
+	aead  cipher.AEAD
+	size  int64 // Plaintext size
+	pos   int64
+	chunk int64 // Index of the decrypted chunk in plain, or -1
+	plain []byte
+}
+
+func (d *decryptReader) Read(p []byte) (int, error) {
+	if d.pos >= d.size {
+		return 0, io.EOF
+	}
+	index := d.pos / encChunkSize
+	if index != d.chunk {
+		if err := d.load(index); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, d.plain[d.pos-index*encChunkSize:])
+	d.pos += int64(n)
+	return n, nil
+}
+
+// load decrypts the chunk at index
+func (d *decryptReader) load(index int64) error {
+	if _, err := d.obj.Seek(int64(encHeaderSize)+index*encSealedChunk, io.SeekStart); err != nil {
+		return err
+	}
+	last := max(d.size-1, 0) / encChunkSize
+	sealed := make([]byte, min(d.size-index*encChunkSize, encChunkSize)+encTagSize)
+	if _, err := io.ReadFull(d.obj, sealed); err != nil {
+		return fmt.Errorf("read chunk: %w", err)
+	}
+	plain, err := d.aead.Open(d.plain[:0], chunkNonce(index, index == last), sealed, nil)
+	if err != nil {
+		d.chunk = -1
+		return fmt.Errorf("decrypt chunk %d: %w", index, err)
+	}
+	d.plain = plain
+	d.chunk = index
+	return nil
+}
+
+func (d *decryptReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += d.pos
+	case io.SeekEnd:
+		offset += d.size
+	default:
+		return 0, errors.New("invalid whence")
+	}
+	if offset < 0 {
+		return 0, errors.New("negative position")
+	}
+	d.pos = offset
+	return offset, nil
+}
+
+func (d *decryptReader) Close() error {
+	return d.obj.Close()
+}
@@ -0,0 +1,161 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"os"
+	"testing"
+)
+
+func TestEncryptedBackendRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	local := NewLocalBackend(t.TempDir())
+	b := NewEncryptedBackend(local, "secret")
+
+	// Sizes around the chunk boundaries
+	for _, size := range []int{0, 1, encChunkSize - 1, encChunkSize, encChunkSize + 1, 3*encChunkSize + 17} {
+		data := make([]byte, size)
+		r := rand.New(rand.NewPCG(uint64(size), 1))
+		for i := range data {
+			data[i] = byte(r.Uint32())
+		}
+
+		key := "originals/test.pdf"
+		if err := b.Put(ctx, key, bytes.NewReader(data), int64(size)); err != nil {
+			t.Fatalf("Put(%d bytes) failed: %v", size, err)
+		}
+
+		raw, _ := os.ReadFile(local.LocalPath(key))
+		if int64(len(raw)) != encryptedSize(int64(size)) {
+			t.Errorf("stored size = %d, want %d", len(raw), encryptedSize(int64(size)))
+		}
+		if size > 16 && bytes.Contains(raw, data[:16]) {
+			t.Errorf("stored object for %d bytes contains plaintext", size)
+		}
+
+		obj, info, err := b.Open(ctx, key)
+		if err != nil {
+			t.Fatalf("Open failed: %v", err)
+		}
+		if info.Size != int64(size) {
+			t.Errorf("info.Size = %d, want %d", info.Size, size)
+		}
+		got, err := io.ReadAll(obj)
+		if err != nil {
+			t.Fatalf("ReadAll(%d bytes) failed: %v", size, err)
+		}
+		if !bytes.Equal(got, data) {
+			t.Errorf("round trip of %d bytes returned different content", size)
+		}
+
+		// Seek across a chunk boundary
+		if size > encChunkSize+10 {
+			off := int64(encChunkSize - 3)
+			if _, err := obj.Seek(off, io.SeekStart); err != nil {
+				t.Fatalf("Seek failed: %v", err)
+			}
+			buf := make([]byte, 10)
+			if _, err := io.ReadFull(obj, buf); err != nil {
+				t.Fatalf("read after seek failed: %v", err)
+			}
+			if !bytes.Equal(buf, data[off:off+10]) {
+				t.Errorf("read after seek = %x, want %x", buf, data[off:off+10])
+			}
+		}
+		_ = obj.Close()
+	}
+}
+
+func TestEncryptedBackendRejectsTampering(t *testing.T) {
+	ctx := context.Background()
+	local := NewLocalBackend(t.TempDir())
+	key := "originals/test.pdf"
+	data := bytes.Repeat([]byte("x"), 2*encChunkSize+5)
+
+	if err := NewEncryptedBackend(local, "secret").Put(ctx, key, bytes.NewReader(data), int64(len(data))); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	if _, _, err := NewEncryptedBackend(local, "other").Open(ctx, key); !errors.Is(err, ErrWrongKey) {
+		t.Errorf("Open with wrong key error = %v, want ErrWrongKey", err)
+	}
+
+	// Dropping the final chunk must not read as a shorter file
+	path := local.LocalPath(key)
+	raw, _ := os.ReadFile(path)
+	if err := os.WriteFile(path, raw[:encHeaderSize+2*encSealedChunk], 0644); err != nil {
+		t.Fatal(err)
+	}
+	obj, _, err := NewEncryptedBackend(local, "secret").Open(ctx, key)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer func() { _ = obj.Close() }()
+	if _, err := io.ReadAll(obj); err == nil {
+		t.Error("ReadAll of truncated object succeeded, want error")
+	}
+}
+
+func TestEncryptExisting(t *testing.T) {
+	ctx := context.Background()
+	local := NewLocalBackend(t.TempDir())
+	plain := []byte("%PDF-1.4 existing")
+	keys := []string{"originals/ab/cd/one.pdf", "thumbnails/ab/cd/one.webp"}
+	for _, key := range keys {
+		if err := local.Put(ctx, key, bytes.NewReader(plain), int64(len(plain))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	b := NewEncryptedBackend(local, "secret")
+
+	// Plaintext objects stay readable before they are encrypted
+	obj, _, err := b.Open(ctx, keys[0])
+	if err != nil {
+		t.Fatalf("Open(plaintext) failed: %v", err)
+	}
+	got, _ := io.ReadAll(obj)
+	_ = obj.Close()
+	if !bytes.Equal(got, plain) {
+		t.Errorf("Open(plaintext) = %q, want %q", got, plain)
+	}
+
+	result, err := b.EncryptExisting(ctx, nil)
+	if err != nil {
+		t.Fatalf("EncryptExisting failed: %v", err)
+	}
+	if result.Encrypted != 2 || result.Skipped != 0 {
+		t.Errorf("result = %+v, want 2 encrypted", result)
+	}
+
+	for _, key := range keys {
+		raw, _ := os.ReadFile(local.LocalPath(key))
+		if !bytes.HasPrefix(raw, []byte(encMagic)) {
+			t.Errorf("%s was not encrypted", key)
+		}
+		obj, _, err := b.Open(ctx, key)
+		if err != nil {
+			t.Fatalf("Open(%s) failed: %v", key, err)
+		}
+		got, _ := io.ReadAll(obj)
+		_ = obj.Close()
+		if !bytes.Equal(got, plain) {
+			t.Errorf("%s = %q after encryption, want %q", key, got, plain)
+		}
+	}
+
+	// Re-running skips what is already encrypted
+	result, err = b.EncryptExisting(ctx, nil)
+	if err != nil {
+		t.Fatalf("EncryptExisting failed: %v", err)
+	}
+	if result.Encrypted != 0 || result.Skipped != 2 {
+		t.Errorf("second run result = %+v, want 2 skipped", result)
+	}
+	if left, _ := local.List(ctx, "originals/"); len(left) != 1 {
+		t.Errorf("originals after encryption = %v, want only the original key", left)
+	}
+}
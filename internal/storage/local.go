@@ -0,0 +1,144 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// LocalBackend keeps objects as files under a base directory
+type LocalBackend struct {
+	basePath string
+}
+
+// NewLocalBackend creates a LocalBackend rooted at basePath
+func NewLocalBackend(basePath string) *LocalBackend {
+	return &LocalBackend{basePath: basePath}
+}
+
+// EnsureDirectories creates the required directory structure
+func (b *LocalBackend) EnsureDirectories() error {
+	for _, cat := range Categories {
+		path := filepath.Join(b.basePath, cat)
+		if err := os.MkdirAll(path, 0755); err != nil {
+			return fmt.Errorf("failed to create %s directory: %w", cat, err)
+		}
+	}
+	return nil
+}
+
+// LocalPath returns the file path for a key
+func (b *LocalBackend) LocalPath(key string) string {
+	return filepath.Join(b.basePath, filepath.FromSlash(key))
+}
+
+// Put writes the object next to its destination and renames it into place
+// so readers never see a partial file
+func (b *LocalBackend) Put(_ context.Context, key string, r io.Reader, _ int64) error {
+	dst := b.LocalPath(key)
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+
+	out, err := os.CreateTemp(filepath.Dir(dst), ".put-*")
+	if err != nil {
+		return fmt.Errorf("create destination: %w", err)
+	}
+	tmp := out.Name()
+	defer func() { _ = os.Remove(tmp) }()
+
+	if _, err := io.Copy(out, r); err != nil {
+		_ = out.Close()
+		return fmt.Errorf("copy file: %w", err)
+	}
+	if err := out.Sync(); err != nil {
+		_ = out.Close()
+		return fmt.Errorf("sync file: %w", err)
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("close file: %w", err)
+	}
+	if err := os.Chmod(tmp, 0644); err != nil {
+		return fmt.Errorf("chmod file: %w", err)
+	}
+	return os.Rename(tmp, dst)
+}
+
+// Open opens the file for key
+func (b *LocalBackend) Open(_ context.Context, key string) (Object, ObjectInfo, error) {
+	f, err := os.Open(b.LocalPath(key))
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, ObjectInfo{}, err
+	}
+	return f, ObjectInfo{Size: info.Size(), ModTime: info.ModTime()}, nil
+}
+
+// Stat describes the file for key
+func (b *LocalBackend) Stat(_ context.Context, key string) (ObjectInfo, error) {
+	info, err := os.Stat(b.LocalPath(key))
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	return ObjectInfo{Size: info.Size(), ModTime: info.ModTime()}, nil
+}
+
+// Delete removes the file for key
+func (b *LocalBackend) Delete(_ context.Context, key string) error {
+	if err := os.Remove(b.LocalPath(key)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Move renames the file for src to dst
+func (b *LocalBackend) Move(_ context.Context, src, dst string) error {
+	dstPath := b.LocalPath(dst)
+	if err := os.MkdirAll(filepath.Dir(dstPath), 0755); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+	return os.Rename(b.LocalPath(src), dstPath)
+}
+
+// List returns the keys that start with prefix
+func (b *LocalBackend) List(_ context.Context, prefix string) ([]string, error) {
+	// Walk from the deepest directory the prefix names
+	dir := path.Dir(prefix)
+	if strings.HasSuffix(prefix, "/") {
+		dir = strings.TrimSuffix(prefix, "/")
+	}
+
+	var keys []string
+	err := filepath.WalkDir(b.LocalPath(dir), func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(b.basePath, p)
+		if err != nil {
+			return err
+		}
+		if key := filepath.ToSlash(rel); strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
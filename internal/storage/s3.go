@@ -0,0 +1,147 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"path"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3Config configures an S3-compatible object store
+type S3Config struct {
+	Endpoint  string // host[:port], e.g. s3.amazonaws.com or minio:9000
+	Bucket    string
+	Region    string
+	AccessKey string
+	SecretKey string
+	Prefix    string // Optional key prefix, for sharing a bucket
+	UseSSL    bool
+	PathStyle bool // Address the bucket in the path rather than the host name (MinIO and most self-hosted stores)
+}
+
+// S3Backend keeps objects in an S3-compatible bucket
+type S3Backend struct {
+	client *minio.Client
+	bucket string
+	prefix string
+}
+
+// NewS3Backend connects to the bucket, creating it if it doesn't exist
+func NewS3Backend(ctx context.Context, cfg S3Config) (*S3Backend, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" {
+		return nil, fmt.Errorf("s3 endpoint and bucket are required")
+	}
+
+	lookup := minio.BucketLookupAuto
+	if cfg.PathStyle {
+		lookup = minio.BucketLookupPath
+	}
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:        credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Secure:       cfg.UseSSL,
+		Region:       cfg.Region,
+		BucketLookup: lookup,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create s3 client: %w", err)
+	}
+
+	exists, err := client.BucketExists(ctx, cfg.Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("check bucket: %w", err)
+	}
+	if !exists {
+		if err := client.MakeBucket(ctx, cfg.Bucket, minio.MakeBucketOptions{Region: cfg.Region}); err != nil {
+			return nil, fmt.Errorf("create bucket: %w", err)
+		}
+	}
+
+	prefix := strings.Trim(cfg.Prefix, "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+	return &S3Backend{client: client, bucket: cfg.Bucket, prefix: prefix}, nil
+}
+
+func (b *S3Backend) object(key string) string {
+	return b.prefix + key
+}
+
+// Put uploads the object
+func (b *S3Backend) Put(ctx context.Context, key string, r io.Reader, size int64) error {
+	_, err := b.client.PutObject(ctx, b.bucket, b.object(key), r, size, minio.PutObjectOptions{
+		ContentType: mime.TypeByExtension(path.Ext(key)),
+	})
+	return err
+}
+
+// Open returns a seekable reader for the object
+func (b *S3Backend) Open(ctx context.Context, key string) (Object, ObjectInfo, error) {
+	obj, err := b.client.GetObject(ctx, b.bucket, b.object(key), minio.GetObjectOptions{})
+	if err != nil {
+		return nil, ObjectInfo{}, s3Error(key, err)
+	}
+	// GetObject is lazy; Stat makes the request and surfaces a missing key
+	info, err := obj.Stat()
+	if err != nil {
+		_ = obj.Close()
+		return nil, ObjectInfo{}, s3Error(key, err)
+	}
+	return obj, ObjectInfo{Size: info.Size, ModTime: info.LastModified}, nil
+}
+
+// Stat describes the object
+func (b *S3Backend) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	info, err := b.client.StatObject(ctx, b.bucket, b.object(key), minio.StatObjectOptions{})
+	if err != nil {
+		return ObjectInfo{}, s3Error(key, err)
+	}
+	return ObjectInfo{Size: info.Size, ModTime: info.LastModified}, nil
+}
+
+// Delete removes the object; S3 doesn't report missing keys on delete
+func (b *S3Backend) Delete(ctx context.Context, key string) error {
+	return b.client.RemoveObject(ctx, b.bucket, b.object(key), minio.RemoveObjectOptions{})
+}
+
+// Move copies the object server-side and removes the source
+func (b *S3Backend) Move(ctx context.Context, src, dst string) error {
+	_, err := b.client.CopyObject(ctx,
+		minio.CopyDestOptions{Bucket: b.bucket, Object: b.object(dst)},
+		minio.CopySrcOptions{Bucket: b.bucket, Object: b.object(src)},
+	)
+	if err != nil {
+		return s3Error(src, err)
+	}
+	return b.Delete(ctx, src)
+}
+
+// List returns the keys that start with prefix
+func (b *S3Backend) List(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	for info := range b.client.ListObjects(ctx, b.bucket, minio.ListObjectsOptions{
+		Prefix:    b.object(prefix),
+		Recursive: true,
+	}) {
+		if info.Err != nil {
+			return nil, info.Err
+		}
+		keys = append(keys, strings.TrimPrefix(info.Key, b.prefix))
+	}
+	return keys, nil
+}
+
+// s3Error maps missing keys to fs.ErrNotExist
+func s3Error(key string, err error) error {
+	switch minio.ToErrorResponse(err).Code {
+	case "NoSuchKey", "NotFound":
+		return fmt.Errorf("%s: %w", key, fs.ErrNotExist)
+	}
+	return err
+}
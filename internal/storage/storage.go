@@ -1,18 +1,57 @@
 package storage
 
 import (
+	"context"
 	"crypto/sha256"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
-	"path/filepath"
+	"path"
+	"time"
 
 	"github.com/google/uuid"
 )
 
-// Storage handles file system operations for documents
+// Backend stores document files as objects named by slash-separated keys
+// such as originals/ab/c1/abc12345-....pdf
+type Backend interface {
+	// Put stores size bytes from r under key, replacing any existing object
+	Put(ctx context.Context, key string, r io.Reader, size int64) error
+	// Open returns the object at key; errors wrap fs.ErrNotExist when it is missing
+	Open(ctx context.Context, key string) (Object, ObjectInfo, error)
+	// Stat describes the object at key; errors wrap fs.ErrNotExist when it is missing
+	Stat(ctx context.Context, key string) (ObjectInfo, error)
+	// Delete removes the object at key; a missing object is not an error
+	Delete(ctx context.Context, key string) error
+	// Move renames the object at src to dst, replacing any existing object
+	Move(ctx context.Context, src, dst string) error
+	// List returns the keys that start with prefix
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+// Object is an open stored file
+type Object interface {
+	io.ReadSeekCloser
+}
+
+// ObjectInfo describes a stored file
+type ObjectInfo struct {
+	Size    int64
+	ModTime time.Time
+}
+
+// localPather is implemented by backends whose objects are plain files,
+// letting Fetch hand out the file itself instead of a temp copy
+type localPather interface {
+	LocalPath(key string) string
+}
+
+// Storage handles document file operations on top of a Backend
 type Storage struct {
-	basePath string
+	backend Backend
+	workDir string
 }
 
 // Categories for different file types
@@ -20,87 +59,83 @@ const (
 	CategoryOriginals  = "originals"
 	CategoryThumbnails = "thumbnails"
 	CategoryText       = "text"
+	CategoryRenditions = "renditions"
+	CategoryArchive    = "archive" // Searchable PDF/A produced by OCR
 )
 
-// New creates a Storage instance with the given base path
+// Categories lists every storage category
+var Categories = []string{CategoryOriginals, CategoryThumbnails, CategoryText, CategoryRenditions, CategoryArchive}
+
+// New creates a Storage instance keeping files on the local filesystem under basePath
 func New(basePath string) (*Storage, error) {
 	if basePath == "" {
 		return nil, fmt.Errorf("storage path cannot be empty")
 	}
 
-	s := &Storage{basePath: basePath}
+	backend := NewLocalBackend(basePath)
 
 	// Ensure base directories exist
-	if err := s.EnsureDirectories(); err != nil {
+	if err := backend.EnsureDirectories(); err != nil {
 		return nil, fmt.Errorf("failed to create directories: %w", err)
 	}
 
-	return s, nil
+	return NewWithBackend(backend, basePath)
 }
 
-// EnsureDirectories creates the required directory structure
-func (s *Storage) EnsureDirectories() error {
-	categories := []string{CategoryOriginals, CategoryThumbnails, CategoryText}
-	for _, cat := range categories {
-		path := filepath.Join(s.basePath, cat)
-		if err := os.MkdirAll(path, 0755); err != nil {
-			return fmt.Errorf("failed to create %s directory: %w", cat, err)
-		}
+// NewWithBackend creates a Storage instance on backend
+// workDir is local scratch space for work that needs real files, such as
+// the OCR volumes; it doesn't need to survive restarts.
+func NewWithBackend(backend Backend, workDir string) (*Storage, error) {
+	if workDir == "" {
+		return nil, fmt.Errorf("storage work directory cannot be empty")
 	}
-	return nil
+	if err := os.MkdirAll(workDir, 0755); err != nil {
+		return nil, fmt.Errorf("create work directory: %w", err)
+	}
+	return &Storage{backend: backend, workDir: workDir}, nil
 }
 
-// PathForUUID returns the full file path for a UUID in a category
+// KeyForUUID returns the storage key for a UUID in a category
 // Uses 2-level sharding: ab/c1/abc12345-...
-func (s *Storage) PathForUUID(category string, id uuid.UUID, ext string) string {
+func KeyForUUID(category string, id uuid.UUID, ext string) string {
 	str := id.String()
-	return filepath.Join(s.basePath, category, str[0:2], str[2:4], str+ext)
+	return path.Join(category, str[0:2], str[2:4], str+ext)
 }
 
-// DirForUUID returns the directory path for a UUID in a category
-func (s *Storage) DirForUUID(category string, id uuid.UUID) string {
-	str := id.String()
-	return filepath.Join(s.basePath, category, str[0:2], str[2:4])
+// Store copies a local file into storage under key
+func (s *Storage) Store(ctx context.Context, key, srcPath string) error {
+	_, _, err := s.StoreAndHash(ctx, key, srcPath)
+	return err
 }
 
-// CopyAndHash copies a file to destination while computing SHA256 hash
+// StoreAndHash copies a local file into storage while computing its SHA256 hash
 // Returns the hex-encoded hash, file size, and any error
-func (s *Storage) CopyAndHash(dst, src string) (string, int64, error) {
-	in, err := os.Open(src)
+func (s *Storage) StoreAndHash(ctx context.Context, key, srcPath string) (string, int64, error) {
+	in, err := os.Open(srcPath)
 	if err != nil {
 		return "", 0, fmt.Errorf("open source: %w", err)
 	}
 	defer func() { _ = in.Close() }()
 
-	// Ensure destination directory exists
-	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
-		return "", 0, fmt.Errorf("create directory: %w", err)
-	}
-
-	out, err := os.Create(dst)
+	info, err := in.Stat()
 	if err != nil {
-		return "", 0, fmt.Errorf("create destination: %w", err)
+		return "", 0, fmt.Errorf("stat source: %w", err)
 	}
-	defer func() { _ = out.Close() }()
 
 	hash := sha256.New()
-	tee := io.TeeReader(in, hash)
-
-	size, err := io.Copy(out, tee)
-	if err != nil {
-		// Clean up partial file
-		_ = os.Remove(dst)
-		return "", 0, fmt.Errorf("copy file: %w", err)
+	if err := s.backend.Put(ctx, key, io.TeeReader(in, hash), info.Size()); err != nil {
+		return "", 0, fmt.Errorf("store file: %w", err)
 	}
 
-	if err := out.Sync(); err != nil {
-		return "", 0, fmt.Errorf("sync file: %w", err)
-	}
+	return fmt.Sprintf("%x", hash.Sum(nil)), info.Size(), nil
+}
 
-	return fmt.Sprintf("%x", hash.Sum(nil)), size, nil
+// Put stores size bytes from r under key
+func (s *Storage) Put(ctx context.Context, key string, r io.Reader, size int64) error {
+	return s.backend.Put(ctx, key, r, size)
 }
 
-// HashFile computes SHA256 hash of a file without copying
+// HashFile computes SHA256 hash of a local file without copying
 func (s *Storage) HashFile(path string) (string, error) {
 	f, err := os.Open(path)
 	if err != nil {
@@ -108,26 +143,115 @@ func (s *Storage) HashFile(path string) (string, error) {
 	}
 	defer func() { _ = f.Close() }()
 
+	return hashReader(f)
+}
+
+// Hash computes the SHA256 hash of a stored file, streaming it from the backend
+func (s *Storage) Hash(ctx context.Context, key string) (string, error) {
+	obj, _, err := s.backend.Open(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = obj.Close() }()
+
+	return hashReader(obj)
+}
+
+func hashReader(r io.Reader) (string, error) {
 	h := sha256.New()
-	if _, err := io.Copy(h, f); err != nil {
+	if _, err := io.Copy(h, r); err != nil {
 		return "", fmt.Errorf("hash file: %w", err)
 	}
 
 	return fmt.Sprintf("%x", h.Sum(nil)), nil
 }
 
-// FileExists checks if a file exists at the given path
-func (s *Storage) FileExists(path string) bool {
-	_, err := os.Stat(path)
+// Open opens a stored file for reading
+func (s *Storage) Open(ctx context.Context, key string) (Object, ObjectInfo, error) {
+	return s.backend.Open(ctx, key)
+}
+
+// Fetch makes a stored file available at a local path for tools that need
+// one, such as pdfcpu and the command-line converters. release must be
+// called when done; it removes the temp copy, if one was made.
+func (s *Storage) Fetch(ctx context.Context, key string) (string, func(), error) {
+	if lp, ok := s.backend.(localPather); ok {
+		p := lp.LocalPath(key)
+		if _, err := os.Stat(p); err != nil {
+			return "", nil, err
+		}
+		return p, func() {}, nil
+	}
+
+	obj, _, err := s.backend.Open(ctx, key)
+	if err != nil {
+		return "", nil, err
+	}
+	defer func() { _ = obj.Close() }()
+
+	// Keep the extension; several tools decide what to do by it
+	tmp, err := os.CreateTemp("", "fetch-*"+path.Ext(key))
+	if err != nil {
+		return "", nil, fmt.Errorf("create temp file: %w", err)
+	}
+	release := func() { _ = os.Remove(tmp.Name()) }
+
+	if _, err := io.Copy(tmp, obj); err != nil {
+		_ = tmp.Close()
+		release()
+		return "", nil, fmt.Errorf("fetch %s: %w", key, err)
+	}
+	if err := tmp.Close(); err != nil {
+		release()
+		return "", nil, fmt.Errorf("fetch %s: %w", key, err)
+	}
+	return tmp.Name(), release, nil
+}
+
+// FileExists checks if a file is stored under key
+func (s *Storage) FileExists(ctx context.Context, key string) bool {
+	_, err := s.backend.Stat(ctx, key)
 	return err == nil
 }
 
-// Delete removes a file at the given path
-func (s *Storage) Delete(path string) error {
-	return os.Remove(path)
+// Delete removes a stored file
+func (s *Storage) Delete(ctx context.Context, key string) error {
+	return s.backend.Delete(ctx, key)
+}
+
+// List returns the keys of stored files that start with prefix
+func (s *Storage) List(ctx context.Context, prefix string) ([]string, error) {
+	return s.backend.List(ctx, prefix)
+}
+
+// Move renames a stored file
+func (s *Storage) Move(ctx context.Context, src, dst string) error {
+	return s.backend.Move(ctx, src, dst)
+}
+
+// DeleteAllForUUID removes every file stored for a UUID across all categories
+// Missing files are not an error.
+func (s *Storage) DeleteAllForUUID(ctx context.Context, id uuid.UUID) error {
+	for _, cat := range Categories {
+		keys, err := s.backend.List(ctx, KeyForUUID(cat, id, ""))
+		if err != nil {
+			return fmt.Errorf("list %s files: %w", cat, err)
+		}
+		for _, key := range keys {
+			if err := s.backend.Delete(ctx, key); err != nil {
+				return fmt.Errorf("delete %s: %w", key, err)
+			}
+		}
+	}
+	return nil
+}
+
+// WorkDir returns the local scratch directory
+func (s *Storage) WorkDir() string {
+	return s.workDir
 }
 
-// BasePath returns the storage base path
-func (s *Storage) BasePath() string {
-	return s.basePath
+// IsNotExist reports whether err means a stored file is missing
+func IsNotExist(err error) bool {
+	return errors.Is(err, fs.ErrNotExist)
 }
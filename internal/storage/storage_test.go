@@ -0,0 +1,165 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/johannesboyne/gofakes3"
+	"github.com/johannesboyne/gofakes3/backend/s3mem"
+)
+
+// newFakeS3 starts an in-memory S3-compatible server and returns a backend for it
+func newFakeS3(t *testing.T, prefix string) *S3Backend {
+	t.Helper()
+
+	ts := httptest.NewServer(gofakes3.New(s3mem.New()).Server())
+	t.Cleanup(ts.Close)
+
+	backend, err := NewS3Backend(context.Background(), S3Config{
+		Endpoint:  strings.TrimPrefix(ts.URL, "http://"),
+		Bucket:    "docko",
+		Region:    "us-east-1",
+		AccessKey: "test",
+		SecretKey: "test",
+		Prefix:    prefix,
+		PathStyle: true,
+	})
+	if err != nil {
+		t.Fatalf("NewS3Backend failed: %v", err)
+	}
+	return backend
+}
+
+func TestStorageBackends(t *testing.T) {
+	backends := map[string]func(t *testing.T) *Storage{
+		"local": func(t *testing.T) *Storage {
+			s, err := New(t.TempDir())
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+			return s
+		},
+		"s3": func(t *testing.T) *Storage {
+			s, err := NewWithBackend(newFakeS3(t, "archive"), t.TempDir())
+			if err != nil {
+				t.Fatalf("NewWithBackend failed: %v", err)
+			}
+			return s
+		},
+		"encrypted-s3": func(t *testing.T) *Storage {
+			s, err := NewWithBackend(NewEncryptedBackend(newFakeS3(t, ""), "secret"), t.TempDir())
+			if err != nil {
+				t.Fatalf("NewWithBackend failed: %v", err)
+			}
+			return s
+		},
+	}
+
+	for name, newStorage := range backends {
+		t.Run(name, func(t *testing.T) {
+			testStorage(t, newStorage(t))
+		})
+	}
+}
+
+func testStorage(t *testing.T, s *Storage) {
+	ctx := context.Background()
+	id := uuid.New()
+	content := "%PDF-1.4 hello"
+
+	src := filepath.Join(t.TempDir(), "in.pdf")
+	if err := os.WriteFile(src, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	key := KeyForUUID(CategoryOriginals, id, ".pdf")
+	hash, size, err := s.StoreAndHash(ctx, key, src)
+	if err != nil {
+		t.Fatalf("StoreAndHash failed: %v", err)
+	}
+	if size != int64(len(content)) {
+		t.Errorf("size = %d, want %d", size, len(content))
+	}
+	if want, _ := s.HashFile(src); hash != want {
+		t.Errorf("hash = %s, want %s", hash, want)
+	}
+	if !s.FileExists(ctx, key) {
+		t.Error("FileExists = false after store")
+	}
+
+	// Open supports seeking, which range requests rely on
+	obj, info, err := s.Open(ctx, key)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if info.Size != int64(len(content)) {
+		t.Errorf("info.Size = %d, want %d", info.Size, len(content))
+	}
+	if _, err := obj.Seek(5, io.SeekStart); err != nil {
+		t.Fatalf("Seek failed: %v", err)
+	}
+	rest, _ := io.ReadAll(obj)
+	_ = obj.Close()
+	if string(rest) != content[5:] {
+		t.Errorf("read after seek = %q, want %q", rest, content[5:])
+	}
+
+	// Fetch stages the file locally with its extension
+	local, release, err := s.Fetch(ctx, key)
+	if err != nil {
+		t.Fatalf("Fetch failed: %v", err)
+	}
+	if data, _ := os.ReadFile(local); string(data) != content {
+		t.Errorf("fetched content = %q, want %q", data, content)
+	}
+	if filepath.Ext(local) != ".pdf" {
+		t.Errorf("fetched path %q lost its extension", local)
+	}
+	release()
+
+	// Move and list
+	moved := KeyForUUID(CategoryOriginals, id, ".v1.pdf")
+	if err := s.Move(ctx, key, moved); err != nil {
+		t.Fatalf("Move failed: %v", err)
+	}
+	if s.FileExists(ctx, key) || !s.FileExists(ctx, moved) {
+		t.Error("Move didn't rename the file")
+	}
+	if err := s.Store(ctx, KeyForUUID(CategoryThumbnails, id, ".webp"), src); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+	other := KeyForUUID(CategoryOriginals, uuid.New(), ".pdf")
+	if err := s.Store(ctx, other, src); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+
+	if err := s.DeleteAllForUUID(ctx, id); err != nil {
+		t.Fatalf("DeleteAllForUUID failed: %v", err)
+	}
+	for _, cat := range Categories {
+		if keys, _ := s.backend.List(ctx, KeyForUUID(cat, id, "")); len(keys) != 0 {
+			t.Errorf("%s files left after DeleteAllForUUID: %v", cat, keys)
+		}
+	}
+	if keys, _ := s.backend.List(ctx, CategoryOriginals+"/"); !slices.Contains(keys, other) {
+		t.Errorf("List(originals/) = %v, want it to include %s", keys, other)
+	}
+
+	// Missing files
+	if _, _, err := s.Open(ctx, key); !IsNotExist(err) {
+		t.Errorf("Open(missing) error = %v, want not-exist", err)
+	}
+	if _, _, err := s.Fetch(ctx, key); !IsNotExist(err) {
+		t.Errorf("Fetch(missing) error = %v, want not-exist", err)
+	}
+	if err := s.Delete(ctx, key); err != nil {
+		t.Errorf("Delete(missing) error = %v, want nil", err)
+	}
+}
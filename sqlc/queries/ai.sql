@@ -35,12 +35,15 @@ ORDER BY confidence DESC, created_at DESC;
 SELECT s.*, d.original_filename
 FROM ai_suggestions s
 JOIN documents d ON s.document_id = d.id
-WHERE s.status = 'pending'
+WHERE s.status = 'pending' AND d.deleted_at IS NULL
 ORDER BY s.created_at DESC
 LIMIT $1 OFFSET $2;
 
 -- name: CountPendingSuggestions :one
-SELECT COUNT(*) FROM ai_suggestions WHERE status = 'pending';
+SELECT COUNT(*)
+FROM ai_suggestions s
+JOIN documents d ON s.document_id = d.id
+WHERE s.status = 'pending' AND d.deleted_at IS NULL;
 
 -- name: ListPendingSuggestionsForDocument :many
 SELECT * FROM ai_suggestions
@@ -73,7 +76,12 @@ WHERE id = $1
 RETURNING *;
 
 -- name: DeleteDocumentSuggestions :exec
-DELETE FROM ai_suggestions WHERE document_id = $1;
+-- Date suggestions come from processing, not AI analysis, so they're kept
+DELETE FROM ai_suggestions WHERE document_id = $1 AND suggestion_type != 'date';
+
+-- name: DeletePendingDateSuggestions :exec
+DELETE FROM ai_suggestions
+WHERE document_id = $1 AND suggestion_type = 'date' AND status = 'pending';
 
 -- AI Usage tracking
 
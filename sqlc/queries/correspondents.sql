@@ -1,13 +1,19 @@
 -- name: ListCorrespondentsWithCounts :many
-SELECT c.id, c.name, c.notes, c.created_at, COUNT(dc.document_id)::int AS document_count
+SELECT c.id, c.name, c.notes, c.created_at, COUNT(d.id)::int AS document_count
 FROM correspondents c
 LEFT JOIN document_correspondents dc ON dc.correspondent_id = c.id
+LEFT JOIN documents d ON d.id = dc.document_id AND d.deleted_at IS NULL
 GROUP BY c.id
 ORDER BY c.name;
 
 -- name: GetCorrespondent :one
 SELECT id, name, notes, created_at FROM correspondents WHERE id = $1;
 
+-- name: GetCorrespondentByName :one
+SELECT id, name, notes, created_at FROM correspondents
+WHERE lower(name) = lower($1)
+LIMIT 1;
+
 -- name: CreateCorrespondent :one
 INSERT INTO correspondents (name, notes)
 VALUES ($1, $2)
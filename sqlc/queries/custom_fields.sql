@@ -0,0 +1,52 @@
+-- name: ListCustomFieldsWithCounts :many
+SELECT f.id, f.name, f.field_type, f.created_at, COUNT(dcf.document_id)::int AS document_count
+FROM custom_fields f
+LEFT JOIN document_custom_fields dcf ON f.id = dcf.field_id
+GROUP BY f.id
+ORDER BY f.name;
+
+-- name: ListCustomFields :many
+SELECT * FROM custom_fields ORDER BY name;
+
+-- name: GetCustomField :one
+SELECT * FROM custom_fields WHERE id = $1;
+
+-- name: CreateCustomField :one
+INSERT INTO custom_fields (name, field_type)
+VALUES ($1, $2)
+ON CONFLICT (name) DO NOTHING
+RETURNING *;
+
+-- name: RenameCustomField :one
+UPDATE custom_fields
+SET name = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteCustomField :exec
+DELETE FROM custom_fields WHERE id = $1;
+
+-- name: ListDocumentCustomFields :many
+SELECT f.id AS field_id, f.name, f.field_type,
+    dcf.value_text, dcf.value_number, dcf.value_date, dcf.value_bool, dcf.value_document_id,
+    linked.original_filename AS linked_filename, linked.title AS linked_title
+FROM document_custom_fields dcf
+INNER JOIN custom_fields f ON f.id = dcf.field_id
+LEFT JOIN documents linked ON linked.id = dcf.value_document_id
+WHERE dcf.document_id = $1
+ORDER BY f.name;
+
+-- name: SetDocumentCustomField :exec
+INSERT INTO document_custom_fields (document_id, field_id, value_text, value_number, value_date, value_bool, value_document_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (document_id, field_id) DO UPDATE SET
+    value_text = EXCLUDED.value_text,
+    value_number = EXCLUDED.value_number,
+    value_date = EXCLUDED.value_date,
+    value_bool = EXCLUDED.value_bool,
+    value_document_id = EXCLUDED.value_document_id,
+    updated_at = NOW();
+
+-- name: RemoveDocumentCustomField :exec
+DELETE FROM document_custom_fields
+WHERE document_id = $1 AND field_id = $2;
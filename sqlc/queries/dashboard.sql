@@ -5,7 +5,8 @@ SELECT
     COUNT(*) FILTER (WHERE processing_status = 'pending')::int AS pending,
     COUNT(*) FILTER (WHERE processing_status = 'failed')::int AS failed,
     COUNT(*) FILTER (WHERE created_at >= CURRENT_DATE)::int AS today
-FROM documents;
+FROM documents
+WHERE deleted_at IS NULL;
 
 -- name: GetDashboardQueueStats :one
 SELECT
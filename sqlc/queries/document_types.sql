@@ -0,0 +1,49 @@
+-- name: ListDocumentTypesWithCounts :many
+SELECT t.id, t.name, t.created_at, COUNT(d.id)::int AS document_count
+FROM document_types t
+LEFT JOIN documents d ON d.document_type_id = t.id AND d.deleted_at IS NULL
+GROUP BY t.id
+ORDER BY t.name;
+
+-- name: ListDocumentTypes :many
+SELECT * FROM document_types ORDER BY name;
+
+-- name: GetDocumentType :one
+SELECT * FROM document_types WHERE id = $1;
+
+-- name: GetDocumentTypeByName :one
+SELECT * FROM document_types
+WHERE lower(name) = lower($1)
+LIMIT 1;
+
+-- name: CreateDocumentType :one
+INSERT INTO document_types (name)
+VALUES ($1)
+ON CONFLICT (name) DO NOTHING
+RETURNING *;
+
+-- name: UpdateDocumentType :one
+UPDATE document_types
+SET name = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteDocumentType :exec
+DELETE FROM document_types WHERE id = $1;
+
+-- name: GetDocumentTypeForDocument :one
+SELECT t.* FROM document_types t
+INNER JOIN documents d ON d.document_type_id = t.id
+WHERE d.id = $1;
+
+-- name: SetDocumentType :exec
+UPDATE documents
+SET document_type_id = sqlc.narg(document_type_id), updated_at = NOW()
+WHERE id = sqlc.arg(id);
+
+-- name: CopyDocumentType :exec
+-- Gives the target the source's type unless it already has one
+UPDATE documents
+SET document_type_id = (SELECT src.document_type_id FROM documents src WHERE src.id = @source_id::uuid),
+    updated_at = NOW()
+WHERE documents.id = @target_id::uuid AND documents.document_type_id IS NULL;
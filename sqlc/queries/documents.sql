@@ -1,16 +1,24 @@
 -- name: CreateDocument :one
-INSERT INTO documents (id, original_filename, content_hash, file_size, page_count, pdf_title, pdf_author, pdf_created_at, document_date)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, COALESCE($9, NOW()))
+INSERT INTO documents (id, original_filename, content_hash, file_size, page_count, pdf_title, pdf_author, pdf_created_at, document_date, content_type, parent_document_id, date_confidence)
+VALUES (@id, @original_filename, @content_hash, @file_size, @page_count, @pdf_title, @pdf_author, @pdf_created_at,
+    COALESCE(sqlc.narg(document_date)::timestamptz, NOW()), @content_type, @parent_document_id, @date_confidence)
 RETURNING *;
 
 -- name: GetDocument :one
 SELECT * FROM documents WHERE id = $1;
 
+-- name: LockDocument :one
+-- Locks a document's row until the transaction ends, serializing edits of its files
+SELECT * FROM documents WHERE id = $1 FOR UPDATE;
+
 -- name: GetDocumentByHash :one
 SELECT * FROM documents WHERE content_hash = $1;
 
+-- name: ListChildDocuments :many
+SELECT * FROM documents WHERE parent_document_id = $1 ORDER BY created_at;
+
 -- name: ListDocuments :many
-SELECT * FROM documents ORDER BY created_at DESC LIMIT $1 OFFSET $2;
+SELECT * FROM documents WHERE deleted_at IS NULL ORDER BY created_at DESC LIMIT $1 OFFSET $2;
 
 -- name: UpdateDocument :one
 UPDATE documents SET
@@ -19,9 +27,76 @@ UPDATE documents SET
 WHERE id = $1
 RETURNING *;
 
+-- name: UpdateDocumentDetails :one
+UPDATE documents SET
+  title = $2,
+  notes = $3,
+  updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: ReplaceDocumentFile :one
+UPDATE documents SET
+    original_filename = $2,
+    content_hash = $3,
+    file_size = $4,
+    content_type = $5,
+    revision = revision + 1,
+    edit_version = 0,
+    archive_checksum = NULL,
+    page_count = NULL,
+    pdf_title = NULL,
+    pdf_author = NULL,
+    pdf_created_at = NULL,
+    text_content = NULL,
+    thumbnail_generated = false,
+    processing_status = 'pending',
+    processing_error = NULL,
+    processed_at = NULL,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: SetDocumentEditVersion :one
+UPDATE documents SET
+    edit_version = $2,
+    page_count = $3,
+    archive_checksum = NULL,
+    text_content = NULL,
+    thumbnail_generated = false,
+    processing_status = 'pending',
+    processing_error = NULL,
+    processed_at = NULL,
+    updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: SetDocumentArchive :exec
+UPDATE documents SET archive_checksum = $2, updated_at = NOW()
+WHERE id = $1;
+
 -- name: DeleteDocument :exec
 DELETE FROM documents WHERE id = $1;
 
+-- name: TrashDocument :one
+UPDATE documents SET deleted_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING *;
+
+-- name: RestoreDocument :one
+UPDATE documents SET deleted_at = NULL, updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: ListTrashedDocuments :many
+SELECT * FROM documents WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC;
+
+-- name: ListTrashedDocumentsBefore :many
+SELECT * FROM documents WHERE deleted_at IS NOT NULL AND deleted_at < $1 ORDER BY deleted_at;
+
+-- name: CountTrashedDocuments :one
+SELECT COUNT(*)::int FROM documents WHERE deleted_at IS NOT NULL;
+
 -- name: CreateDocumentEvent :one
 INSERT INTO document_events (document_id, event_type, payload, error_message, duration_ms)
 VALUES ($1, $2, $3, $4, $5)
@@ -44,12 +119,45 @@ UPDATE documents SET
 WHERE id = $1
 RETURNING *;
 
+-- name: UpdateDocumentMetadata :exec
+-- An unset title is pre-filled from the PDF title when an upload is first
+-- processed, so a title cleared by hand stays cleared on reprocessing
+UPDATE documents SET
+    page_count = $2,
+    pdf_title = $3,
+    title = CASE WHEN processed_at IS NULL AND revision = 1 AND edit_version = 0
+        THEN COALESCE(title, $3) ELSE title END,
+    pdf_author = $4,
+    pdf_created_at = $5,
+    updated_at = NOW()
+WHERE id = $1;
+
+-- name: SetDetectedDocumentDate :exec
+-- Dates from the source or a person (confidence 1.00) are never overwritten
+UPDATE documents SET
+    document_date = $2,
+    date_confidence = $3,
+    updated_at = NOW()
+WHERE id = $1 AND (date_confidence IS NULL OR date_confidence < 1);
+
+-- name: SetDocumentDate :exec
+UPDATE documents SET
+    document_date = $2,
+    date_confidence = 1,
+    updated_at = NOW()
+WHERE id = $1;
+
 -- name: GetPendingProcessingDocuments :many
 SELECT * FROM documents
 WHERE processing_status = 'pending'
 ORDER BY created_at ASC
 LIMIT $1;
 
+-- name: LockName :exec
+-- Serializes find-or-create of a named tag, correspondent or document type
+-- across concurrent ingests; the lock is held until the transaction ends
+SELECT pg_advisory_xact_lock(@kind::int, hashtext(lower(@name::text)));
+
 -- name: SetDocumentProcessingStatus :one
 UPDATE documents SET
     processing_status = $2,
@@ -62,6 +170,7 @@ SELECT d.*, c.id as correspondent_id, c.name as correspondent_name
 FROM documents d
 LEFT JOIN document_correspondents dc ON dc.document_id = d.id
 LEFT JOIN correspondents c ON c.id = dc.correspondent_id
+WHERE d.deleted_at IS NULL
 ORDER BY d.created_at DESC
 LIMIT $1 OFFSET $2;
 
@@ -70,6 +179,7 @@ SELECT
     d.*,
     c.id as correspondent_id,
     c.name as correspondent_name,
+    dty.name as document_type_name,
     CASE WHEN sqlc.narg(query)::text IS NOT NULL AND sqlc.narg(query)::text != ''
          THEN ts_rank(d.search_vector, websearch_to_tsquery('english', sqlc.narg(query)::text))
          ELSE 0 END as rank,
@@ -80,15 +190,23 @@ SELECT
 FROM documents d
 LEFT JOIN document_correspondents dc ON dc.document_id = d.id
 LEFT JOIN correspondents c ON c.id = dc.correspondent_id
+LEFT JOIN document_types dty ON dty.id = d.document_type_id
 WHERE
+    -- Trashed documents are only listed on the trash page
+    d.deleted_at IS NULL
     -- Full-text search (optional - empty/null matches all)
-    (sqlc.narg(query)::text IS NULL OR sqlc.narg(query)::text = ''
+    AND (sqlc.narg(query)::text IS NULL OR sqlc.narg(query)::text = ''
         OR d.search_vector @@ websearch_to_tsquery('english', sqlc.narg(query)::text))
     -- Correspondent filter (optional)
     AND (NOT sqlc.arg(has_correspondent)::boolean OR c.id = sqlc.arg(correspondent_id)::uuid)
+    -- Document type filter (optional)
+    AND (NOT sqlc.arg(has_document_type)::boolean OR d.document_type_id = sqlc.arg(document_type_id)::uuid)
     -- Date range filter (optional)
     AND (NOT sqlc.arg(has_date_from)::boolean OR d.document_date >= sqlc.arg(date_from)::timestamptz)
     AND (NOT sqlc.arg(has_date_to)::boolean OR d.document_date <= sqlc.arg(date_to)::timestamptz)
+    -- Page count range filter (optional)
+    AND (NOT sqlc.arg(has_pages_min)::boolean OR d.page_count >= sqlc.arg(pages_min)::int)
+    AND (NOT sqlc.arg(has_pages_max)::boolean OR d.page_count <= sqlc.arg(pages_max)::int)
     -- Tag filter (optional - AND logic: must have ALL selected tags)
     AND (NOT sqlc.arg(has_tags)::boolean
         OR d.id IN (
@@ -98,6 +216,34 @@ WHERE
             GROUP BY dt.document_id
             HAVING COUNT(DISTINCT dt.tag_id) = sqlc.arg(tag_count)::int
         ))
+    -- Custom field filters (optional - AND logic: must match ALL).
+    -- Ops are type-specific so values are only cast to their field's type.
+    AND (NOT sqlc.arg(has_fields)::boolean
+        OR d.id IN (
+            SELECT dcf.document_id
+            FROM document_custom_fields dcf
+            INNER JOIN jsonb_to_recordset(sqlc.arg(field_filters)::jsonb)
+                AS f(field_id uuid, op text, val text) ON f.field_id = dcf.field_id
+            WHERE CASE f.op
+                WHEN 'set' THEN true
+                WHEN 'text_eq' THEN lower(dcf.value_text) = lower(f.val)
+                WHEN 'text_contains' THEN strpos(lower(dcf.value_text), lower(f.val)) > 0
+                WHEN 'num_eq' THEN dcf.value_number = f.val::numeric
+                WHEN 'num_gt' THEN dcf.value_number > f.val::numeric
+                WHEN 'num_gte' THEN dcf.value_number >= f.val::numeric
+                WHEN 'num_lt' THEN dcf.value_number < f.val::numeric
+                WHEN 'num_lte' THEN dcf.value_number <= f.val::numeric
+                WHEN 'date_eq' THEN dcf.value_date = f.val::date
+                WHEN 'date_before' THEN dcf.value_date < f.val::date
+                WHEN 'date_after' THEN dcf.value_date > f.val::date
+                WHEN 'date_next' THEN dcf.value_date BETWEEN CURRENT_DATE AND CURRENT_DATE + f.val::int
+                WHEN 'date_last' THEN dcf.value_date BETWEEN CURRENT_DATE - f.val::int AND CURRENT_DATE
+                WHEN 'bool_eq' THEN dcf.value_bool = f.val::boolean
+                WHEN 'link_eq' THEN dcf.value_document_id = f.val::uuid
+                ELSE false END
+            GROUP BY dcf.document_id
+            HAVING COUNT(*) = sqlc.arg(field_count)::int
+        ))
 ORDER BY
     CASE WHEN sqlc.narg(query)::text IS NOT NULL AND sqlc.narg(query)::text != ''
          THEN ts_rank(d.search_vector, websearch_to_tsquery('english', sqlc.narg(query)::text))
@@ -111,11 +257,15 @@ FROM documents d
 LEFT JOIN document_correspondents dc ON dc.document_id = d.id
 LEFT JOIN correspondents c ON c.id = dc.correspondent_id
 WHERE
-    (sqlc.narg(query)::text IS NULL OR sqlc.narg(query)::text = ''
+    d.deleted_at IS NULL
+    AND (sqlc.narg(query)::text IS NULL OR sqlc.narg(query)::text = ''
         OR d.search_vector @@ websearch_to_tsquery('english', sqlc.narg(query)::text))
     AND (NOT sqlc.arg(has_correspondent)::boolean OR c.id = sqlc.arg(correspondent_id)::uuid)
+    AND (NOT sqlc.arg(has_document_type)::boolean OR d.document_type_id = sqlc.arg(document_type_id)::uuid)
     AND (NOT sqlc.arg(has_date_from)::boolean OR d.document_date >= sqlc.arg(date_from)::timestamptz)
     AND (NOT sqlc.arg(has_date_to)::boolean OR d.document_date <= sqlc.arg(date_to)::timestamptz)
+    AND (NOT sqlc.arg(has_pages_min)::boolean OR d.page_count >= sqlc.arg(pages_min)::int)
+    AND (NOT sqlc.arg(has_pages_max)::boolean OR d.page_count <= sqlc.arg(pages_max)::int)
     AND (NOT sqlc.arg(has_tags)::boolean
         OR d.id IN (
             SELECT dt.document_id
@@ -123,4 +273,32 @@ WHERE
             WHERE dt.tag_id = ANY(sqlc.arg(tag_ids)::uuid[])
             GROUP BY dt.document_id
             HAVING COUNT(DISTINCT dt.tag_id) = sqlc.arg(tag_count)::int
+        ))
+    -- Custom field filters (optional - AND logic: must match ALL).
+    -- Ops are type-specific so values are only cast to their field's type.
+    AND (NOT sqlc.arg(has_fields)::boolean
+        OR d.id IN (
+            SELECT dcf.document_id
+            FROM document_custom_fields dcf
+            INNER JOIN jsonb_to_recordset(sqlc.arg(field_filters)::jsonb)
+                AS f(field_id uuid, op text, val text) ON f.field_id = dcf.field_id
+            WHERE CASE f.op
+                WHEN 'set' THEN true
+                WHEN 'text_eq' THEN lower(dcf.value_text) = lower(f.val)
+                WHEN 'text_contains' THEN strpos(lower(dcf.value_text), lower(f.val)) > 0
+                WHEN 'num_eq' THEN dcf.value_number = f.val::numeric
+                WHEN 'num_gt' THEN dcf.value_number > f.val::numeric
+                WHEN 'num_gte' THEN dcf.value_number >= f.val::numeric
+                WHEN 'num_lt' THEN dcf.value_number < f.val::numeric
+                WHEN 'num_lte' THEN dcf.value_number <= f.val::numeric
+                WHEN 'date_eq' THEN dcf.value_date = f.val::date
+                WHEN 'date_before' THEN dcf.value_date < f.val::date
+                WHEN 'date_after' THEN dcf.value_date > f.val::date
+                WHEN 'date_next' THEN dcf.value_date BETWEEN CURRENT_DATE AND CURRENT_DATE + f.val::int
+                WHEN 'date_last' THEN dcf.value_date BETWEEN CURRENT_DATE - f.val::int AND CURRENT_DATE
+                WHEN 'bool_eq' THEN dcf.value_bool = f.val::boolean
+                WHEN 'link_eq' THEN dcf.value_document_id = f.val::uuid
+                ELSE false END
+            GROUP BY dcf.document_id
+            HAVING COUNT(*) = sqlc.arg(field_count)::int
         ));
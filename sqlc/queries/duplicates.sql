@@ -0,0 +1,67 @@
+-- name: UpdateDocumentFingerprint :exec
+UPDATE documents
+SET text_simhash = $2, image_hash = $3, updated_at = NOW()
+WHERE id = $1;
+
+-- name: ListNearDuplicateFingerprints :many
+-- Prefilters on Hamming distance so only likely matches leave the database:
+-- text fingerprints within max_text_distance, or first page fingerprints
+-- within max_image_distance when neither document has text
+SELECT id, text_simhash, image_hash FROM documents
+WHERE id <> @id::uuid
+  AND deleted_at IS NULL
+  AND (
+    (sqlc.narg(text_simhash)::bigint IS NOT NULL AND text_simhash IS NOT NULL
+      AND bit_count((text_simhash # sqlc.narg(text_simhash)::bigint)::bit(64)) <= @max_text_distance::int)
+    OR (sqlc.narg(text_simhash)::bigint IS NULL AND text_simhash IS NULL
+      AND sqlc.narg(image_hash)::bigint IS NOT NULL AND image_hash IS NOT NULL
+      AND bit_count((image_hash # sqlc.narg(image_hash)::bigint)::bit(64)) <= @max_image_distance::int)
+  );
+
+-- name: CreateDuplicateCandidate :exec
+-- Pairs already reviewed in either direction are not raised again
+INSERT INTO duplicate_candidates (document_id, duplicate_of_id, text_distance, image_distance)
+SELECT @document_id::uuid, @duplicate_of_id::uuid, sqlc.narg(text_distance)::int, sqlc.narg(image_distance)::int
+WHERE NOT EXISTS (
+    SELECT 1 FROM duplicate_candidates dc
+    WHERE (dc.document_id = @document_id::uuid AND dc.duplicate_of_id = @duplicate_of_id::uuid)
+       OR (dc.document_id = @duplicate_of_id::uuid AND dc.duplicate_of_id = @document_id::uuid)
+);
+
+-- name: GetDuplicateCandidate :one
+SELECT * FROM duplicate_candidates WHERE id = $1;
+
+-- name: ListPendingDuplicateCandidates :many
+SELECT c.*,
+    d.original_filename AS document_filename,
+    d.created_at AS document_created_at,
+    d.file_size AS document_file_size,
+    o.original_filename AS duplicate_of_filename,
+    o.created_at AS duplicate_of_created_at,
+    o.file_size AS duplicate_of_file_size
+FROM duplicate_candidates c
+JOIN documents d ON c.document_id = d.id
+JOIN documents o ON c.duplicate_of_id = o.id
+WHERE c.status = 'pending'
+  AND d.deleted_at IS NULL
+  AND o.deleted_at IS NULL
+ORDER BY c.created_at DESC;
+
+-- name: CountPendingDuplicateCandidates :one
+SELECT COUNT(*) FROM duplicate_candidates c
+JOIN documents d ON c.document_id = d.id
+JOIN documents o ON c.duplicate_of_id = o.id
+WHERE c.status = 'pending'
+  AND d.deleted_at IS NULL
+  AND o.deleted_at IS NULL;
+
+-- name: ResolveDuplicateCandidate :one
+UPDATE duplicate_candidates
+SET status = $2, resolved_at = NOW()
+WHERE id = $1 AND status = 'pending'
+RETURNING *;
+
+-- name: CopyDocumentTags :exec
+INSERT INTO document_tags (document_id, tag_id)
+SELECT @target_id::uuid, tag_id FROM document_tags WHERE document_id = @source_id::uuid
+ON CONFLICT (document_id, tag_id) DO NOTHING;
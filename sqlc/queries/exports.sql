@@ -0,0 +1,26 @@
+-- name: ListExportableDocuments :many
+-- Processed, non-deleted documents with the metadata used by export path templates
+SELECT d.id, d.original_filename, d.content_type, d.revision, d.edit_version,
+    d.title, d.pdf_title, d.document_date, d.created_at,
+    c.name AS correspondent_name, dty.name AS document_type_name
+FROM documents d
+LEFT JOIN document_correspondents dc ON dc.document_id = d.id
+LEFT JOIN correspondents c ON c.id = dc.correspondent_id
+LEFT JOIN document_types dty ON dty.id = d.document_type_id
+WHERE d.deleted_at IS NULL AND d.processing_status = 'completed'
+ORDER BY d.created_at, d.id;
+
+-- name: ListDocumentExports :many
+SELECT * FROM document_exports;
+
+-- name: UpsertDocumentExport :exec
+INSERT INTO document_exports (document_id, export_path, source_path, source_revision)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (document_id) DO UPDATE
+SET export_path = EXCLUDED.export_path,
+    source_path = EXCLUDED.source_path,
+    source_revision = EXCLUDED.source_revision,
+    exported_at = NOW();
+
+-- name: DeleteDocumentExport :exec
+DELETE FROM document_exports WHERE document_id = $1;
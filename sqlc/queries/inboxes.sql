@@ -1,6 +1,8 @@
 -- name: CreateInbox :one
-INSERT INTO inboxes (path, name, error_path, duplicate_action, enabled)
-VALUES ($1, $2, $3, $4, $5)
+INSERT INTO inboxes (
+    path, name, error_path, duplicate_action, enabled, recursive, folder_tags,
+    default_tags, default_correspondent, default_document_type
+) VALUES ($1, $2, $3, $4, $5, $6, $7, COALESCE(@default_tags::text[], '{}'), $8, $9)
 RETURNING *;
 
 -- name: GetInbox :one
@@ -17,7 +19,15 @@ SELECT * FROM inboxes WHERE enabled = true ORDER BY created_at ASC;
 
 -- name: UpdateInbox :one
 UPDATE inboxes
-SET name = $2, path = $3, error_path = $4, duplicate_action = $5, enabled = $6, updated_at = NOW()
+SET name = $2, path = $3, error_path = $4, duplicate_action = $5, enabled = $6,
+    recursive = $7, folder_tags = $8, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateInboxDefaults :one
+UPDATE inboxes
+SET default_tags = COALESCE(@default_tags::text[], '{}'), default_correspondent = $2,
+    default_document_type = $3, updated_at = NOW()
 WHERE id = $1
 RETURNING *;
 
@@ -0,0 +1,46 @@
+-- name: ListAllDocuments :many
+-- Every document, trashed ones included, for storage integrity checks
+SELECT * FROM documents ORDER BY created_at, id;
+
+-- name: ListAllDocumentRevisions :many
+SELECT * FROM document_revisions ORDER BY document_id, revision;
+
+-- name: CreateIntegrityCheck :one
+INSERT INTO integrity_checks DEFAULT VALUES RETURNING *;
+
+-- name: FinishIntegrityCheck :one
+UPDATE integrity_checks
+SET finished_at = NOW(), documents_checked = $2, files_checked = $3, error = $4
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteOtherIntegrityChecks :exec
+-- Drops every run but the given one, along with their issues
+DELETE FROM integrity_checks WHERE id <> $1;
+
+-- name: GetLatestIntegrityCheck :one
+SELECT * FROM integrity_checks
+WHERE finished_at IS NOT NULL
+ORDER BY started_at DESC
+LIMIT 1;
+
+-- name: CreateIntegrityIssue :exec
+INSERT INTO integrity_issues (check_id, kind, category, storage_key, document_id, detail)
+VALUES ($1, $2, $3, $4, $5, $6);
+
+-- name: ListIntegrityIssues :many
+SELECT i.*, d.original_filename AS document_filename
+FROM integrity_issues i
+LEFT JOIN documents d ON d.id = i.document_id
+WHERE i.check_id = $1
+ORDER BY i.category, i.kind, i.storage_key;
+
+-- name: GetIntegrityIssue :one
+SELECT * FROM integrity_issues WHERE id = $1;
+
+-- name: DeleteIntegrityIssue :exec
+DELETE FROM integrity_issues WHERE id = $1;
+
+-- name: SetDocumentThumbnailGenerated :exec
+UPDATE documents SET thumbnail_generated = $2, updated_at = NOW()
+WHERE id = $1;
@@ -2,8 +2,10 @@
 INSERT INTO network_sources (
     name, protocol, host, share_path, username, password_encrypted,
     enabled, continuous_sync, post_import_action, move_subfolder,
-    duplicate_action, batch_size
-) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+    duplicate_action, batch_size,
+    default_tags, default_correspondent, default_document_type
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12,
+    COALESCE(@default_tags::text[], '{}'), $13, $14)
 RETURNING *;
 
 -- name: GetNetworkSource :one
@@ -29,6 +31,13 @@ UPDATE network_sources SET
 WHERE id = $1
 RETURNING *;
 
+-- name: UpdateNetworkSourceDefaults :one
+UPDATE network_sources SET
+    default_tags = COALESCE(@default_tags::text[], '{}'), default_correspondent = $2,
+    default_document_type = $3, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
 -- name: UpdateNetworkSourceStatus :exec
 UPDATE network_sources SET
     connection_state = $2, consecutive_failures = $3,
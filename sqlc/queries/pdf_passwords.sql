@@ -0,0 +1,15 @@
+-- name: ListPDFPasswords :many
+-- Most recently used first, so the likeliest password is tried first
+SELECT * FROM pdf_passwords
+ORDER BY last_used_at DESC NULLS LAST, created_at DESC;
+
+-- name: CreatePDFPassword :one
+INSERT INTO pdf_passwords (label, password_encrypted)
+VALUES ($1, $2)
+RETURNING *;
+
+-- name: DeletePDFPassword :exec
+DELETE FROM pdf_passwords WHERE id = $1;
+
+-- name: TouchPDFPassword :exec
+UPDATE pdf_passwords SET last_used_at = NOW() WHERE id = $1;
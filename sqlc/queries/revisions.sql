@@ -0,0 +1,10 @@
+-- name: CreateDocumentRevision :one
+INSERT INTO document_revisions (document_id, revision, original_filename, content_hash, file_size, content_type, edit_version)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetDocumentRevision :one
+SELECT * FROM document_revisions WHERE document_id = $1 AND revision = $2;
+
+-- name: ListDocumentRevisions :many
+SELECT * FROM document_revisions WHERE document_id = $1 ORDER BY revision DESC;
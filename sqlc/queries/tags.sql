@@ -1,7 +1,8 @@
 -- name: ListTagsWithCounts :many
-SELECT t.id, t.name, t.color, t.created_at, COUNT(dt.document_id)::int AS document_count
+SELECT t.id, t.name, t.color, t.created_at, COUNT(d.id)::int AS document_count
 FROM tags t
 LEFT JOIN document_tags dt ON t.id = dt.tag_id
+LEFT JOIN documents d ON d.id = dt.document_id AND d.deleted_at IS NULL
 GROUP BY t.id
 ORDER BY t.name;
 
@@ -59,3 +60,8 @@ FROM document_tags dt
 INNER JOIN tags t ON t.id = dt.tag_id
 WHERE dt.document_id = ANY($1::uuid[])
 ORDER BY dt.document_id, t.name;
+
+-- name: GetTagByName :one
+SELECT * FROM tags
+WHERE lower(name) = lower($1)
+LIMIT 1;
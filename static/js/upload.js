@@ -13,6 +13,13 @@
     const progressContainer = document.getElementById('upload-progress');
     const resultsContainer = document.getElementById('upload-results');
     const toastContainer = document.getElementById('toast-container');
+    const combineImages = document.getElementById('combine-images');
+
+    // File extensions accepted for upload (must match document.IsSupportedFilename)
+    const ACCEPTED_EXTENSIONS = ['.pdf', '.jpg', '.jpeg', '.png', '.tif', '.tiff', '.heic', '.heif', '.docx', '.xlsx', '.odt', '.ods', '.rtf', '.eml', '.mbox'];
+
+    // Image extensions that can be combined into one document
+    const IMAGE_EXTENSIONS = ['.jpg', '.jpeg', '.png', '.tif', '.tiff', '.heic', '.heif'];
 
     // Drag counter to handle child element events (prevents overlay flicker)
     let dragCounter = 0;
@@ -169,10 +176,35 @@
      * @returns {Promise<Object>} Upload result
      */
     function uploadFile(file, index) {
+        const formData = new FormData();
+        formData.append('file', file);
+        return sendUpload('/api/upload', formData, file.name, index);
+    }
+
+    /**
+     * Upload images as the pages of one document
+     * @param {File[]} files - Images in page order
+     * @param {number} index - Index for progress tracking
+     * @returns {Promise<Object>} Upload result
+     */
+    function uploadCombined(files, index) {
+        const formData = new FormData();
+        files.forEach(file => formData.append('files', file));
+        formData.append('combine', 'true');
+        return sendUpload('/upload', formData, files[0].name, index);
+    }
+
+    /**
+     * Send an upload form with progress tracking
+     * @param {string} url - Upload endpoint
+     * @param {FormData} formData - Form with the files
+     * @param {string} name - Name shown for the processing tracker
+     * @param {number} index - Index for progress tracking
+     * @returns {Promise<Object>} Upload result
+     */
+    function sendUpload(url, formData, name, index) {
         return new Promise((resolve) => {
             const xhr = new XMLHttpRequest();
-            const formData = new FormData();
-            formData.append('file', file);
 
             // Track upload progress
             xhr.upload.onprogress = (e) => {
@@ -188,7 +220,11 @@
 
                 if (xhr.status >= 200 && xhr.status < 300) {
                     try {
-                        const result = JSON.parse(xhr.responseText);
+                        let result = JSON.parse(xhr.responseText);
+                        // Multi-file uploads answer with a list of results
+                        if (Array.isArray(result)) {
+                            result = result[0] || { success: false, error: 'Invalid response' };
+                        }
                         const success = result.success !== false;
                         const isDuplicate = result.is_duplicate === true;
 
@@ -198,7 +234,7 @@
                             uploadResults.success++;
                             // Add processing tracker for this document
                             if (result.document_id) {
-                                addProcessingTracker(result.document_id, file.name);
+                                addProcessingTracker(result.document_id, result.filename || name);
                             }
                         } else if (isDuplicate) {
                             uploadResults.duplicate++;
@@ -215,7 +251,10 @@
                 } else {
                     let error = 'Upload failed';
                     try {
-                        const result = JSON.parse(xhr.responseText);
+                        let result = JSON.parse(xhr.responseText);
+                        if (Array.isArray(result)) {
+                            result = result[0] || {};
+                        }
                         error = result.error || error;
                     } catch (e) {
                         // Use default error
@@ -239,7 +278,7 @@
             };
 
             // Send the request
-            xhr.open('POST', '/api/upload');
+            xhr.open('POST', url);
             xhr.setRequestHeader('Accept', 'application/json');
             xhr.send(formData);
             activeUploads++;
@@ -277,34 +316,65 @@
         }
     }
 
+    /**
+     * Check whether a file has an extension the server can ingest
+     * @param {File} file - File to check
+     * @returns {boolean}
+     */
+    function isAcceptedFile(file) {
+        const name = file.name.toLowerCase();
+        return file.type === 'application/pdf' || ACCEPTED_EXTENSIONS.some(ext => name.endsWith(ext));
+    }
+
+    /**
+     * Check whether a file is an image
+     * @param {File} file - File to check
+     * @returns {boolean}
+     */
+    function isImageFile(file) {
+        const name = file.name.toLowerCase();
+        return IMAGE_EXTENSIONS.some(ext => name.endsWith(ext));
+    }
+
     /**
      * Filter and upload files
      * @param {FileList|File[]} files - Files to upload
      */
     function uploadFiles(files) {
-        // Filter for PDF files only
-        const pdfFiles = Array.from(files).filter(file => {
-            const isPDF = file.type === 'application/pdf' || file.name.toLowerCase().endsWith('.pdf');
-            if (!isPDF) {
-                showToast(`Skipped "${file.name}" - not a PDF file`, true);
+        // Filter for supported files only
+        const acceptedFiles = Array.from(files).filter(file => {
+            const isAccepted = isAcceptedFile(file);
+            if (!isAccepted) {
+                showToast(`Skipped "${file.name}" - not a supported file type`, true);
             }
-            return isPDF;
+            return isAccepted;
         });
 
-        if (pdfFiles.length === 0) {
+        if (acceptedFiles.length === 0) {
             return;
         }
 
         // Clear previous progress entries
         progressContainer.innerHTML = '';
 
+        if (combineImages && combineImages.checked && acceptedFiles.length > 1) {
+            if (!acceptedFiles.every(isImageFile)) {
+                showToast('Only images can be combined into one document', true);
+                return;
+            }
+            const name = acceptedFiles[0].name.replace(/\.[^.]+$/, '') + '.pdf';
+            createProgressEntry(`${name} (${acceptedFiles.length} pages)`, 0);
+            uploadCombined(acceptedFiles, 0);
+            return;
+        }
+
         // Create progress entries for each file
-        pdfFiles.forEach((file, index) => {
+        acceptedFiles.forEach((file, index) => {
             createProgressEntry(file.name, index);
         });
 
         // Upload all files in parallel
-        pdfFiles.forEach((file, index) => {
+        acceptedFiles.forEach((file, index) => {
             uploadFile(file, index);
         });
     }
@@ -11,6 +11,7 @@ import templruntime "github.com/a-h/templ/runtime"
 import (
 	"github.com/bketelsen/docko/components/button"
 	"github.com/bketelsen/docko/components/card"
+	"github.com/bketelsen/docko/components/label"
 	"github.com/bketelsen/docko/internal/meta"
 	"github.com/bketelsen/docko/templates/layouts"
 )
@@ -48,7 +49,7 @@ func Upload() templ.Component {
 				}()
 			}
 			ctx = templ.InitializeContext(ctx)
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"mb-8\"><h1 class=\"text-2xl font-bold\">Upload Documents</h1><p class=\"text-muted-foreground\">Drag PDF files anywhere on this page, or click to select.</p></div><!-- Upload Area --> ")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<div class=\"mb-8\"><h1 class=\"text-2xl font-bold\">Upload Documents</h1><p class=\"text-muted-foreground\">Drag PDF, image, office or email files anywhere on this page, or click to select. Images, office documents and emails are converted to PDF.</p></div><!-- Upload Area --> ")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -76,7 +77,7 @@ func Upload() templ.Component {
 						}()
 					}
 					ctx = templ.InitializeContext(ctx)
-					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "<div id=\"upload-area\" class=\"border-2 border-dashed border-muted rounded-lg p-12 text-center cursor-pointer hover:border-primary hover:bg-accent/50 transition-colors\"><svg class=\"w-12 h-12 mx-auto mb-4 text-muted-foreground\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M7 16a4 4 0 01-.88-7.903A5 5 0 1115.9 6L16 6a5 5 0 011 9.9M15 13l-3-3m0 0l-3 3m3-3v12\"></path></svg><p class=\"text-lg font-medium mb-2\">Drop PDF files here</p><p class=\"text-sm text-muted-foreground mb-4\">or click to select files</p><input type=\"file\" id=\"file-input\" class=\"hidden\" accept=\".pdf,application/pdf\" multiple>")
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "<div id=\"upload-area\" class=\"border-2 border-dashed border-muted rounded-lg p-12 text-center cursor-pointer hover:border-primary hover:bg-accent/50 transition-colors\"><svg class=\"w-12 h-12 mx-auto mb-4 text-muted-foreground\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M7 16a4 4 0 01-.88-7.903A5 5 0 1115.9 6L16 6a5 5 0 011 9.9M15 13l-3-3m0 0l-3 3m3-3v12\"></path></svg><p class=\"text-lg font-medium mb-2\">Drop PDF, image, office or email files here</p><p class=\"text-sm text-muted-foreground mb-4\">or click to select files</p><input type=\"file\" id=\"file-input\" class=\"hidden\" accept=\".pdf,application/pdf,.jpg,.jpeg,.png,.tif,.tiff,.heic,.heif,image/jpeg,image/png,image/tiff,image/heic,.docx,.xlsx,.odt,.ods,.rtf,.eml,.mbox,message/rfc822\" multiple>")
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
 					}
@@ -105,7 +106,33 @@ func Upload() templ.Component {
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
 					}
-					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, "</div>")
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 4, "</div><div class=\"flex items-center space-x-2 mt-4\"><input type=\"checkbox\" id=\"combine-images\" class=\"h-4 w-4 rounded border-input text-primary focus:ring-primary\">")
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+					templ_7745c5c3_Var6 := templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
+						templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
+						templ_7745c5c3_Buffer, templ_7745c5c3_IsBuffer := templruntime.GetBuffer(templ_7745c5c3_W)
+						if !templ_7745c5c3_IsBuffer {
+							defer func() {
+								templ_7745c5c3_BufErr := templruntime.ReleaseBuffer(templ_7745c5c3_Buffer)
+								if templ_7745c5c3_Err == nil {
+									templ_7745c5c3_Err = templ_7745c5c3_BufErr
+								}
+							}()
+						}
+						ctx = templ.InitializeContext(ctx)
+						templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, "Combine images into one document, a page per image in the order selected")
+						if templ_7745c5c3_Err != nil {
+							return templ_7745c5c3_Err
+						}
+						return nil
+					})
+					templ_7745c5c3_Err = label.Label(label.Props{For: "combine-images"}).Render(templ.WithChildren(ctx, templ_7745c5c3_Var6), templ_7745c5c3_Buffer)
+					if templ_7745c5c3_Err != nil {
+						return templ_7745c5c3_Err
+					}
+					templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 6, "</div>")
 					if templ_7745c5c3_Err != nil {
 						return templ_7745c5c3_Err
 					}
@@ -121,13 +148,13 @@ func Upload() templ.Component {
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 5, " <!-- Upload Progress Container --> <div id=\"upload-progress\" class=\"space-y-3 mt-6\"></div><!-- Processing Status Container (for SSE updates after upload) --> <div id=\"processing-status\" class=\"space-y-3 mt-6\" hx-ext=\"sse\" sse-connect=\"/api/processing/status\" sse-close=\"close\"><!-- Document status updates will be swapped here via SSE --></div><!-- Upload Results Container (filled by HTMX responses) --> <div id=\"upload-results\" class=\"space-y-3\"></div><!-- Full-page Drop Overlay (hidden by default) --> <div id=\"drop-overlay\" class=\"hidden fixed inset-0 z-50 bg-black/50 backdrop-blur-sm flex items-center justify-center\"><div class=\"border-4 border-dashed border-white rounded-xl p-16 text-center\"><svg class=\"w-16 h-16 mx-auto mb-4 text-white\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M7 16a4 4 0 01-.88-7.903A5 5 0 1115.9 6L16 6a5 5 0 011 9.9M15 13l-3-3m0 0l-3 3m3-3v12\"></path></svg><p class=\"text-2xl font-bold text-white\">Drop PDF files to upload</p></div></div><!-- Toast Container for Notifications --> <div id=\"toast-container\" class=\"fixed top-4 right-4 z-50 space-y-2\"></div><script src=\"/static/js/upload.js\"></script>")
+			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 7, " <!-- Upload Progress Container --> <div id=\"upload-progress\" class=\"space-y-3 mt-6\"></div><!-- Processing Status Container (for SSE updates after upload) --> <div id=\"processing-status\" class=\"space-y-3 mt-6\" hx-ext=\"sse\" sse-connect=\"/api/processing/status\" sse-close=\"close\"><!-- Document status updates will be swapped here via SSE --></div><!-- Upload Results Container (filled by HTMX responses) --> <div id=\"upload-results\" class=\"space-y-3\"></div><!-- Full-page Drop Overlay (hidden by default) --> <div id=\"drop-overlay\" class=\"hidden fixed inset-0 z-50 bg-black/50 backdrop-blur-sm flex items-center justify-center\"><div class=\"border-4 border-dashed border-white rounded-xl p-16 text-center\"><svg class=\"w-16 h-16 mx-auto mb-4 text-white\" fill=\"none\" stroke=\"currentColor\" viewBox=\"0 0 24 24\"><path stroke-linecap=\"round\" stroke-linejoin=\"round\" stroke-width=\"2\" d=\"M7 16a4 4 0 01-.88-7.903A5 5 0 1115.9 6L16 6a5 5 0 011 9.9M15 13l-3-3m0 0l-3 3m3-3v12\"></path></svg><p class=\"text-2xl font-bold text-white\">Drop files to upload</p></div></div><!-- Toast Container for Notifications --> <div id=\"toast-container\" class=\"fixed top-4 right-4 z-50 space-y-2\"></div><script src=\"/static/js/upload.js\"></script>")
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
 			return nil
 		})
-		templ_7745c5c3_Err = layouts.Admin(meta.New("Upload Documents", "Upload PDF documents to your collection")).Render(templ.WithChildren(ctx, templ_7745c5c3_Var2), templ_7745c5c3_Buffer)
+		templ_7745c5c3_Err = layouts.Admin(meta.New("Upload Documents", "Upload PDFs, images, office documents and emails to your collection")).Render(templ.WithChildren(ctx, templ_7745c5c3_Var2), templ_7745c5c3_Buffer)
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}